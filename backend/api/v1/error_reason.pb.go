@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "github.com/go-kratos/kratos/v2/errors"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -24,15 +25,51 @@ type ErrorReason int32
 
 const (
 	ErrorReason_OPERATION_TYPE_UNKNOWN ErrorReason = 0
+	// Etag из запроса устарел: стрим изменили после того, как клиент его прочитал
+	ErrorReason_STREAM_VERSION_MISMATCH ErrorReason = 1
+	// Стрима с таким id нет
+	ErrorReason_STREAM_NOT_FOUND ErrorReason = 2
+	// Кадра с такой sequence нет (или у стрима ещё нет кадров/постера)
+	ErrorReason_FRAME_NOT_FOUND ErrorReason = 3
+	// Некорректный запрос: id, параметры, update_mask, page_token, пустой кадр
+	ErrorReason_INVALID_ARGUMENT ErrorReason = 4
+	// Кадр больше лимита ChunkStore
+	ErrorReason_FRAME_TOO_LARGE ErrorReason = 5
+	// Кадр не картинка
+	ErrorReason_UNSUPPORTED_MEDIA_TYPE ErrorReason = 6
+	// Кадр не удалось декодировать (превью)
+	ErrorReason_FRAME_UNDECODABLE ErrorReason = 7
+	// Кэш кадров перегружен: повторить позже
+	ErrorReason_CACHE_PRESSURE ErrorReason = 8
+	// БД недоступна или не ответила вовремя
+	ErrorReason_DB_UNAVAILABLE ErrorReason = 9
 )
 
 // Enum value maps for ErrorReason.
 var (
 	ErrorReason_name = map[int32]string{
 		0: "OPERATION_TYPE_UNKNOWN",
+		1: "STREAM_VERSION_MISMATCH",
+		2: "STREAM_NOT_FOUND",
+		3: "FRAME_NOT_FOUND",
+		4: "INVALID_ARGUMENT",
+		5: "FRAME_TOO_LARGE",
+		6: "UNSUPPORTED_MEDIA_TYPE",
+		7: "FRAME_UNDECODABLE",
+		8: "CACHE_PRESSURE",
+		9: "DB_UNAVAILABLE",
 	}
 	ErrorReason_value = map[string]int32{
-		"OPERATION_TYPE_UNKNOWN": 0,
+		"OPERATION_TYPE_UNKNOWN":  0,
+		"STREAM_VERSION_MISMATCH": 1,
+		"STREAM_NOT_FOUND":        2,
+		"FRAME_NOT_FOUND":         3,
+		"INVALID_ARGUMENT":        4,
+		"FRAME_TOO_LARGE":         5,
+		"UNSUPPORTED_MEDIA_TYPE":  6,
+		"FRAME_UNDECODABLE":       7,
+		"CACHE_PRESSURE":          8,
+		"DB_UNAVAILABLE":          9,
 	}
 )
 
@@ -68,14 +105,31 @@ var File_v1_error_reason_proto protoreflect.FileDescriptor
 var file_v1_error_reason_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x76, 0x31, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f,
 	0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e,
-	0x76, 0x31, 0x2a, 0x29, 0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x61, 0x73, 0x6f,
-	0x6e, 0x12, 0x1a, 0x0a, 0x16, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54,
-	0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x42, 0x49, 0x0a,
-	0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x50, 0x01, 0x5a, 0x2c, 0x67, 0x69,
-	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x6d, 0x61, 0x70, 0x63, 0x61, 0x72, 0x64, 0x2e, 0x70, 0x72, 0x6f,
-	0x2f, 0x74, 0x68, 0x65, 0x2d, 0x6d, 0x61, 0x70, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x61, 0x6c,
-	0x61, 0x6e, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x31, 0xa2, 0x02, 0x0b, 0x41, 0x50, 0x49,
-	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x76, 0x31, 0x1a, 0x13, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2a, 0xb3, 0x02, 0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x16, 0x4f, 0x50, 0x45, 0x52, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57,
+	0x4e, 0x10, 0x00, 0x12, 0x21, 0x0a, 0x17, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x56, 0x45,
+	0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x01,
+	0x1a, 0x04, 0xa8, 0x45, 0x9c, 0x03, 0x12, 0x1a, 0x0a, 0x10, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d,
+	0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x02, 0x1a, 0x04, 0xa8, 0x45,
+	0x94, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x46, 0x52, 0x41, 0x4d, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f,
+	0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x03, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x1a, 0x0a,
+	0x10, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x41, 0x52, 0x47, 0x55, 0x4d, 0x45, 0x4e,
+	0x54, 0x10, 0x04, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x46, 0x52, 0x41,
+	0x4d, 0x45, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x41, 0x52, 0x47, 0x45, 0x10, 0x05, 0x1a, 0x04,
+	0xa8, 0x45, 0x9d, 0x03, 0x12, 0x20, 0x0a, 0x16, 0x55, 0x4e, 0x53, 0x55, 0x50, 0x50, 0x4f, 0x52,
+	0x54, 0x45, 0x44, 0x5f, 0x4d, 0x45, 0x44, 0x49, 0x41, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x10, 0x06,
+	0x1a, 0x04, 0xa8, 0x45, 0x9f, 0x03, 0x12, 0x1b, 0x0a, 0x11, 0x46, 0x52, 0x41, 0x4d, 0x45, 0x5f,
+	0x55, 0x4e, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x07, 0x1a, 0x04, 0xa8,
+	0x45, 0xa6, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x43, 0x41, 0x43, 0x48, 0x45, 0x5f, 0x50, 0x52, 0x45,
+	0x53, 0x53, 0x55, 0x52, 0x45, 0x10, 0x08, 0x1a, 0x04, 0xa8, 0x45, 0xf7, 0x03, 0x12, 0x18, 0x0a,
+	0x0e, 0x44, 0x42, 0x5f, 0x55, 0x4e, 0x41, 0x56, 0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x10,
+	0x09, 0x1a, 0x04, 0xa8, 0x45, 0xf7, 0x03, 0x1a, 0x04, 0xa0, 0x45, 0xf4, 0x03, 0x42, 0x34, 0x0a,
+	0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x50, 0x01, 0x5a, 0x17, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x3b, 0x76, 0x31, 0xa2, 0x02, 0x0b, 0x41, 0x50, 0x49, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
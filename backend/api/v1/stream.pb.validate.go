@@ -127,6 +127,10 @@ func (m *Stream) validate(all bool) error {
 		}
 	}
 
+	// no validation rules for ThumbnailUrl
+
+	// no validation rules for Etag
+
 	if len(errors) > 0 {
 		return StreamMultiError(errors)
 	}
@@ -226,6 +230,50 @@ func (m *ListStreamsRequest) validate(all bool) error {
 
 	var errors []error
 
+	if val := m.GetPageSize(); val < 0 || val > 200 {
+		err := ListStreamsRequestValidationError{
+			field:  "PageSize",
+			reason: "value must be inside range [0, 200]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetPageToken()) > 512 {
+		err := ListStreamsRequestValidationError{
+			field:  "PageToken",
+			reason: "value length must be at most 512 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetSearch()) > 200 {
+		err := ListStreamsRequestValidationError{
+			field:  "Search",
+			reason: "value length must be at most 200 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := _ListStreamsRequest_OrderBy_InLookup[m.GetOrderBy()]; !ok {
+		err := ListStreamsRequestValidationError{
+			field:  "OrderBy",
+			reason: "value must be in list [ created_at created_at asc created_at desc]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
 	if len(errors) > 0 {
 		return ListStreamsRequestMultiError(errors)
 	}
@@ -306,6 +354,13 @@ var _ interface {
 	ErrorName() string
 } = ListStreamsRequestValidationError{}
 
+var _ListStreamsRequest_OrderBy_InLookup = map[string]struct{}{
+	"":                {},
+	"created_at":      {},
+	"created_at asc":  {},
+	"created_at desc": {},
+}
+
 // Validate checks the field values on ListStreamsResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
@@ -362,6 +417,8 @@ func (m *ListStreamsResponse) validate(all bool) error {
 
 	}
 
+	// no validation rules for NextPageToken
+
 	if len(errors) > 0 {
 		return ListStreamsResponseMultiError(errors)
 	}
@@ -693,33 +750,32 @@ var _ interface {
 	ErrorName() string
 } = GetStreamResponseValidationError{}
 
-// Validate checks the field values on UpdateStreamRequest with the rules
+// Validate checks the field values on CreateStreamRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *UpdateStreamRequest) Validate() error {
+func (m *CreateStreamRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on UpdateStreamRequest with the rules
+// ValidateAll checks the field values on CreateStreamRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// UpdateStreamRequestMultiError, or nil if none found.
-func (m *UpdateStreamRequest) ValidateAll() error {
+// CreateStreamRequestMultiError, or nil if none found.
+func (m *CreateStreamRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *UpdateStreamRequest) validate(all bool) error {
+func (m *CreateStreamRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	if err := m._validateUuid(m.GetId()); err != nil {
-		err = UpdateStreamRequestValidationError{
-			field:  "Id",
-			reason: "value must be a valid UUID",
-			cause:  err,
+	if l := utf8.RuneCountInString(m.GetTitle()); l < 1 || l > 200 {
+		err := CreateStreamRequestValidationError{
+			field:  "Title",
+			reason: "value length must be between 1 and 200 runes, inclusive",
 		}
 		if !all {
 			return err
@@ -727,34 +783,42 @@ func (m *UpdateStreamRequest) validate(all bool) error {
 		errors = append(errors, err)
 	}
 
-	// no validation rules for Title
-
-	// no validation rules for Description
-
-	// no validation rules for FrameIntervalMs
-
-	if len(errors) > 0 {
-		return UpdateStreamRequestMultiError(errors)
+	if utf8.RuneCountInString(m.GetDescription()) > 2000 {
+		err := CreateStreamRequestValidationError{
+			field:  "Description",
+			reason: "value length must be at most 2000 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
 	}
 
-	return nil
-}
+	if val := m.GetFrameIntervalMs(); val <= 0 || val > 60000 {
+		err := CreateStreamRequestValidationError{
+			field:  "FrameIntervalMs",
+			reason: "value must be inside range (0, 60000]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
 
-func (m *UpdateStreamRequest) _validateUuid(uuid string) error {
-	if matched := _stream_uuidPattern.MatchString(uuid); !matched {
-		return errors.New("invalid uuid format")
+	if len(errors) > 0 {
+		return CreateStreamRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// UpdateStreamRequestMultiError is an error wrapping multiple validation
-// errors returned by UpdateStreamRequest.ValidateAll() if the designated
+// CreateStreamRequestMultiError is an error wrapping multiple validation
+// errors returned by CreateStreamRequest.ValidateAll() if the designated
 // constraints aren't met.
-type UpdateStreamRequestMultiError []error
+type CreateStreamRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m UpdateStreamRequestMultiError) Error() string {
+func (m CreateStreamRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -763,11 +827,11 @@ func (m UpdateStreamRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m UpdateStreamRequestMultiError) AllErrors() []error { return m }
+func (m CreateStreamRequestMultiError) AllErrors() []error { return m }
 
-// UpdateStreamRequestValidationError is the validation error returned by
-// UpdateStreamRequest.Validate if the designated constraints aren't met.
-type UpdateStreamRequestValidationError struct {
+// CreateStreamRequestValidationError is the validation error returned by
+// CreateStreamRequest.Validate if the designated constraints aren't met.
+type CreateStreamRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -775,24 +839,24 @@ type UpdateStreamRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e UpdateStreamRequestValidationError) Field() string { return e.field }
+func (e CreateStreamRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e UpdateStreamRequestValidationError) Reason() string { return e.reason }
+func (e CreateStreamRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e UpdateStreamRequestValidationError) Cause() error { return e.cause }
+func (e CreateStreamRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e UpdateStreamRequestValidationError) Key() bool { return e.key }
+func (e CreateStreamRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e UpdateStreamRequestValidationError) ErrorName() string {
-	return "UpdateStreamRequestValidationError"
+func (e CreateStreamRequestValidationError) ErrorName() string {
+	return "CreateStreamRequestValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e UpdateStreamRequestValidationError) Error() string {
+func (e CreateStreamRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -804,14 +868,14 @@ func (e UpdateStreamRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sUpdateStreamRequest.%s: %s%s",
+		"invalid %sCreateStreamRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = UpdateStreamRequestValidationError{}
+var _ error = CreateStreamRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -819,24 +883,24 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = UpdateStreamRequestValidationError{}
+} = CreateStreamRequestValidationError{}
 
-// Validate checks the field values on UpdateStreamResponse with the rules
+// Validate checks the field values on CreateStreamResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *UpdateStreamResponse) Validate() error {
+func (m *CreateStreamResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on UpdateStreamResponse with the rules
+// ValidateAll checks the field values on CreateStreamResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// UpdateStreamResponseMultiError, or nil if none found.
-func (m *UpdateStreamResponse) ValidateAll() error {
+// CreateStreamResponseMultiError, or nil if none found.
+func (m *CreateStreamResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *UpdateStreamResponse) validate(all bool) error {
+func (m *CreateStreamResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
@@ -847,7 +911,7 @@ func (m *UpdateStreamResponse) validate(all bool) error {
 		switch v := interface{}(m.GetStream()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, UpdateStreamResponseValidationError{
+				errors = append(errors, CreateStreamResponseValidationError{
 					field:  "Stream",
 					reason: "embedded message failed validation",
 					cause:  err,
@@ -855,7 +919,7 @@ func (m *UpdateStreamResponse) validate(all bool) error {
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, UpdateStreamResponseValidationError{
+				errors = append(errors, CreateStreamResponseValidationError{
 					field:  "Stream",
 					reason: "embedded message failed validation",
 					cause:  err,
@@ -864,7 +928,7 @@ func (m *UpdateStreamResponse) validate(all bool) error {
 		}
 	} else if v, ok := interface{}(m.GetStream()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return UpdateStreamResponseValidationError{
+			return CreateStreamResponseValidationError{
 				field:  "Stream",
 				reason: "embedded message failed validation",
 				cause:  err,
@@ -873,19 +937,19 @@ func (m *UpdateStreamResponse) validate(all bool) error {
 	}
 
 	if len(errors) > 0 {
-		return UpdateStreamResponseMultiError(errors)
+		return CreateStreamResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// UpdateStreamResponseMultiError is an error wrapping multiple validation
-// errors returned by UpdateStreamResponse.ValidateAll() if the designated
+// CreateStreamResponseMultiError is an error wrapping multiple validation
+// errors returned by CreateStreamResponse.ValidateAll() if the designated
 // constraints aren't met.
-type UpdateStreamResponseMultiError []error
+type CreateStreamResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m UpdateStreamResponseMultiError) Error() string {
+func (m CreateStreamResponseMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -894,11 +958,11 @@ func (m UpdateStreamResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m UpdateStreamResponseMultiError) AllErrors() []error { return m }
+func (m CreateStreamResponseMultiError) AllErrors() []error { return m }
 
-// UpdateStreamResponseValidationError is the validation error returned by
-// UpdateStreamResponse.Validate if the designated constraints aren't met.
-type UpdateStreamResponseValidationError struct {
+// CreateStreamResponseValidationError is the validation error returned by
+// CreateStreamResponse.Validate if the designated constraints aren't met.
+type CreateStreamResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -906,24 +970,24 @@ type UpdateStreamResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e UpdateStreamResponseValidationError) Field() string { return e.field }
+func (e CreateStreamResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e UpdateStreamResponseValidationError) Reason() string { return e.reason }
+func (e CreateStreamResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e UpdateStreamResponseValidationError) Cause() error { return e.cause }
+func (e CreateStreamResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e UpdateStreamResponseValidationError) Key() bool { return e.key }
+func (e CreateStreamResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e UpdateStreamResponseValidationError) ErrorName() string {
-	return "UpdateStreamResponseValidationError"
+func (e CreateStreamResponseValidationError) ErrorName() string {
+	return "CreateStreamResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e UpdateStreamResponseValidationError) Error() string {
+func (e CreateStreamResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -935,14 +999,14 @@ func (e UpdateStreamResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sUpdateStreamResponse.%s: %s%s",
+		"invalid %sCreateStreamResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = UpdateStreamResponseValidationError{}
+var _ error = CreateStreamResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -950,4 +1014,1091 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = UpdateStreamResponseValidationError{}
+} = CreateStreamResponseValidationError{}
+
+// Validate checks the field values on UpdateStreamRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateStreamRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateStreamRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateStreamRequestMultiError, or nil if none found.
+func (m *UpdateStreamRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateStreamRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = UpdateStreamRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetTitle()) > 200 {
+		err := UpdateStreamRequestValidationError{
+			field:  "Title",
+			reason: "value length must be at most 200 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetDescription()) > 2000 {
+		err := UpdateStreamRequestValidationError{
+			field:  "Description",
+			reason: "value length must be at most 2000 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetFrameIntervalMs() != 0 {
+
+		if val := m.GetFrameIntervalMs(); val <= 0 || val > 60000 {
+			err := UpdateStreamRequestValidationError{
+				field:  "FrameIntervalMs",
+				reason: "value must be inside range (0, 60000]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetUpdateMask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpdateStreamRequestValidationError{
+					field:  "UpdateMask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpdateStreamRequestValidationError{
+					field:  "UpdateMask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUpdateMask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpdateStreamRequestValidationError{
+				field:  "UpdateMask",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetEtag()) > 64 {
+		err := UpdateStreamRequestValidationError{
+			field:  "Etag",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return UpdateStreamRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+func (m *UpdateStreamRequest) _validateUuid(uuid string) error {
+	if matched := _stream_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// UpdateStreamRequestMultiError is an error wrapping multiple validation
+// errors returned by UpdateStreamRequest.ValidateAll() if the designated
+// constraints aren't met.
+type UpdateStreamRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateStreamRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateStreamRequestMultiError) AllErrors() []error { return m }
+
+// UpdateStreamRequestValidationError is the validation error returned by
+// UpdateStreamRequest.Validate if the designated constraints aren't met.
+type UpdateStreamRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateStreamRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateStreamRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateStreamRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateStreamRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateStreamRequestValidationError) ErrorName() string {
+	return "UpdateStreamRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateStreamRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateStreamRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateStreamRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateStreamRequestValidationError{}
+
+// Validate checks the field values on UpdateStreamResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateStreamResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateStreamResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateStreamResponseMultiError, or nil if none found.
+func (m *UpdateStreamResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateStreamResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetStream()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpdateStreamResponseValidationError{
+					field:  "Stream",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpdateStreamResponseValidationError{
+					field:  "Stream",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStream()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpdateStreamResponseValidationError{
+				field:  "Stream",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return UpdateStreamResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpdateStreamResponseMultiError is an error wrapping multiple validation
+// errors returned by UpdateStreamResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UpdateStreamResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateStreamResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateStreamResponseMultiError) AllErrors() []error { return m }
+
+// UpdateStreamResponseValidationError is the validation error returned by
+// UpdateStreamResponse.Validate if the designated constraints aren't met.
+type UpdateStreamResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateStreamResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateStreamResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateStreamResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateStreamResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateStreamResponseValidationError) ErrorName() string {
+	return "UpdateStreamResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateStreamResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateStreamResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateStreamResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateStreamResponseValidationError{}
+
+// Validate checks the field values on DeleteStreamRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteStreamRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteStreamRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteStreamRequestMultiError, or nil if none found.
+func (m *DeleteStreamRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteStreamRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = DeleteStreamRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DeleteStreamRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+func (m *DeleteStreamRequest) _validateUuid(uuid string) error {
+	if matched := _stream_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// DeleteStreamRequestMultiError is an error wrapping multiple validation
+// errors returned by DeleteStreamRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DeleteStreamRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteStreamRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteStreamRequestMultiError) AllErrors() []error { return m }
+
+// DeleteStreamRequestValidationError is the validation error returned by
+// DeleteStreamRequest.Validate if the designated constraints aren't met.
+type DeleteStreamRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteStreamRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteStreamRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteStreamRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteStreamRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteStreamRequestValidationError) ErrorName() string {
+	return "DeleteStreamRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteStreamRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteStreamRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteStreamRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteStreamRequestValidationError{}
+
+// Validate checks the field values on DeleteStreamResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteStreamResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteStreamResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteStreamResponseMultiError, or nil if none found.
+func (m *DeleteStreamResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteStreamResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return DeleteStreamResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteStreamResponseMultiError is an error wrapping multiple validation
+// errors returned by DeleteStreamResponse.ValidateAll() if the designated
+// constraints aren't met.
+type DeleteStreamResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteStreamResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteStreamResponseMultiError) AllErrors() []error { return m }
+
+// DeleteStreamResponseValidationError is the validation error returned by
+// DeleteStreamResponse.Validate if the designated constraints aren't met.
+type DeleteStreamResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteStreamResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteStreamResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteStreamResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteStreamResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteStreamResponseValidationError) ErrorName() string {
+	return "DeleteStreamResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteStreamResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteStreamResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteStreamResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteStreamResponseValidationError{}
+
+// Validate checks the field values on IngestFramesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *IngestFramesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on IngestFramesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// IngestFramesRequestMultiError, or nil if none found.
+func (m *IngestFramesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *IngestFramesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetStreamId() != "" {
+
+		if err := m._validateUuid(m.GetStreamId()); err != nil {
+			err = IngestFramesRequestValidationError{
+				field:  "StreamId",
+				reason: "value must be a valid UUID",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if l := len(m.GetPayload()); l < 1 || l > 4194304 {
+		err := IngestFramesRequestValidationError{
+			field:  "Payload",
+			reason: "value length must be between 1 and 4194304 bytes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !strings.HasPrefix(m.GetMimeType(), "image/") {
+		err := IngestFramesRequestValidationError{
+			field:  "MimeType",
+			reason: "value does not have prefix \"image/\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return IngestFramesRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+func (m *IngestFramesRequest) _validateUuid(uuid string) error {
+	if matched := _stream_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// IngestFramesRequestMultiError is an error wrapping multiple validation
+// errors returned by IngestFramesRequest.ValidateAll() if the designated
+// constraints aren't met.
+type IngestFramesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m IngestFramesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m IngestFramesRequestMultiError) AllErrors() []error { return m }
+
+// IngestFramesRequestValidationError is the validation error returned by
+// IngestFramesRequest.Validate if the designated constraints aren't met.
+type IngestFramesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IngestFramesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IngestFramesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IngestFramesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IngestFramesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IngestFramesRequestValidationError) ErrorName() string {
+	return "IngestFramesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e IngestFramesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIngestFramesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IngestFramesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IngestFramesRequestValidationError{}
+
+// Validate checks the field values on IngestFramesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *IngestFramesResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on IngestFramesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// IngestFramesResponseMultiError, or nil if none found.
+func (m *IngestFramesResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *IngestFramesResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for StreamId
+
+	// no validation rules for FirstSeq
+
+	// no validation rules for LastSeq
+
+	// no validation rules for Count
+
+	if len(errors) > 0 {
+		return IngestFramesResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// IngestFramesResponseMultiError is an error wrapping multiple validation
+// errors returned by IngestFramesResponse.ValidateAll() if the designated
+// constraints aren't met.
+type IngestFramesResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m IngestFramesResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m IngestFramesResponseMultiError) AllErrors() []error { return m }
+
+// IngestFramesResponseValidationError is the validation error returned by
+// IngestFramesResponse.Validate if the designated constraints aren't met.
+type IngestFramesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IngestFramesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IngestFramesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IngestFramesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IngestFramesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IngestFramesResponseValidationError) ErrorName() string {
+	return "IngestFramesResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e IngestFramesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIngestFramesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IngestFramesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IngestFramesResponseValidationError{}
+
+// Validate checks the field values on StreamFramesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *StreamFramesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on StreamFramesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// StreamFramesRequestMultiError, or nil if none found.
+func (m *StreamFramesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *StreamFramesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetStreamId()); err != nil {
+		err = StreamFramesRequestValidationError{
+			field:  "StreamId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if val := m.GetRate(); val < 0 || val > 8 {
+		err := StreamFramesRequestValidationError{
+			field:  "Rate",
+			reason: "value must be inside range [0, 8]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for AsFastAsPossible
+
+	// no validation rules for Live
+
+	if m.FromSeq != nil {
+
+		if m.GetFromSeq() < 0 {
+			err := StreamFramesRequestValidationError{
+				field:  "FromSeq",
+				reason: "value must be greater than or equal to 0",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if m.FromMs != nil {
+
+		if m.GetFromMs() < 0 {
+			err := StreamFramesRequestValidationError{
+				field:  "FromMs",
+				reason: "value must be greater than or equal to 0",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return StreamFramesRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+func (m *StreamFramesRequest) _validateUuid(uuid string) error {
+	if matched := _stream_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// StreamFramesRequestMultiError is an error wrapping multiple validation
+// errors returned by StreamFramesRequest.ValidateAll() if the designated
+// constraints aren't met.
+type StreamFramesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m StreamFramesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m StreamFramesRequestMultiError) AllErrors() []error { return m }
+
+// StreamFramesRequestValidationError is the validation error returned by
+// StreamFramesRequest.Validate if the designated constraints aren't met.
+type StreamFramesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e StreamFramesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e StreamFramesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e StreamFramesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e StreamFramesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e StreamFramesRequestValidationError) ErrorName() string {
+	return "StreamFramesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e StreamFramesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sStreamFramesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = StreamFramesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = StreamFramesRequestValidationError{}
+
+// Validate checks the field values on Frame with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Frame) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Frame with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in FrameMultiError, or nil if none found.
+func (m *Frame) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Frame) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Seq
+
+	// no validation rules for Mime
+
+	// no validation rules for Payload
+
+	// no validation rules for TimestampMs
+
+	// no validation rules for Skipped
+
+	if len(errors) > 0 {
+		return FrameMultiError(errors)
+	}
+
+	return nil
+}
+
+// FrameMultiError is an error wrapping multiple validation errors returned by
+// Frame.ValidateAll() if the designated constraints aren't met.
+type FrameMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m FrameMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m FrameMultiError) AllErrors() []error { return m }
+
+// FrameValidationError is the validation error returned by Frame.Validate if
+// the designated constraints aren't met.
+type FrameValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e FrameValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e FrameValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e FrameValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e FrameValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e FrameValidationError) ErrorName() string { return "FrameValidationError" }
+
+// Error satisfies the builtin error interface
+func (e FrameValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sFrame.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = FrameValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = FrameValidationError{}
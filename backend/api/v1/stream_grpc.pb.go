@@ -21,7 +21,11 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	StreamService_ListStreams_FullMethodName  = "/stream.v1.StreamService/ListStreams"
 	StreamService_GetStream_FullMethodName    = "/stream.v1.StreamService/GetStream"
+	StreamService_CreateStream_FullMethodName = "/stream.v1.StreamService/CreateStream"
 	StreamService_UpdateStream_FullMethodName = "/stream.v1.StreamService/UpdateStream"
+	StreamService_DeleteStream_FullMethodName = "/stream.v1.StreamService/DeleteStream"
+	StreamService_IngestFrames_FullMethodName = "/stream.v1.StreamService/IngestFrames"
+	StreamService_StreamFrames_FullMethodName = "/stream.v1.StreamService/StreamFrames"
 )
 
 // StreamServiceClient is the client API for StreamService service.
@@ -30,7 +34,16 @@ const (
 type StreamServiceClient interface {
 	ListStreams(ctx context.Context, in *ListStreamsRequest, opts ...grpc.CallOption) (*ListStreamsResponse, error)
 	GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (*GetStreamResponse, error)
+	CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...grpc.CallOption) (*CreateStreamResponse, error)
 	UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*UpdateStreamResponse, error)
+	DeleteStream(ctx context.Context, in *DeleteStreamRequest, opts ...grpc.CallOption) (*DeleteStreamResponse, error)
+	// IngestFrames дописывает JPEG-кадры в конец стрима (sequence назначает сервер)
+	// stream_id обязателен в первом сообщении, в остальных его можно не передавать
+	// HTTP-аналог — multipart POST /v1/streams/{id}/frames
+	IngestFrames(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestFramesRequest, IngestFramesResponse], error)
+	// StreamFrames отдаёт кадры стрима в темпе воспроизведения (как ws/mjpeg) или, с as_fast_as_possible, подряд без пауз и скипов
+	// Кадры берутся из того же кэша чанков, что и у ws-зрителей. HTTP-аналоги — /v1/streams/{id}/ws и /v1/streams/{id}/mjpeg
+	StreamFrames(ctx context.Context, in *StreamFramesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Frame], error)
 }
 
 type streamServiceClient struct {
@@ -61,6 +74,16 @@ func (c *streamServiceClient) GetStream(ctx context.Context, in *GetStreamReques
 	return out, nil
 }
 
+func (c *streamServiceClient) CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...grpc.CallOption) (*CreateStreamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateStreamResponse)
+	err := c.cc.Invoke(ctx, StreamService_CreateStream_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *streamServiceClient) UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*UpdateStreamResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateStreamResponse)
@@ -71,13 +94,64 @@ func (c *streamServiceClient) UpdateStream(ctx context.Context, in *UpdateStream
 	return out, nil
 }
 
+func (c *streamServiceClient) DeleteStream(ctx context.Context, in *DeleteStreamRequest, opts ...grpc.CallOption) (*DeleteStreamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteStreamResponse)
+	err := c.cc.Invoke(ctx, StreamService_DeleteStream_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) IngestFrames(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestFramesRequest, IngestFramesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StreamService_ServiceDesc.Streams[0], StreamService_IngestFrames_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IngestFramesRequest, IngestFramesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StreamService_IngestFramesClient = grpc.ClientStreamingClient[IngestFramesRequest, IngestFramesResponse]
+
+func (c *streamServiceClient) StreamFrames(ctx context.Context, in *StreamFramesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Frame], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StreamService_ServiceDesc.Streams[1], StreamService_StreamFrames_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamFramesRequest, Frame]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StreamService_StreamFramesClient = grpc.ServerStreamingClient[Frame]
+
 // StreamServiceServer is the server API for StreamService service.
 // All implementations must embed UnimplementedStreamServiceServer
 // for forward compatibility.
 type StreamServiceServer interface {
 	ListStreams(context.Context, *ListStreamsRequest) (*ListStreamsResponse, error)
 	GetStream(context.Context, *GetStreamRequest) (*GetStreamResponse, error)
+	CreateStream(context.Context, *CreateStreamRequest) (*CreateStreamResponse, error)
 	UpdateStream(context.Context, *UpdateStreamRequest) (*UpdateStreamResponse, error)
+	DeleteStream(context.Context, *DeleteStreamRequest) (*DeleteStreamResponse, error)
+	// IngestFrames дописывает JPEG-кадры в конец стрима (sequence назначает сервер)
+	// stream_id обязателен в первом сообщении, в остальных его можно не передавать
+	// HTTP-аналог — multipart POST /v1/streams/{id}/frames
+	IngestFrames(grpc.ClientStreamingServer[IngestFramesRequest, IngestFramesResponse]) error
+	// StreamFrames отдаёт кадры стрима в темпе воспроизведения (как ws/mjpeg) или, с as_fast_as_possible, подряд без пауз и скипов
+	// Кадры берутся из того же кэша чанков, что и у ws-зрителей. HTTP-аналоги — /v1/streams/{id}/ws и /v1/streams/{id}/mjpeg
+	StreamFrames(*StreamFramesRequest, grpc.ServerStreamingServer[Frame]) error
 	mustEmbedUnimplementedStreamServiceServer()
 }
 
@@ -94,9 +168,21 @@ func (UnimplementedStreamServiceServer) ListStreams(context.Context, *ListStream
 func (UnimplementedStreamServiceServer) GetStream(context.Context, *GetStreamRequest) (*GetStreamResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStream not implemented")
 }
+func (UnimplementedStreamServiceServer) CreateStream(context.Context, *CreateStreamRequest) (*CreateStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateStream not implemented")
+}
 func (UnimplementedStreamServiceServer) UpdateStream(context.Context, *UpdateStreamRequest) (*UpdateStreamResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateStream not implemented")
 }
+func (UnimplementedStreamServiceServer) DeleteStream(context.Context, *DeleteStreamRequest) (*DeleteStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteStream not implemented")
+}
+func (UnimplementedStreamServiceServer) IngestFrames(grpc.ClientStreamingServer[IngestFramesRequest, IngestFramesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method IngestFrames not implemented")
+}
+func (UnimplementedStreamServiceServer) StreamFrames(*StreamFramesRequest, grpc.ServerStreamingServer[Frame]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamFrames not implemented")
+}
 func (UnimplementedStreamServiceServer) mustEmbedUnimplementedStreamServiceServer() {}
 func (UnimplementedStreamServiceServer) testEmbeddedByValue()                       {}
 
@@ -154,6 +240,24 @@ func _StreamService_GetStream_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StreamService_CreateStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).CreateStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_CreateStream_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).CreateStream(ctx, req.(*CreateStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _StreamService_UpdateStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateStreamRequest)
 	if err := dec(in); err != nil {
@@ -172,6 +276,42 @@ func _StreamService_UpdateStream_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StreamService_DeleteStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).DeleteStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_DeleteStream_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).DeleteStream(ctx, req.(*DeleteStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_IngestFrames_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamServiceServer).IngestFrames(&grpc.GenericServerStream[IngestFramesRequest, IngestFramesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StreamService_IngestFramesServer = grpc.ClientStreamingServer[IngestFramesRequest, IngestFramesResponse]
+
+func _StreamService_StreamFrames_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamFramesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamServiceServer).StreamFrames(m, &grpc.GenericServerStream[StreamFramesRequest, Frame]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StreamService_StreamFramesServer = grpc.ServerStreamingServer[Frame]
+
 // StreamService_ServiceDesc is the grpc.ServiceDesc for StreamService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -187,11 +327,30 @@ var StreamService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStream",
 			Handler:    _StreamService_GetStream_Handler,
 		},
+		{
+			MethodName: "CreateStream",
+			Handler:    _StreamService_CreateStream_Handler,
+		},
 		{
 			MethodName: "UpdateStream",
 			Handler:    _StreamService_UpdateStream_Handler,
 		},
+		{
+			MethodName: "DeleteStream",
+			Handler:    _StreamService_DeleteStream_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IngestFrames",
+			Handler:       _StreamService_IngestFrames_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "StreamFrames",
+			Handler:       _StreamService_StreamFrames_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/stream.proto",
 }
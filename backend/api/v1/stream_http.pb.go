@@ -19,11 +19,15 @@ var _ = binding.EncodeURL
 
 const _ = http.SupportPackageIsVersion1
 
+const OperationStreamServiceCreateStream = "/stream.v1.StreamService/CreateStream"
+const OperationStreamServiceDeleteStream = "/stream.v1.StreamService/DeleteStream"
 const OperationStreamServiceGetStream = "/stream.v1.StreamService/GetStream"
 const OperationStreamServiceListStreams = "/stream.v1.StreamService/ListStreams"
 const OperationStreamServiceUpdateStream = "/stream.v1.StreamService/UpdateStream"
 
 type StreamServiceHTTPServer interface {
+	CreateStream(context.Context, *CreateStreamRequest) (*CreateStreamResponse, error)
+	DeleteStream(context.Context, *DeleteStreamRequest) (*DeleteStreamResponse, error)
 	GetStream(context.Context, *GetStreamRequest) (*GetStreamResponse, error)
 	ListStreams(context.Context, *ListStreamsRequest) (*ListStreamsResponse, error)
 	UpdateStream(context.Context, *UpdateStreamRequest) (*UpdateStreamResponse, error)
@@ -33,7 +37,10 @@ func RegisterStreamServiceHTTPServer(s *http.Server, srv StreamServiceHTTPServer
 	r := s.Route("/")
 	r.GET("/v1/streams", _StreamService_ListStreams0_HTTP_Handler(srv))
 	r.GET("/v1/streams/{id}", _StreamService_GetStream0_HTTP_Handler(srv))
+	r.POST("/v1/streams", _StreamService_CreateStream0_HTTP_Handler(srv))
 	r.PUT("/v1/streams/{id}", _StreamService_UpdateStream0_HTTP_Handler(srv))
+	r.PATCH("/v1/streams/{id}", _StreamService_UpdateStream1_HTTP_Handler(srv))
+	r.DELETE("/v1/streams/{id}", _StreamService_DeleteStream0_HTTP_Handler(srv))
 }
 
 func _StreamService_ListStreams0_HTTP_Handler(srv StreamServiceHTTPServer) func(ctx http.Context) error {
@@ -77,6 +84,28 @@ func _StreamService_GetStream0_HTTP_Handler(srv StreamServiceHTTPServer) func(ct
 	}
 }
 
+func _StreamService_CreateStream0_HTTP_Handler(srv StreamServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in CreateStreamRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationStreamServiceCreateStream)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.CreateStream(ctx, req.(*CreateStreamRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*CreateStreamResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
 func _StreamService_UpdateStream0_HTTP_Handler(srv StreamServiceHTTPServer) func(ctx http.Context) error {
 	return func(ctx http.Context) error {
 		var in UpdateStreamRequest
@@ -102,7 +131,56 @@ func _StreamService_UpdateStream0_HTTP_Handler(srv StreamServiceHTTPServer) func
 	}
 }
 
+func _StreamService_UpdateStream1_HTTP_Handler(srv StreamServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in UpdateStreamRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationStreamServiceUpdateStream)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.UpdateStream(ctx, req.(*UpdateStreamRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*UpdateStreamResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _StreamService_DeleteStream0_HTTP_Handler(srv StreamServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in DeleteStreamRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationStreamServiceDeleteStream)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.DeleteStream(ctx, req.(*DeleteStreamRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*DeleteStreamResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
 type StreamServiceHTTPClient interface {
+	CreateStream(ctx context.Context, req *CreateStreamRequest, opts ...http.CallOption) (rsp *CreateStreamResponse, err error)
+	DeleteStream(ctx context.Context, req *DeleteStreamRequest, opts ...http.CallOption) (rsp *DeleteStreamResponse, err error)
 	GetStream(ctx context.Context, req *GetStreamRequest, opts ...http.CallOption) (rsp *GetStreamResponse, err error)
 	ListStreams(ctx context.Context, req *ListStreamsRequest, opts ...http.CallOption) (rsp *ListStreamsResponse, err error)
 	UpdateStream(ctx context.Context, req *UpdateStreamRequest, opts ...http.CallOption) (rsp *UpdateStreamResponse, err error)
@@ -116,6 +194,32 @@ func NewStreamServiceHTTPClient(client *http.Client) StreamServiceHTTPClient {
 	return &StreamServiceHTTPClientImpl{client}
 }
 
+func (c *StreamServiceHTTPClientImpl) CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...http.CallOption) (*CreateStreamResponse, error) {
+	var out CreateStreamResponse
+	pattern := "/v1/streams"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationStreamServiceCreateStream))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *StreamServiceHTTPClientImpl) DeleteStream(ctx context.Context, in *DeleteStreamRequest, opts ...http.CallOption) (*DeleteStreamResponse, error) {
+	var out DeleteStreamResponse
+	pattern := "/v1/streams/{id}"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation(OperationStreamServiceDeleteStream))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "DELETE", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 func (c *StreamServiceHTTPClientImpl) GetStream(ctx context.Context, in *GetStreamRequest, opts ...http.CallOption) (*GetStreamResponse, error) {
 	var out GetStreamResponse
 	pattern := "/v1/streams/{id}"
@@ -148,7 +252,7 @@ func (c *StreamServiceHTTPClientImpl) UpdateStream(ctx context.Context, in *Upda
 	path := binding.EncodeURL(pattern, in, false)
 	opts = append(opts, http.Operation(OperationStreamServiceUpdateStream))
 	opts = append(opts, http.PathTemplate(pattern))
-	err := c.cc.Invoke(ctx, "PUT", path, in, &out, opts...)
+	err := c.cc.Invoke(ctx, "PATCH", path, in, &out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	v1 "stream-server/api/v1"
+	"stream-server/config"
+	idata "stream-server/internal/data"
+)
+
+const adminUsage = `usage: %[1]s admin <command> [flags] [args]
+
+commands:
+  list [-search s] [-oldest]              list streams
+  stats [-gaps n] <id>                    frame stats: seq range, bytes, gaps in sequence
+  delete-frames -from seq [-to seq] <id>  delete frames in [from, to] (to defaults to the last frame)
+  truncate -after seq <id>                delete frames after seq (-after -1 deletes all frames)
+  renumber <id>                           renumber frames 0..n-1 in order, closing gaps
+  migrate [-dir path] [up|status]         apply db migrations (goose format, goose_db_version table)
+
+Frame edits update frame_count and the poster and bump the stream's frames version. Running servers
+notice it on the next request to the stream and drop its cached chunks; no restart is needed.
+Sessions already playing the stream finish on the frames they hold: reconnect them to see the edit.
+`
+
+// errAdminUsage — неверные аргументы: печатаем справку
+var errAdminUsage = errors.New("bad arguments")
+
+// runAdmin — streams admin ...: обслуживание стримов вместо SQL руками
+func runAdmin(ctx context.Context, conf *conf.Config, logger *log.Helper, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, adminUsage, filepath.Base(os.Args[0]))
+		return errAdminUsage
+	}
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("admin "+cmd, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprintf(fs.Output(), adminUsage, filepath.Base(os.Args[0])) }
+
+	switch cmd {
+	case "list":
+		search := fs.String("search", "", "substring of title or description")
+		oldest := fs.Bool("oldest", false, "oldest first")
+		if err := parseAdminFlags(fs, args, 0); err != nil {
+			return err
+		}
+		return adminList(ctx, conf, logger, *search, *oldest)
+	case "stats":
+		gaps := fs.Int("gaps", 20, "how many gaps to print")
+		if err := parseAdminFlags(fs, args, 1); err != nil {
+			return err
+		}
+		return adminStats(ctx, conf, logger, fs.Arg(0), int32(min(*gaps, math.MaxInt32)))
+	case "delete-frames":
+		from := fs.Int64("from", -1, "first sequence to delete")
+		to := fs.Int64("to", math.MaxInt32, "last sequence to delete")
+		if err := parseAdminFlags(fs, args, 1); err != nil {
+			return err
+		}
+		if *from < 0 {
+			return fmt.Errorf("-from is required")
+		}
+		return adminDeleteFrames(ctx, conf, logger, fs.Arg(0), *from, *to)
+	case "truncate":
+		after := fs.Int64("after", math.MinInt64, "keep frames up to this sequence")
+		if err := parseAdminFlags(fs, args, 1); err != nil {
+			return err
+		}
+		if *after < -1 {
+			return fmt.Errorf("-after is required")
+		}
+		return adminDeleteFrames(ctx, conf, logger, fs.Arg(0), *after+1, math.MaxInt32)
+	case "renumber":
+		if err := parseAdminFlags(fs, args, 1); err != nil {
+			return err
+		}
+		return adminRenumber(ctx, conf, logger, fs.Arg(0))
+	case "migrate":
+		dir := fs.String("dir", "db/migrations", "migrations directory")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		action := "up"
+		if fs.NArg() > 0 {
+			action = fs.Arg(0)
+		}
+		if fs.NArg() > 1 || (action != "up" && action != "status") {
+			fs.Usage()
+			return errAdminUsage
+		}
+		return adminMigrate(ctx, conf, logger, *dir, action == "status")
+	}
+	fs.Usage()
+	return fmt.Errorf("unknown admin command %q", cmd)
+}
+
+// parseAdminFlags — флаги и ровно nargs позиционных аргументов
+func parseAdminFlags(fs *flag.FlagSet, args []string, nargs int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return errAdminUsage
+	}
+	return nil
+}
+
+func adminList(ctx context.Context, conf *conf.Config, logger *log.Helper, search string, oldest bool) error {
+	uc, _, cleanup, err := initUsecase(ctx, conf, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	in := &v1.ListStreamsRequest{PageSize: 200, Search: search}
+	if oldest {
+		in.OrderBy = "created_at asc"
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tFRAMES\tINTERVAL\tCREATED")
+	for {
+		streams, next, err := uc.ListStreams(ctx, in)
+		if err != nil {
+			return err
+		}
+		for _, s := range streams {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%dms\t%s\n", s.Id, s.Title, s.FrameCount, s.FrameIntervalMs, s.CreatedAt.AsTime().Local().Format(time.DateTime))
+		}
+		if next == "" {
+			return tw.Flush()
+		}
+		in.PageToken = next
+	}
+}
+
+func adminStats(ctx context.Context, conf *conf.Config, logger *log.Helper, id string, gaps int32) error {
+	uc, _, cleanup, err := initUsecase(ctx, conf, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	st, err := uc.FrameStats(ctx, id, gaps)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("frames:   %d\n", st.Frames)
+	if st.Frames == 0 {
+		return nil
+	}
+	fmt.Printf("sequence: %d..%d\n", st.MinSeq, st.MaxSeq)
+	fmt.Printf("bytes:    %d (%s, avg %s/frame)\n", st.Bytes, formatBytes(st.Bytes), formatBytes(st.Bytes/st.Frames))
+	fmt.Printf("gaps:     %d (%d missing sequence numbers)\n", st.Gaps, st.MissingFrames)
+	for _, g := range st.FirstGaps {
+		if g.FromSeq == g.ToSeq {
+			fmt.Printf("  missing %d\n", g.FromSeq)
+		} else {
+			fmt.Printf("  missing %d..%d (%d)\n", g.FromSeq, g.ToSeq, g.ToSeq-g.FromSeq+1)
+		}
+	}
+	if int64(len(st.FirstGaps)) < st.Gaps {
+		fmt.Printf("  ... %d more\n", st.Gaps-int64(len(st.FirstGaps)))
+	}
+	return nil
+}
+
+func adminDeleteFrames(ctx context.Context, conf *conf.Config, logger *log.Helper, id string, from, to int64) error {
+	uc, _, cleanup, err := initUsecase(ctx, conf, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	deleted, err := uc.DeleteFrames(ctx, id, from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deleted %d frames\n", deleted)
+	return nil
+}
+
+func adminRenumber(ctx context.Context, conf *conf.Config, logger *log.Helper, id string) error {
+	uc, _, cleanup, err := initUsecase(ctx, conf, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	moved, err := uc.RenumberFrames(ctx, id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("renumbered %d frames\n", moved)
+	return nil
+}
+
+func adminMigrate(ctx context.Context, conf *conf.Config, logger *log.Helper, dir string, statusOnly bool) error {
+	migrations, err := idata.LoadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return fmt.Errorf("no migrations in %s", dir)
+	}
+	clients, cleanup, err := idata.NewClients(ctx, &conf.Database, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if !statusOnly {
+		done, err := idata.Migrate(ctx, clients.DBClientPool, migrations, logger)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migrations\n", len(done))
+	}
+
+	statuses, err := idata.MigrationStatuses(ctx, clients.DBClientPool, migrations)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tMIGRATION\tAPPLIED")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.Applied {
+			applied = s.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return tw.Flush()
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -29,10 +29,16 @@ func initApp(ctx context.Context, conf *conf.Config, logger *log.Helper) (*krato
 	if err != nil {
 		return nil, nil, err
 	}
+	// до серверов: tracing middleware kratos берёт глобальный провайдер при создании
+	_, traceCleanup, err := dep.NewTracerProvider(conf)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Repo
 	dataClients, cleanup, err := idata.NewClients(ctx, &conf.Database, logger)
 	if err != nil {
+		traceCleanup()
 		return nil, nil, err
 	}
 	streamQueries := queries.New(dataClients.DBClientPool)
@@ -40,21 +46,41 @@ func initApp(ctx context.Context, conf *conf.Config, logger *log.Helper) (*krato
 	streamRepoWrapper := wrapper.NewStreamRepoWrapper(streamRepo)
 
 	// Usecase
-	streamUsecase := biz.NewStreamUsecase(streamRepoWrapper, logger, conf)
+	streamPoolStore, storeCleanup, err := biz.NewStreamPoolStore(conf, dataClients.DBClientPool, meter)
+	if err != nil {
+		cleanup()
+		traceCleanup()
+		return nil, nil, err
+	}
+	streamUsecase := biz.NewStreamUsecase(streamRepoWrapper, streamPoolStore, logger, conf)
 	streamUsecaseWrapper := wrapper.NewStreamUsecaseWrapper(streamUsecase)
-	streamPoolStore := biz.NewStreamPoolStore(conf, dataClients.DBClientPool)
+
+	// Постеры для стримов, загруженных до их появления; в фоне, чтобы не задерживать старт
+	go func() {
+		filled, err := streamUsecase.BackfillPosters(ctx)
+		if err != nil {
+			logger.Errorf("error backfill posters: %v", err)
+			return
+		}
+		if filled > 0 {
+			logger.Infof("backfilled %d stream posters", filled)
+		}
+	}()
 
 	// Services
-	streamService := service.NewStreamService(streamUsecaseWrapper, logger, streamPoolStore)
+	streamService := service.NewStreamService(streamUsecaseWrapper, logger, streamPoolStore, conf)
 	streamServiceWrapper := wrapper.NewStreamServiceWrapper(streamService)
 	healthService := service.NewHealthService(dataClients.DBClientPool)
 
 	streamServer := server.NewHTTPStreamServer(conf, streamServiceWrapper, healthService, meter, logger)
+	grpcServer := server.NewGRPCStreamServer(conf, streamServiceWrapper, healthService, meter, logger)
 	metricsServer := server.NewMetricsServer(conf, logger)
-	app := newApp(ctx, logger.Logger(), streamServer, metricsServer)
+	app := newApp(ctx, logger.Logger(), streamServer, grpcServer, metricsServer)
 
 	return app, func() {
+		storeCleanup()
 		cleanup()
+		traceCleanup()
 	}, nil
 }
 
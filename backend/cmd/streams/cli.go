@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	"stream-server/config"
+	"stream-server/internal/biz"
+	idata "stream-server/internal/data"
+	queries "stream-server/internal/data/repo"
+	"stream-server/internal/repo"
+	"stream-server/internal/wrapper"
+)
+
+// runCommand — подкоманды CLI (streams <command> ...); без подкоманды main запускает сервер
+func runCommand(ctx context.Context, conf *conf.Config, logger *log.Helper, args []string) error {
+	switch args[0] {
+	case "import":
+		return runImport(ctx, conf, logger, args[1:])
+	case "admin":
+		return runAdmin(ctx, conf, logger, args[1:])
+	}
+	return fmt.Errorf("unknown command %q (available: import, admin)", args[0])
+}
+
+// initUsecase — repo и usecase без серверов и кэша чанков: для подкоманд CLI
+func initUsecase(ctx context.Context, conf *conf.Config, logger *log.Helper) (*biz.StreamUsecase, *idata.Clients, func(), error) {
+	dataClients, cleanup, err := idata.NewClients(ctx, &conf.Database, logger)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	streamQueries := queries.New(dataClients.DBClientPool)
+	streamRepo := wrapper.NewStreamRepoWrapper(repo.NewStreamRepo(streamQueries, logger, conf, dataClients))
+	return biz.NewStreamUsecase(streamRepo, nil, logger, conf), dataClients, cleanup, nil
+}
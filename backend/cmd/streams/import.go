@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	v1 "stream-server/api/v1"
+	"stream-server/config"
+	"stream-server/internal/biz"
+)
+
+// runImport — streams import [flags] <file|->: новый стрим из Motion-JPEG AVI или сырого MJPEG
+func runImport(ctx context.Context, conf *conf.Config, logger *log.Helper, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	title := fs.String("title", "", "stream title (default: file name)")
+	description := fs.String("description", "", "stream description")
+	interval := fs.Int("interval", 0, "frame interval in ms (default: from AVI header, 40 for raw MJPEG)")
+	format := fs.String("format", biz.ImportAuto, "input format: auto, avi or mjpeg")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s import [flags] <file|->\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one file")
+	}
+
+	path := fs.Arg(0)
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+		if *title == "" {
+			*title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+	}
+
+	src, detected, err := biz.OpenImport(in, *format)
+	if err != nil {
+		return err
+	}
+
+	uc, _, cleanup, err := initUsecase(ctx, conf, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	stream, err := uc.ImportStream(ctx, &v1.CreateStreamRequest{
+		Title:           *title,
+		Description:     *description,
+		FrameIntervalMs: biz.ImportInterval(int32(*interval), detected),
+	}, src)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("imported %d frames into stream %s (%q, %d ms/frame)\n", stream.FrameCount, stream.Id, stream.Title, stream.FrameIntervalMs)
+	return nil
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/signal"
 	"stream-server/logger"
+	"syscall"
 
 	"stream-server/config"
 
@@ -42,6 +45,17 @@ func main() {
 	)
 	logHelper := log.NewHelper(klog)
 
+	// подкоманды CLI; без аргументов — сервер
+	if len(os.Args) > 1 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err = runCommand(ctx, cfg, logHelper, os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app, cancel, err := initApp(context.Background(), cfg, logHelper)
 	if err != nil {
 		logHelper.Fatalf("init app error: %s", err)
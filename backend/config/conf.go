@@ -10,10 +10,13 @@ type (
 	Config struct {
 		Metadata
 		Http
+		Grpc
 		Log
 		Database
 		Metrics
+		Tracing
 		SocketPool
+		Ingest
 	}
 
 	Metadata struct {
@@ -28,6 +31,12 @@ type (
 		Timeout int    `env:"HTTP_TIMEOUT"`
 	}
 
+	Grpc struct {
+		Network string `env:"GRPC_NETWORK"`
+		Addr    string `env:"GRPC_ADDRESS" envDefault:"0.0.0.0:9000"`
+		Timeout int    `env:"GRPC_TIMEOUT" envDefault:"5"` // секунд на unary-вызов; на IngestFrames не действует
+	}
+
 	Log struct {
 		Level string `env:"LOG_LEVEL,required"`
 	}
@@ -55,9 +64,29 @@ type (
 		ServerPort  int    `env:"PROMETHEUS_SERVER_PORT,required"`
 	}
 
+	Tracing struct {
+		// OTLP/HTTP-приёмник трейсов (коллектор), host:port; "" — спаны не экспортируются
+		Endpoint    string  `env:"OTLP_ENDPOINT"`
+		Insecure    bool    `env:"OTLP_INSECURE" envDefault:"true"`   // http вместо https (коллектор рядом, в той же сети)
+		SampleRatio float64 `env:"TRACE_SAMPLE_RATIO" envDefault:"1"` // доля новых трейсов; входящий traceparent решает сам
+	}
+
 	SocketPool struct {
 		ChunkFrames   int64 `env:"CHUNK_FRAMES" envDefault:"256"`
 		CacheCapBytes int64 `env:"CACHE_CAP_BYTES" envDefault:"536870912"` // 512 MB (512<<20)
+		MaxFPS        int   `env:"MAX_FPS" envDefault:"60"`                // потолок частоты слотов при ускорении (?rate=)
+		// доля чанка, пройдя которую сессия подгружает следующий чанк в фоне; 0 — без read-ahead
+		ChunkPrefetchAt float64 `env:"CHUNK_PREFETCH_AT" envDefault:"0.5"`
+		// политика вытеснения чанков: lru, tinylfu (W-TinyLFU) или cursor (защита чанков впереди зрителей)
+		CacheEviction string `env:"CACHE_EVICTION" envDefault:"lru"`
+		// второй уровень кэша чанков на диске: каталог сегментов ("" — выключен) и его бюджет
+		DiskCacheDir   string `env:"DISK_CACHE_DIR"`
+		DiskCacheBytes int64  `env:"DISK_CACHE_BYTES" envDefault:"4294967296"` // 4 GB
+	}
+
+	Ingest struct {
+		BatchFrames int `env:"INGEST_BATCH_FRAMES" envDefault:"64"` // кадров в одном COPY
+		FlushMS     int `env:"INGEST_FLUSH_MS" envDefault:"200"`    // дольше этого кадр не ждёт пачку (задержка live-зрителей)
 	}
 )
 
@@ -0,0 +1,63 @@
+-- Обслуживание стримов (streams admin): сводка по кадрам, дыры в sequence, удаление и перенумерация кадров
+
+-- name: GetFrameStats :one
+SELECT count(*)::bigint AS frames,
+       coalesce(min(sequence), -1)::bigint AS min_seq,
+       coalesce(max(sequence), -1)::bigint AS max_seq,
+       coalesce(sum(bytes), 0)::bigint AS bytes,
+       count(*) FILTER (WHERE sequence - prev > 1)::bigint AS gaps,
+       coalesce(sum(sequence - prev - 1) FILTER (WHERE sequence - prev > 1), 0)::bigint AS missing_frames
+FROM (
+    SELECT sequence, octet_length(payload) AS bytes, lag(sequence) OVER (ORDER BY sequence) AS prev
+    FROM frames
+    WHERE stream_id = $1
+) f
+;
+
+-- name: ListFrameGaps :many
+SELECT (prev + 1)::bigint AS from_seq, (sequence - 1)::bigint AS to_seq
+FROM (
+    SELECT sequence, lag(sequence) OVER (ORDER BY sequence) AS prev
+    FROM frames
+    WHERE stream_id = $1
+) f
+WHERE sequence - prev > 1
+ORDER BY sequence
+LIMIT $2
+;
+
+-- name: DeleteFrameRange :execrows
+DELETE FROM frames
+WHERE stream_id = @stream_id
+  AND sequence >= @from_seq::bigint AND sequence <= @to_seq::bigint
+;
+
+-- Перенумерация в два шага: UNIQUE (stream_id, sequence) проверяется на каждой строке,
+-- поэтому сначала уводим кадры в отрицательные номера -(позиция), потом возвращаем позиция-1
+
+-- name: StageFrameRenumber :execrows
+UPDATE frames f
+SET sequence = -r.pos
+FROM (
+    SELECT id, row_number() OVER (ORDER BY sequence) AS pos
+    FROM frames
+    WHERE stream_id = $1
+) r
+WHERE f.id = r.id AND f.sequence <> r.pos - 1
+;
+
+-- name: FinishFrameRenumber :execrows
+UPDATE frames
+SET sequence = -sequence - 1
+WHERE stream_id = $1 AND sequence < 0
+;
+
+-- name: ResetStreamFrameStats :exec
+UPDATE streams
+SET frame_count = (SELECT count(*) FROM frames WHERE stream_id = $1),
+    thumbnail = NULL,
+    version = version + 1,
+    frames_version = frames_version + 1,
+    updated_at = now()
+WHERE id = $1
+;
@@ -1,25 +1,42 @@
 -- name: ListStreams :many
-select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, count(f.id) as frame_count
-from streams s left join frames f on f.stream_id = s.id
-group by s.id
-order by s.created_at desc
+select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, s.frame_count,
+       coalesce(length(s.thumbnail), 0) > 0 as has_thumbnail, s.version
+from streams s
+where (@pattern::text = '' or s.title ilike @pattern or s.description ilike @pattern)
+  and (sqlc.narg(after_created_at)::timestamptz is null
+       or (s.created_at, s.id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+order by s.created_at desc, s.id desc
+limit @page_limit
+;
+
+-- name: ListStreamsOldestFirst :many
+select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, s.frame_count,
+       coalesce(length(s.thumbnail), 0) > 0 as has_thumbnail, s.version
+from streams s
+where (@pattern::text = '' or s.title ilike @pattern or s.description ilike @pattern)
+  and (sqlc.narg(after_created_at)::timestamptz is null
+       or (s.created_at, s.id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+order by s.created_at, s.id
+limit @page_limit
 ;
 
 -- name: GetStream :one
-select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, count(f.id) as frame_count
-from streams s left join frames f on f.stream_id = s.id
-group by s.id
-having s.id = $1
+select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, s.frame_count,
+       coalesce(length(s.thumbnail), 0) > 0 as has_thumbnail, s.version
+from streams s
+where s.id = $1
 ;
 
 -- name: UpdateStream :one
 UPDATE streams s
 SET
     updated_at = now(),
-    title = $2,
-    description = $3,
-    frame_interval_ms = $4
-WHERE s.id = $1
+    version = s.version + 1,
+    title = coalesce(sqlc.narg(title)::text, s.title),
+    description = coalesce(sqlc.narg(description)::text, s.description),
+    frame_interval_ms = coalesce(sqlc.narg(frame_interval_ms)::integer, s.frame_interval_ms)
+WHERE s.id = @id
+  AND (sqlc.narg(expected_version)::bigint IS NULL OR s.version = sqlc.narg(expected_version)::bigint)
     RETURNING
     s.id,
     s.title,
@@ -27,9 +44,79 @@ WHERE s.id = $1
     s.frame_interval_ms,
     s.created_at,
     s.updated_at,
-    (
-        SELECT count(f.id)
-        FROM frames f
-        WHERE f.stream_id = s.id
-    ) AS frame_count
+    s.frame_count,
+    coalesce(length(s.thumbnail), 0) > 0 AS has_thumbnail,
+    s.version
+;
+
+-- name: CreateStream :one
+INSERT INTO streams (id, title, description, frame_interval_ms)
+VALUES (uuid_generate_v4(), $1, $2, $3)
+RETURNING id, title, description, frame_interval_ms, created_at, updated_at, thumbnail, frame_count, version, frames_version
+;
+
+-- name: DeleteStream :execrows
+DELETE FROM streams
+WHERE id = $1
+;
+
+-- name: LockStream :one
+SELECT id
+FROM streams
+WHERE id = $1
+FOR UPDATE
+;
+
+-- name: GetFrameRangeStats :one
+SELECT count(*)::bigint AS frames,
+       coalesce(sum(octet_length(payload)), 0)::bigint AS bytes,
+       count(*) FILTER (WHERE octet_length(payload) % 2 = 1)::bigint AS odd_frames,
+       coalesce(max(sequence), -1)::bigint AS last_seq
+FROM frames
+WHERE stream_id = @stream_id
+  AND sequence >= @from_seq::bigint AND sequence <= @to_seq::bigint
+  AND mime_type = @mime_type
+;
+
+-- name: GetNextFrameSequence :one
+SELECT COALESCE(MAX(sequence) + 1, 0)::integer AS next_seq
+FROM frames
+WHERE stream_id = $1
+;
+
+-- name: AddStreamFrameCount :exec
+UPDATE streams
+SET frame_count = frame_count + $2
+WHERE id = $1
+;
+
+-- name: InsertFrames :copyfrom
+INSERT INTO frames (id, stream_id, sequence, payload, mime_type)
+VALUES ($1, $2, $3, $4, $5)
+;
+
+-- name: GetStreamThumbnail :one
+SELECT thumbnail, frames_version
+FROM streams
+WHERE id = $1
+;
+
+-- name: SetStreamThumbnail :exec
+UPDATE streams
+SET thumbnail = $2
+WHERE id = $1 AND thumbnail IS NULL
+;
+
+-- name: ListStreamsWithoutThumbnail :many
+SELECT s.id, f.payload
+FROM streams s
+JOIN LATERAL (
+    SELECT payload
+    FROM frames
+    WHERE stream_id = s.id
+    ORDER BY sequence
+    LIMIT 1
+) f ON true
+WHERE s.thumbnail IS NULL
+LIMIT $1
 ;
@@ -0,0 +1,122 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"stream-server/internal/converters"
+	"stream-server/internal/interfaces"
+)
+
+var _ interfaces.IAdminUsecase = (*StreamUsecase)(nil)
+
+// FrameStats — сводка по кадрам стрима и первые maxGaps пропусков в sequence
+func (u *StreamUsecase) FrameStats(ctx context.Context, streamID string, maxGaps int32) (res *interfaces.FrameStats, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	id, err := converters.StringToPgUUID(streamID)
+	if err != nil {
+		return nil, fmt.Errorf("error converting uuid: %w", err)
+	}
+	if _, err = u.repo.GetStream(ctx, id); err != nil {
+		return nil, fmt.Errorf("error get stream: %w", err)
+	}
+
+	stats, err := u.repo.GetFrameStats(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error get frame stats: %w", err)
+	}
+	res = &interfaces.FrameStats{
+		Frames:        stats.Frames,
+		MinSeq:        stats.MinSeq,
+		MaxSeq:        stats.MaxSeq,
+		Bytes:         stats.Bytes,
+		Gaps:          stats.Gaps,
+		MissingFrames: stats.MissingFrames,
+	}
+	if stats.Gaps == 0 || maxGaps <= 0 {
+		return res, nil
+	}
+
+	gaps, err := u.repo.ListFrameGaps(ctx, id, maxGaps)
+	if err != nil {
+		return nil, fmt.Errorf("error list frame gaps: %w", err)
+	}
+	for _, g := range gaps {
+		res.FirstGaps = append(res.FirstGaps, interfaces.FrameGap{FromSeq: g.FromSeq, ToSeq: g.ToSeq})
+	}
+	return res, nil
+}
+
+// DeleteFrames — удалить кадры [fromSeq, toSeq]; дыра в sequence остаётся (закрывает её RenumberFrames)
+func (u *StreamUsecase) DeleteFrames(ctx context.Context, streamID string, fromSeq, toSeq int64) (deleted int64, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	if fromSeq < 0 || toSeq < fromSeq {
+		return 0, fmt.Errorf("%w: from_seq=%d to_seq=%d", ErrBadRange, fromSeq, toSeq)
+	}
+	id, err := converters.StringToPgUUID(streamID)
+	if err != nil {
+		return 0, fmt.Errorf("error converting uuid: %w", err)
+	}
+
+	if deleted, err = u.repo.DeleteFrames(ctx, id, fromSeq, toSeq); err != nil {
+		return 0, fmt.Errorf("error delete frames: %w", err)
+	}
+	u.framesRewritten(ctx, id)
+	return deleted, nil
+}
+
+// RenumberFrames — перенумеровать кадры подряд с 0 (закрыть дыры); возвращает число сдвинутых кадров
+func (u *StreamUsecase) RenumberFrames(ctx context.Context, streamID string) (moved int64, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	id, err := converters.StringToPgUUID(streamID)
+	if err != nil {
+		return 0, fmt.Errorf("error converting uuid: %w", err)
+	}
+
+	if moved, err = u.repo.RenumberFrames(ctx, id); err != nil {
+		return 0, fmt.Errorf("error renumber frames: %w", err)
+	}
+	u.framesRewritten(ctx, id)
+	return moved, nil
+}
+
+// framesRewritten — кадры стрима изменились не дозаписью: repo сбросил постер (первый кадр мог смениться)
+// и поднял версию стрима. Постер пересобираем сразу и только этому стриму; запущенные серверы увидят
+// новую версию при следующем обращении к стриму (LoadStreamMeta) и сами сбросят его чанки
+func (u *StreamUsecase) framesRewritten(ctx context.Context, id pgtype.UUID) {
+	if err := u.rebuildPoster(ctx, id); err != nil {
+		u.log.Errorf("error rebuild poster for stream %s: %v", id.String(), err)
+	}
+}
+
+// rebuildPoster — постер из текущего первого кадра стрима; без кадров постера нет
+func (u *StreamUsecase) rebuildPoster(ctx context.Context, id pgtype.UUID) error {
+	stats, err := u.repo.GetFrameStats(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error get frame stats: %w", err)
+	}
+	if stats.Frames == 0 {
+		return nil
+	}
+
+	var first []byte
+	err = u.repo.ScanFrames(ctx, id, stats.MinSeq, stats.MinSeq, func(_ int64, payload []byte, _ string) error {
+		first = append([]byte(nil), payload...) // payload — буфер драйвера
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error read first frame: %w", err)
+	}
+	if first == nil {
+		return nil
+	}
+	if err = u.repo.SetStreamThumbnail(ctx, id, makePoster(first)); err != nil {
+		return fmt.Errorf("error save poster: %w", err)
+	}
+	return nil
+}
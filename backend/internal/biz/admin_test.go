@@ -0,0 +1,80 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbrepo "stream-server/internal/data/repo"
+	"stream-server/internal/interfaces"
+)
+
+func TestStreamUsecase_FrameStats(t *testing.T) {
+	repo := &stubRepo{
+		frameStats: dbrepo.GetFrameStatsRow{Frames: 7, MinSeq: 0, MaxSeq: 11, Bytes: 700, Gaps: 3, MissingFrames: 5},
+		gaps:       []dbrepo.ListFrameGapsRow{{FromSeq: 2, ToSeq: 3}, {FromSeq: 6, ToSeq: 6}, {FromSeq: 8, ToSeq: 9}},
+	}
+	uc := newIngestUsecase(repo, nil, 1)
+
+	res, err := uc.FrameStats(context.Background(), uuid.NewString(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interfaces.FrameGap{{FromSeq: 2, ToSeq: 3}, {FromSeq: 6, ToSeq: 6}}
+	if res.Frames != 7 || res.MaxSeq != 11 || res.Gaps != 3 || res.MissingFrames != 5 || len(res.FirstGaps) != 2 ||
+		res.FirstGaps[0] != want[0] || res.FirstGaps[1] != want[1] || repo.gapLimit != 2 {
+		t.Fatalf("unexpected stats %+v (limit %d)", res, repo.gapLimit)
+	}
+
+	// без дыр список пропусков не запрашивается
+	repo = &stubRepo{frameStats: dbrepo.GetFrameStatsRow{Frames: 3, MaxSeq: 2}}
+	if res, err = newIngestUsecase(repo, nil, 1).FrameStats(context.Background(), uuid.NewString(), 10); err != nil || res.FirstGaps != nil || repo.gapLimit != 0 {
+		t.Fatalf("stats without gaps: %+v %v", res, err)
+	}
+
+	repo = &stubRepo{err: pgx.ErrNoRows}
+	if _, err = newIngestUsecase(repo, nil, 1).FrameStats(context.Background(), uuid.NewString(), 10); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("missing stream: %v", err)
+	}
+}
+
+func TestStreamUsecase_DeleteAndRenumberFrames(t *testing.T) {
+	id := uuid.New()
+	other := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	first := testJPEG(t, 16, 8)
+	repo := &stubRepo{
+		batches:    [][]dbrepo.InsertFramesParams{{{Sequence: 10, Payload: first, MimeType: "image/jpeg"}}},
+		frameStats: dbrepo.GetFrameStatsRow{Frames: 1, MinSeq: 10, MaxSeq: 10},
+		noPosters:  []dbrepo.ListStreamsWithoutThumbnailRow{{ID: other, Payload: []byte{2}}},
+	}
+	uc := newIngestUsecase(repo, nil, 1)
+
+	deleted, err := uc.DeleteFrames(context.Background(), id.String(), 5, 9)
+	if err != nil || deleted != 5 || repo.deletedRange[0] != 5 || repo.deletedRange[1] != 9 {
+		t.Fatalf("delete: %d %v %v", deleted, repo.deletedRange, err)
+	}
+	// постер сброшен вместе с правкой кадров — пересоздан сразу из нового первого кадра, чужие стримы не трогаем
+	if p := repo.posters[pgtype.UUID{Bytes: id, Valid: true}]; !bytes.Equal(p, first) {
+		t.Fatalf("poster must be regenerated from the first frame, got %v", p)
+	}
+	if _, ok := repo.posters[other]; ok {
+		t.Fatal("only the edited stream's poster must be rebuilt")
+	}
+	if _, err = uc.RenumberFrames(context.Background(), id.String()); err != nil || repo.renumbered != 1 {
+		t.Fatalf("renumber: %v", err)
+	}
+
+	for _, r := range [][2]int64{{-1, 5}, {6, 5}} {
+		if _, err = uc.DeleteFrames(context.Background(), id.String(), r[0], r[1]); !errors.Is(err, ErrBadRange) {
+			t.Fatalf("range %v: %v", r, err)
+		}
+	}
+	if _, err = uc.RenumberFrames(context.Background(), "nope"); err == nil {
+		t.Fatal("bad uuid must be rejected")
+	}
+}
@@ -0,0 +1,93 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz/session/store_pool"
+	"stream-server/internal/converters"
+)
+
+// invalidArgument ошибки запроса клиента (400 INVALID_ARGUMENT)
+var invalidArgument = []error{
+	converters.ErrBadUUID,
+	ErrBadPageToken,
+	ErrBadOrderBy,
+	ErrBadUpdateMask,
+	ErrEmptyUpdate,
+	ErrEmptyTitle,
+	ErrBadInterval,
+	ErrEmptyFrame,
+	ErrBadThumbnailWidth,
+	ErrBadRange,
+	ErrBadImport,
+	ErrBadImportFormat,
+}
+
+// ToApiError — ошибка нижних слоёв → kratos-ошибка с причиной из ErrorReason
+// По ней REST отдаёт HTTP-код, gRPC — статус; исходная ошибка остаётся cause (errors.Is по ней работает)
+// Уже размеченные и нераспознанные ошибки возвращаются как есть (последние — 500)
+func ToApiError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var se *kerrors.Error
+	if errors.As(err, &se) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, store_pool.ErrStreamNotFound):
+		return v1.ErrorStreamNotFound("stream not found").WithCause(err)
+	case errors.Is(err, store_pool.ErrFrameNotFound):
+		return v1.ErrorFrameNotFound("frame not found").WithCause(err)
+	case errors.Is(err, ErrFrameTooLarge):
+		return v1.ErrorFrameTooLarge("%s", ErrFrameTooLarge).WithCause(err)
+	case errors.Is(err, ErrFrameNotImage):
+		return v1.ErrorUnsupportedMediaType("%s", rootMessage(err, ErrFrameNotImage)).WithCause(err)
+	case errors.Is(err, ErrFrameUndecodable):
+		return v1.ErrorFrameUndecodable("%s", ErrFrameUndecodable).WithCause(err)
+	case errors.Is(err, store_pool.ErrCachePressure):
+		return v1.ErrorCachePressure("frame cache is overloaded, retry later").WithCause(err)
+	case isDBUnavailable(err):
+		return v1.ErrorDbUnavailable("database unavailable").WithCause(err)
+	}
+	for _, target := range invalidArgument {
+		if errors.Is(err, target) {
+			return v1.ErrorInvalidArgument("%s", rootMessage(err, target)).WithCause(err)
+		}
+	}
+	return err
+}
+
+// rootMessage — текст ошибки без служебных префиксов слоёв ("error update stream: ...")
+// начиная с сентинела: клиенту важна причина, а не путь по коду
+func rootMessage(err, target error) string {
+	msg := err.Error()
+	if i := strings.Index(msg, target.Error()); i >= 0 {
+		return msg[i:]
+	}
+	return target.Error()
+}
+
+// isDBUnavailable — БД не отвечает: нет соединения, таймаут или сервер не принимает запросы
+func isDBUnavailable(err error) bool {
+	var connErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connErr) || errors.As(err, &netErr) || pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// 08 — connection exception, 53 — insufficient resources, 57P — shutdown / cannot connect now
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") || strings.HasPrefix(pgErr.Code, "57P")
+	}
+	return false
+}
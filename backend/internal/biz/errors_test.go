@@ -0,0 +1,64 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz/session/store_pool"
+	"stream-server/internal/converters"
+)
+
+func TestToApiError(t *testing.T) {
+	_, badUUID := converters.StringToPgUUID("nope")
+	cases := []struct {
+		err    error
+		reason v1.ErrorReason
+		code   int
+	}{
+		{fmt.Errorf("error get stream: %w", pgx.ErrNoRows), v1.ErrorReason_STREAM_NOT_FOUND, 404},
+		{store_pool.ErrStreamNotFound, v1.ErrorReason_STREAM_NOT_FOUND, 404},
+		{store_pool.ErrFrameNotFound, v1.ErrorReason_FRAME_NOT_FOUND, 404},
+		{fmt.Errorf("error converting uuid: %w", badUUID), v1.ErrorReason_INVALID_ARGUMENT, 400},
+		{ErrBadPageToken, v1.ErrorReason_INVALID_ARGUMENT, 400},
+		{ErrFrameTooLarge, v1.ErrorReason_FRAME_TOO_LARGE, 413},
+		{fmt.Errorf("%w: text/plain", ErrFrameNotImage), v1.ErrorReason_UNSUPPORTED_MEDIA_TYPE, 415},
+		{fmt.Errorf("%w: bad huffman", ErrFrameUndecodable), v1.ErrorReason_FRAME_UNDECODABLE, 422},
+		{fmt.Errorf("%w: too many chunks", store_pool.ErrCachePressure), v1.ErrorReason_CACHE_PRESSURE, 503},
+		{&pgconn.PgError{Code: "57P03"}, v1.ErrorReason_DB_UNAVAILABLE, 503},
+		{fmt.Errorf("error list: %w", context.DeadlineExceeded), v1.ErrorReason_DB_UNAVAILABLE, 503},
+	}
+	for _, c := range cases {
+		got := ToApiError(c.err)
+		se := kerrors.FromError(got)
+		if se.Reason != c.reason.String() || int(se.Code) != c.code {
+			t.Errorf("%v: got %s/%d, want %s/%d", c.err, se.Reason, se.Code, c.reason, c.code)
+		}
+		// исходная ошибка доступна через errors.Is
+		if !errors.Is(got, c.err) {
+			t.Errorf("%v: cause lost", c.err)
+		}
+	}
+
+	// Сообщение без префиксов слоёв
+	got := kerrors.FromError(ToApiError(fmt.Errorf("error converting uuid: %w", badUUID)))
+	if got.Message != `bad uuid "nope"` {
+		t.Errorf("unexpected message %q", got.Message)
+	}
+
+	// Уже размеченные и неизвестные ошибки не трогаем
+	mismatch := v1.ErrorStreamVersionMismatch("stale")
+	if ToApiError(mismatch) != mismatch {
+		t.Error("kratos error must pass through")
+	}
+	plain := errors.New("boom")
+	if ToApiError(plain) != plain || ToApiError(nil) != nil {
+		t.Error("unknown errors must pass through")
+	}
+}
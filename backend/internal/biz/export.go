@@ -0,0 +1,86 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"stream-server/internal/converters"
+	dbrepo "stream-server/internal/data/repo"
+	"stream-server/internal/interfaces"
+)
+
+// ErrBadRange диапазон кадров для выгрузки задан неверно
+var ErrBadRange = errors.New("bad frame range")
+
+// ExportFrames — кадры стрима из [fromSeq, toSeq] по порядку, прямо из БД (мимо кэша чанков)
+// Стрим проверяется заранее: пока fn ничего не получил, вызывающий ещё может ответить 404
+// Проверка и выгрузка идут в одном снимке БД
+func (u *StreamUsecase) ExportFrames(ctx context.Context, streamID string, fromSeq, toSeq int64, fn interfaces.FrameFunc) (err error) {
+	defer func() { err = ToApiError(err) }()
+
+	if fromSeq < 0 || toSeq < fromSeq {
+		return fmt.Errorf("%w: from_seq=%d to_seq=%d", ErrBadRange, fromSeq, toSeq)
+	}
+	id, err := converters.StringToPgUUID(streamID)
+	if err != nil {
+		return fmt.Errorf("error converting uuid: %w", err)
+	}
+	return u.repo.ReadSnapshot(ctx, func(snap interfaces.IFrameReader) error {
+		if _, err := snap.GetStream(ctx, id); err != nil {
+			return fmt.Errorf("error get stream: %w", err)
+		}
+		if err := snap.ScanFrames(ctx, id, fromSeq, toSeq, fn); err != nil {
+			return fmt.Errorf("error export frames: %w", err)
+		}
+		return nil
+	})
+}
+
+// ExportFrameRange — как ExportFrames, но только кадры с заданным mime и со сводкой по ним заранее:
+// prepare получает сводку до первого кадра (AVI считает по ней размеры файла).
+// Сводка и выгрузка идут в одном снимке БД, поэтому выгружаются ровно кадры из сводки
+func (u *StreamUsecase) ExportFrameRange(ctx context.Context, streamID string, fromSeq, toSeq int64, mime string, prepare func(fr *interfaces.FrameRange) error, fn interfaces.FrameFunc) (err error) {
+	defer func() { err = ToApiError(err) }()
+
+	if fromSeq < 0 || toSeq < fromSeq {
+		return fmt.Errorf("%w: from_seq=%d to_seq=%d", ErrBadRange, fromSeq, toSeq)
+	}
+	id, err := converters.StringToPgUUID(streamID)
+	if err != nil {
+		return fmt.Errorf("error converting uuid: %w", err)
+	}
+	return u.repo.ReadSnapshot(ctx, func(snap interfaces.IFrameReader) error {
+		stream, err := snap.GetStream(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error get stream: %w", err)
+		}
+		stats, err := snap.GetFrameRangeStats(ctx, dbrepo.GetFrameRangeStatsParams{StreamID: id, FromSeq: fromSeq, ToSeq: toSeq, MimeType: mime})
+		if err != nil {
+			return fmt.Errorf("error get frame range: %w", err)
+		}
+		if err = prepare(&interfaces.FrameRange{
+			IntervalMS: stream.FrameIntervalMs,
+			Frames:     stats.Frames,
+			Bytes:      stats.Bytes,
+			OddFrames:  stats.OddFrames,
+			LastSeq:    stats.LastSeq,
+		}); err != nil {
+			return err
+		}
+		if stats.LastSeq < fromSeq {
+			return nil
+		}
+
+		err = snap.ScanFrames(ctx, id, fromSeq, stats.LastSeq, func(seq int64, payload []byte, frameMime string) error {
+			if frameMime != mime {
+				return nil
+			}
+			return fn(seq, payload, frameMime)
+		})
+		if err != nil {
+			return fmt.Errorf("error export frames: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,101 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/jackc/pgx/v5"
+
+	v1 "stream-server/api/v1"
+	conf "stream-server/config"
+	dbrepo "stream-server/internal/data/repo"
+	"stream-server/internal/interfaces"
+)
+
+func TestStreamUsecase_ExportFrames(t *testing.T) {
+	repo := &stubRepo{batches: [][]dbrepo.InsertFramesParams{{
+		{Sequence: 0, Payload: []byte{0}, MimeType: "image/jpeg"},
+		{Sequence: 1, Payload: []byte{1}, MimeType: "image/jpeg"},
+		{Sequence: 2, Payload: []byte{2}, MimeType: "image/png"},
+	}}}
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	id := "84a1c6a6-96ee-4d7b-94a9-0f3fbb29e7a1"
+
+	var got []int64
+	collect := func(seq int64, _ []byte, _ string) error {
+		got = append(got, seq)
+		return nil
+	}
+	if err := uc.ExportFrames(context.Background(), id, 1, 5, collect); err != nil || len(got) != 2 || got[0] != 1 {
+		t.Fatalf("got %v err=%v", got, err)
+	}
+	if repo.snapshots != 1 {
+		t.Fatalf("expected stream check and scan in one snapshot, got %d", repo.snapshots)
+	}
+
+	// неверный диапазон и id — до БД
+	for _, r := range [][2]int64{{-1, 2}, {3, 2}} {
+		if err := uc.ExportFrames(context.Background(), id, r[0], r[1], collect); !errors.Is(err, ErrBadRange) || !v1.IsInvalidArgument(err) {
+			t.Fatalf("%v: expected ErrBadRange, got %v", r, err)
+		}
+	}
+	if err := uc.ExportFrames(context.Background(), "nope", 0, 1, collect); !v1.IsInvalidArgument(err) {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+
+	// нет стрима — 404, а кадры не читаются
+	got = nil
+	repo.err = pgx.ErrNoRows
+	if err := uc.ExportFrames(context.Background(), id, 0, 5, collect); !v1.IsStreamNotFound(err) || got != nil {
+		t.Fatalf("expected not found before scan, got %v (%v)", err, got)
+	}
+}
+
+func TestStreamUsecase_ExportFrameRange(t *testing.T) {
+	repo := &stubRepo{batches: [][]dbrepo.InsertFramesParams{{
+		{Sequence: 0, Payload: []byte{0, 0}, MimeType: "image/jpeg"},
+		{Sequence: 1, Payload: []byte{1}, MimeType: "image/png"},
+		{Sequence: 2, Payload: []byte{2, 2, 2}, MimeType: "image/jpeg"},
+	}}}
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	id := "84a1c6a6-96ee-4d7b-94a9-0f3fbb29e7a1"
+
+	var (
+		fr  *interfaces.FrameRange
+		got []int64
+	)
+	prepare := func(r *interfaces.FrameRange) error {
+		fr = r
+		return nil
+	}
+	collect := func(seq int64, _ []byte, _ string) error {
+		if fr == nil {
+			t.Fatal("frame before prepare")
+		}
+		got = append(got, seq)
+		return nil
+	}
+	err := uc.ExportFrameRange(context.Background(), id, 0, 10, "image/jpeg", prepare, collect)
+	if err != nil || fr.Frames != 2 || fr.Bytes != 5 || fr.OddFrames != 1 || fr.LastSeq != 2 {
+		t.Fatalf("unexpected range %+v err=%v", fr, err)
+	}
+	if len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Fatalf("expected only jpeg frames from the summary, got %v", got)
+	}
+	// сводка и кадры — из одного снимка
+	if repo.snapshots != 1 {
+		t.Fatalf("expected one snapshot, got %d", repo.snapshots)
+	}
+
+	// отказ prepare — кадры не читаются
+	got = nil
+	stop := errors.New("stop")
+	if err = uc.ExportFrameRange(context.Background(), id, 0, 10, "image/jpeg", func(*interfaces.FrameRange) error { return stop }, collect); !errors.Is(err, stop) || got != nil {
+		t.Fatalf("expected prepare error before scan, got %v (%v)", err, got)
+	}
+	if err = uc.ExportFrameRange(context.Background(), id, 5, 1, "image/jpeg", prepare, collect); !errors.Is(err, ErrBadRange) {
+		t.Fatalf("expected ErrBadRange, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package biz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	v1 "stream-server/api/v1"
+	session_pool "stream-server/internal/biz/session"
+	"stream-server/internal/biz/session/store_pool"
+	"stream-server/internal/converters"
+	"stream-server/internal/data/repo"
+	"stream-server/internal/interfaces"
+	"stream-server/pkg/avi"
+	"stream-server/pkg/jpegstream"
+)
+
+// Форматы импорта
+const (
+	ImportAuto  = "auto"  // по сигнатуре файла
+	ImportAVI   = "avi"   // Motion-JPEG AVI
+	ImportMJPEG = "mjpeg" // JPEG-кадры подряд
+)
+
+var (
+	// ErrBadImport файл импорта не разобран или в нём нет кадров
+	ErrBadImport = errors.New("bad import file")
+	// ErrBadImportFormat неизвестный формат импорта
+	ErrBadImportFormat = errors.New("import format must be auto, avi or mjpeg")
+)
+
+// importMime — оба формата несут только JPEG
+const importMime = "image/jpeg"
+
+// OpenImport — источник кадров из файла; interval — интервал кадров из заголовка AVI (0 — в файле его нет)
+// Файл читается потоково: в памяти только текущий кадр
+func OpenImport(r io.Reader, format string) (src interfaces.FrameSource, interval time.Duration, err error) {
+	br := bufio.NewReader(r)
+	if format == "" || format == ImportAuto {
+		format = ImportMJPEG
+		if sig, _ := br.Peek(12); len(sig) == 12 && bytes.Equal(sig[:4], []byte("RIFF")) && bytes.Equal(sig[8:], []byte("AVI ")) {
+			format = ImportAVI
+		}
+	}
+
+	switch format {
+	case ImportAVI:
+		ar, err := avi.NewReader(br, store_pool.MaxFrameBytes)
+		if err != nil {
+			return nil, 0, importError(err)
+		}
+		return &aviFrameSource{r: ar}, ar.FrameInterval(), nil
+	case ImportMJPEG:
+		return &mjpegFrameSource{s: jpegstream.NewScanner(br, store_pool.MaxFrameBytes)}, 0, nil
+	}
+	return nil, 0, fmt.Errorf("%w: %q", ErrBadImportFormat, format)
+}
+
+// ImportInterval — frame_interval_ms нового стрима: заданный явно, иначе из файла (с округлением), иначе 25fps
+func ImportInterval(explicit int32, detected time.Duration) int32 {
+	if explicit != 0 {
+		return explicit
+	}
+	if ms := detected.Round(time.Millisecond).Milliseconds(); ms > 0 && ms <= math.MaxInt32 {
+		return int32(ms)
+	}
+	return int32(session_pool.DefaultInterval.Milliseconds())
+}
+
+type aviFrameSource struct {
+	r *avi.Reader
+}
+
+func (a *aviFrameSource) Next() ([]byte, string, error) {
+	frame, err := a.r.Next()
+	if err != nil {
+		return nil, "", importError(err)
+	}
+	// в AVI видеочанки '##dc' бывают любого кодека: берём только Motion-JPEG
+	if !bytes.HasPrefix(frame, []byte{0xFF, 0xD8}) {
+		return nil, "", fmt.Errorf("%w: AVI video is not Motion-JPEG", ErrBadImport)
+	}
+	return frame, importMime, nil
+}
+
+type mjpegFrameSource struct {
+	s *jpegstream.Scanner
+}
+
+func (m *mjpegFrameSource) Next() ([]byte, string, error) {
+	frame, err := m.s.Next()
+	if err != nil {
+		return nil, "", importError(err)
+	}
+	return frame, importMime, nil
+}
+
+// importError — ошибки разбора файла → ErrBadImport/ErrFrameTooLarge; io.EOF и ошибки чтения — как есть
+func importError(err error) error {
+	switch {
+	case errors.Is(err, io.EOF):
+		return err
+	case errors.Is(err, avi.ErrFrameTooLarge), errors.Is(err, jpegstream.ErrFrameTooLarge):
+		return fmt.Errorf("%w: %v", ErrFrameTooLarge, err)
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, avi.ErrNotAVI), errors.Is(err, avi.ErrNoVideo), errors.Is(err, avi.ErrMalformed),
+		errors.Is(err, jpegstream.ErrTruncated), errors.Is(err, jpegstream.ErrMalformed):
+		return fmt.Errorf("%w: %v", ErrBadImport, err)
+	}
+	return err
+}
+
+// ImportStream — новый стрим из файла: создаёт стрим по in и загружает в него все кадры src
+// Импорт — всё или ничего: стрим и кадры пишутся одной транзакцией, поэтому до конца файла стрим нигде не виден,
+// а при ошибке (в том числе пустом файле или обрыве загрузки) от него ничего не остаётся
+func (u *StreamUsecase) ImportStream(ctx context.Context, in *v1.CreateStreamRequest, src interfaces.FrameSource) (res *v1.Stream, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	if err = in.Validate(); err != nil {
+		return nil, v1.ErrorInvalidArgument("%v", err).WithCause(err)
+	}
+
+	batchSize := u.cfg.Ingest.BatchFrames
+	if batchSize <= 0 {
+		batchSize = defaultIngestBatch
+	}
+
+	// next — очередная пачка проверенных кадров src; первый кадр запоминаем для постера
+	var (
+		count      int64
+		firstFrame []byte
+	)
+	next := func() ([]repo.InsertFramesParams, error) {
+		batch := make([]repo.InsertFramesParams, 0, batchSize)
+		for len(batch) < batchSize {
+			payload, mime, err := src.Next()
+			switch {
+			case errors.Is(err, io.EOF):
+				if count+int64(len(batch)) == 0 {
+					return nil, fmt.Errorf("%w: no frames found", ErrBadImport)
+				}
+				count += int64(len(batch))
+				return batch, io.EOF
+			case err != nil:
+				return nil, fmt.Errorf("error read frame %d: %w", count+int64(len(batch)), err)
+			}
+			if err = ValidateFrame(payload, mime); err != nil {
+				return nil, fmt.Errorf("frame %d rejected: %w", count+int64(len(batch)), err)
+			}
+			if firstFrame == nil {
+				firstFrame = payload
+			}
+			batch = append(batch, repo.InsertFramesParams{Payload: payload, MimeType: mime})
+		}
+		count += int64(len(batch))
+		return batch, nil
+	}
+
+	stream, err := u.repo.ImportStream(ctx, converters.ToDbCreateStreamParams(in), next)
+	if err != nil {
+		return nil, fmt.Errorf("error import stream: %w", err)
+	}
+	// постер — уже после коммита: его ошибка импорт не отменяет
+	u.savePoster(ctx, stream.ID, firstFrame)
+
+	return u.GetStream(ctx, &v1.GetStreamRequest{Id: stream.ID.String()})
+}
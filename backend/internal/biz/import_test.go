@@ -0,0 +1,173 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	v1 "stream-server/api/v1"
+	"stream-server/pkg/avi"
+)
+
+var (
+	jpegA = []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02, 1, 2, 0xFF, 0xD9}
+	jpegB = []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02, 3, 0xFF, 0x00, 0xFF, 0xD9}
+)
+
+func aviFile(t *testing.T, intervalMS int, frames ...[]byte) []byte {
+	t.Helper()
+	l := avi.Layout{Width: 8, Height: 8, IntervalMS: intervalMS, Frames: int64(len(frames))}
+	for _, f := range frames {
+		l.Bytes += int64(len(f))
+		l.OddFrames += int64(len(f) % 2)
+	}
+	var buf bytes.Buffer
+	w, err := avi.NewWriter(&buf, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range frames {
+		if err = w.WriteFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func drain(t *testing.T, src interface {
+	Next() ([]byte, string, error)
+}) ([][]byte, error) {
+	t.Helper()
+	var res [][]byte
+	for {
+		f, mime, err := src.Next()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+		if err != nil {
+			return res, err
+		}
+		if mime != "image/jpeg" {
+			t.Fatalf("mime %q", mime)
+		}
+		res = append(res, f)
+	}
+}
+
+func TestOpenImport(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     []byte
+		format   string
+		interval time.Duration
+		frames   int
+		wantErr  error
+	}{
+		{"avi auto", aviFile(t, 33, jpegA, jpegB), "", 33 * time.Millisecond, 2, nil},
+		{"raw auto", append(append([]byte("junk"), jpegA...), jpegB...), ImportAuto, 0, 2, nil},
+		{"raw forced", append(append([]byte{}, jpegA...), jpegB...), ImportMJPEG, 0, 2, nil},
+		{"avi forced on raw", jpegA, ImportAVI, 0, 0, ErrBadImport},
+		{"avi not mjpeg", aviFile(t, 40, []byte{0, 0, 0, 1, 0x67}), "", 40 * time.Millisecond, 0, ErrBadImport},
+		{"raw truncated", jpegA[:7], "", 0, 0, ErrBadImport},
+		{"unknown format", jpegA, "gif", 0, 0, ErrBadImportFormat},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			src, interval, err := OpenImport(bytes.NewReader(tc.body), tc.format)
+			var frames [][]byte
+			if err == nil {
+				if interval != tc.interval {
+					t.Fatalf("interval %v, want %v", interval, tc.interval)
+				}
+				frames, err = drain(t, src)
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil && (len(frames) != tc.frames || !bytes.Equal(frames[0], jpegA)) {
+				t.Fatalf("got %d frames %v", len(frames), frames)
+			}
+		})
+	}
+}
+
+func TestImportInterval(t *testing.T) {
+	cases := []struct {
+		explicit int32
+		detected time.Duration
+		want     int32
+	}{
+		{100, 40 * time.Millisecond, 100},
+		{0, 33366 * time.Microsecond, 33}, // 29.97fps
+		{0, 0, 40},
+		{0, 200 * time.Microsecond, 40}, // округлилось в 0
+	}
+	for _, tc := range cases {
+		if got := ImportInterval(tc.explicit, tc.detected); got != tc.want {
+			t.Fatalf("ImportInterval(%d, %v) = %d, want %d", tc.explicit, tc.detected, got, tc.want)
+		}
+	}
+}
+
+func TestStreamUsecase_ImportStream(t *testing.T) {
+	id := pgtype.UUID{Bytes: [16]byte{1, 2, 3}, Valid: true}
+	in := &v1.CreateStreamRequest{Title: "cam", FrameIntervalMs: 40}
+
+	t.Run("ok", func(t *testing.T) {
+		repo := &stubRepo{}
+		repo.created.ID = id
+		uc := newIngestUsecase(repo, nil, 1)
+		src, _, _ := OpenImport(bytes.NewReader(append(append([]byte{}, jpegA...), jpegB...)), "")
+		if _, err := uc.ImportStream(context.Background(), in, src); err != nil {
+			t.Fatal(err)
+		}
+		if repo.created.Title != "cam" || len(repo.batches) != 2 || repo.deleted.Valid {
+			t.Fatalf("created %+v, %d batches, deleted %v", repo.created, len(repo.batches), repo.deleted)
+		}
+	})
+
+	t.Run("no frames leaves nothing", func(t *testing.T) {
+		repo := &stubRepo{}
+		uc := newIngestUsecase(repo, nil, 1)
+		src, _, _ := OpenImport(bytes.NewReader([]byte("nothing")), "")
+		_, err := uc.ImportStream(context.Background(), in, src)
+		if !errors.Is(err, ErrBadImport) || kerrors.Reason(err) != v1.ErrorReason_INVALID_ARGUMENT.String() {
+			t.Fatalf("err %v", err)
+		}
+		if repo.created.Title != "" || len(repo.batches) != 0 {
+			t.Fatalf("failed import must leave nothing: created %+v, %d batches", repo.created, len(repo.batches))
+		}
+	})
+
+	// битый кадр после уже прочитанных пачек откатывает весь импорт, а не оставляет половину стрима
+	t.Run("bad frame after stored batches leaves nothing", func(t *testing.T) {
+		repo := &stubRepo{}
+		uc := newIngestUsecase(repo, nil, 1)
+		src := &sliceSource{mime: "image/jpeg", frames: [][]byte{jpegA, jpegB, {}}}
+		_, err := uc.ImportStream(context.Background(), in, src)
+		if !errors.Is(err, ErrEmptyFrame) {
+			t.Fatalf("err %v", err)
+		}
+		if repo.created.Title != "" || len(repo.batches) != 0 || len(repo.posters) != 0 {
+			t.Fatalf("failed import must leave nothing: created %+v, %d batches", repo.created, len(repo.batches))
+		}
+	})
+
+	t.Run("bad request creates nothing", func(t *testing.T) {
+		repo := &stubRepo{}
+		uc := newIngestUsecase(repo, nil, 1)
+		_, err := uc.ImportStream(context.Background(), &v1.CreateStreamRequest{Description: "no title", FrameIntervalMs: 40}, &sliceSource{})
+		if kerrors.Reason(err) != v1.ErrorReason_INVALID_ARGUMENT.String() || repo.created.Description != "" {
+			t.Fatalf("err %v, created %+v", err, repo.created)
+		}
+	})
+}
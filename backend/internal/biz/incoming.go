@@ -7,22 +7,46 @@ import (
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type StreamUsecase struct {
-	repo interfaces.IRepo
-	log  *log.Helper
-	cfg  *conf.Config
+	repo  interfaces.IRepo
+	store *store_pool.ChunkStore
+	log   *log.Helper
+	cfg   *conf.Config
 }
 
-func NewStreamUsecase(repo interfaces.IRepo, l *log.Helper, cfg *conf.Config) *StreamUsecase {
+func NewStreamUsecase(repo interfaces.IRepo, store *store_pool.ChunkStore, l *log.Helper, cfg *conf.Config) *StreamUsecase {
 	return &StreamUsecase{
-		repo: repo,
-		log:  l,
-		cfg:  cfg,
+		repo:  repo,
+		store: store,
+		log:   l,
+		cfg:   cfg,
 	}
 }
 
-func NewStreamPoolStore(cfg *conf.Config, db *pgxpool.Pool) *store_pool.ChunkStore {
-	return store_pool.NewChunkStore(db, store_pool.Sizes, cfg.CacheCapBytes, cfg.ChunkFrames)
+// NewStreamPoolStore — кэш чанков по конфигу; cleanup дописывает и закрывает дисковый уровень, если он включён
+// Метрики кэша и сессий регистрируются на meter
+func NewStreamPoolStore(cfg *conf.Config, db *pgxpool.Pool, meter metric.Meter) (*store_pool.ChunkStore, func(), error) {
+	policy, err := store_pool.NewEvictionPolicy(cfg.CacheEviction, cfg.CacheCapBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	store := store_pool.NewChunkStore(db, store_pool.Sizes, cfg.CacheCapBytes, cfg.ChunkFrames)
+	store.SetEvictionPolicy(policy)
+	store.SetPrefetchAt(cfg.ChunkPrefetchAt)
+	if err = store.RegisterMetrics(meter); err != nil {
+		return nil, nil, err
+	}
+	if cfg.DiskCacheDir == "" {
+		return store, func() {}, nil
+	}
+
+	disk, err := store_pool.OpenDiskTier(cfg.DiskCacheDir, cfg.DiskCacheBytes, store_pool.DiskSegmentBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	store.SetDiskTier(disk)
+	return store, func() { _ = disk.Close() }, nil
 }
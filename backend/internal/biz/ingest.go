@@ -0,0 +1,154 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz/session/store_pool"
+	"stream-server/internal/converters"
+	"stream-server/internal/data/repo"
+	"stream-server/internal/interfaces"
+)
+
+// defaultIngestBatch кадров в одном COPY, если в конфиге не задано
+const defaultIngestBatch = 64
+
+// defaultIngestFlush сколько первый кадр неполной пачки ждёт записи, если в конфиге не задано
+const defaultIngestFlush = 200 * time.Millisecond
+
+var (
+	ErrEmptyFrame    = errors.New("empty frame")
+	ErrFrameTooLarge = fmt.Errorf("frame is larger than %d bytes", store_pool.MaxFrameBytes)
+	ErrFrameNotImage = errors.New("frame mime type is not an image")
+)
+
+// ValidateFrame проверяет кадр до записи в БД: пустые, слишком большие (их всё равно пропустит ChunkStore)
+// и не-картинки отклоняем
+func ValidateFrame(payload []byte, mime string) error {
+	switch {
+	case len(payload) == 0:
+		return ErrEmptyFrame
+	case len(payload) > store_pool.MaxFrameBytes:
+		return ErrFrameTooLarge
+	case !strings.HasPrefix(mime, "image/"):
+		return fmt.Errorf("%w: %q", ErrFrameNotImage, mime)
+	}
+	return nil
+}
+
+// IngestFrames читает кадры из src и пачками дописывает их в конец стрима
+// Каждая пачка — отдельная транзакция (COPY): при ошибке уже записанные пачки остаются в стриме,
+// а в ответе/ошибке видно, сколько кадров успели сохранить
+// Live-загрузка идёт медленнее, чем наполняется пачка: неполную пачку пишет таймер через Ingest.FlushMS
+// после её первого кадра, чтобы live-зрители не ждали следующих кадров (медленный источник мог бы держать их сколько угодно)
+// src читаем только в этой горутине и не после возврата: тело HTTP-запроса нельзя читать после ответа
+func (u *StreamUsecase) IngestFrames(ctx context.Context, streamID string, src interfaces.FrameSource) (res *v1.IngestFramesResponse, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	uuid, err := converters.StringToPgUUID(streamID)
+	if err != nil {
+		return nil, fmt.Errorf("error converting uuid: %w", err)
+	}
+
+	batchSize := u.cfg.Ingest.BatchFrames
+	if batchSize <= 0 {
+		batchSize = defaultIngestBatch
+	}
+
+	flushAfter := time.Duration(u.cfg.Ingest.FlushMS) * time.Millisecond
+	if flushAfter <= 0 {
+		flushAfter = defaultIngestFlush
+	}
+
+	res = &v1.IngestFramesResponse{StreamId: streamID, FirstSeq: -1, LastSeq: -1}
+	batch := make([]repo.InsertFramesParams, 0, batchSize)
+
+	// mu — пачка и res общие с таймером; flushErr — ошибка записи по таймеру, вернём её на следующем кадре
+	var (
+		mu       sync.Mutex
+		flushErr error
+		finished bool
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		first, err := u.repo.AppendFrames(ctx, uuid, batch)
+		if err != nil {
+			return fmt.Errorf("error append frames (stored %d): %w", res.Count, err)
+		}
+		if res.FirstSeq < 0 {
+			res.FirstSeq = int64(first)
+		}
+		// Первый кадр стрима — сразу делаем постер для списков
+		if first == 0 {
+			u.savePoster(ctx, uuid, batch[0].Payload)
+		}
+		res.LastSeq = int64(first) + int64(len(batch)) - 1
+		res.Count += int64(len(batch))
+		batch = batch[:0]
+
+		// Дописали хвост — неполный последний чанк в кэше устарел, а live-сессии ждут новые кадры
+		if u.store != nil {
+			u.store.FramesAppended(uuid.Bytes, res.LastSeq)
+		}
+		return nil
+	}
+
+	timer := time.AfterFunc(flushAfter, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !finished && flushErr == nil {
+			flushErr = flush()
+		}
+	})
+	timer.Stop()
+	// после возврата таймер уже ничего не пишет: недописанная при ошибке пачка так и не попадёт в стрим
+	defer func() {
+		mu.Lock()
+		finished = true
+		timer.Stop()
+		mu.Unlock()
+	}()
+
+	for {
+		payload, mime, err := src.Next()
+
+		mu.Lock()
+		switch {
+		case flushErr != nil:
+			err = flushErr
+		case errors.Is(err, io.EOF):
+			if err = flush(); err == nil {
+				mu.Unlock()
+				return res, nil
+			}
+		case err != nil:
+			err = fmt.Errorf("error read frame (stored %d): %w", res.Count, err)
+		default:
+			err = ValidateFrame(payload, mime)
+			if err != nil {
+				err = fmt.Errorf("frame %d rejected (stored %d): %w", res.Count+int64(len(batch)), res.Count, err)
+				break
+			}
+			batch = append(batch, repo.InsertFramesParams{Payload: payload, MimeType: mime})
+			if len(batch) == 1 {
+				timer.Reset(flushAfter)
+			}
+			if len(batch) >= batchSize {
+				timer.Stop()
+				err = flush()
+			}
+		}
+		mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package biz
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbrepo "stream-server/internal/data/repo"
+)
+
+const (
+	// DefaultPageSize / MaxPageSize размер страницы ListStreams
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+var (
+	ErrBadPageToken = errors.New("bad page token")
+	ErrBadOrderBy   = errors.New(`order_by must be "created_at desc" or "created_at asc"`)
+)
+
+// pageToken — позиция keyset-пагинации: последний стрим страницы + параметры, с которыми её получили
+// Токен непрозрачен для клиента; search и order_by сверяем, чтобы токен не применили к другому списку
+type pageToken struct {
+	OldestFirst bool   `json:"o,omitempty"`
+	Search      string `json:"q,omitempty"`
+	CreatedAt   int64  `json:"t"` // unix-микросекунды: точность timestamptz, иначе (created_at, id) не совпадёт
+	ID          string `json:"id"`
+}
+
+func (t pageToken) encode() string {
+	raw, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageToken(s string) (t pageToken, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, ErrBadPageToken
+	}
+	if err = json.Unmarshal(raw, &t); err != nil {
+		return t, ErrBadPageToken
+	}
+	if _, err = uuid.Parse(t.ID); err != nil {
+		return t, ErrBadPageToken
+	}
+	return t, nil
+}
+
+// parseOrderBy — направление сортировки по created_at (по умолчанию — новые первыми)
+func parseOrderBy(orderBy string) (oldestFirst bool, err error) {
+	switch strings.Join(strings.Fields(strings.ToLower(orderBy)), " ") {
+	case "", "created_at desc":
+		return false, nil
+	case "created_at", "created_at asc":
+		return true, nil
+	}
+	return false, ErrBadOrderBy
+}
+
+// searchPattern — подстрока для ILIKE; %, _ и \ в запросе ищутся буквально
+func searchPattern(search string) string {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return ""
+	}
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(search) + "%"
+}
+
+// listStreamsParams — параметры запроса страницы; лимит на один больше, чтобы понять, есть ли следующая
+func listStreamsParams(pageSize int32, search, token string, oldestFirst bool) (dbrepo.ListStreamsParams, int, error) {
+	size := int(pageSize)
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+
+	params := dbrepo.ListStreamsParams{
+		Pattern:   searchPattern(search),
+		PageLimit: int32(size + 1),
+	}
+	if token == "" {
+		return params, size, nil
+	}
+
+	t, err := decodePageToken(token)
+	if err != nil {
+		return params, 0, err
+	}
+	if t.OldestFirst != oldestFirst || t.Search != strings.TrimSpace(search) {
+		return params, 0, ErrBadPageToken
+	}
+	params.AfterCreatedAt = pgtype.Timestamptz{Time: time.UnixMicro(t.CreatedAt).UTC(), Valid: true}
+	params.AfterID = pgtype.UUID{Bytes: uuid.MustParse(t.ID), Valid: true}
+	return params, size, nil
+}
+
+// nextPageToken — токен после последнего стрима страницы
+func nextPageToken(last dbrepo.ListStreamsRow, search string, oldestFirst bool) string {
+	return pageToken{
+		OldestFirst: oldestFirst,
+		Search:      strings.TrimSpace(search),
+		CreatedAt:   last.CreatedAt.Time.UnixMicro(),
+		ID:          uuid.UUID(last.ID.Bytes).String(),
+	}.encode()
+}
@@ -0,0 +1,80 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "stream-server/api/v1"
+	dbrepo "stream-server/internal/data/repo"
+
+	conf "stream-server/config"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestStreamUsecase_ListStreams_Pages(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC)
+	var rows []dbrepo.ListStreamsRow
+	for i := 0; i < 3; i++ {
+		rows = append(rows, dbrepo.ListStreamsRow{
+			ID:        pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			CreatedAt: pgtype.Timestamptz{Time: created.Add(-time.Duration(i) * time.Minute), Valid: true},
+		})
+	}
+	repo := &stubRepo{rows: rows}
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+
+	got, next, err := uc.ListStreams(context.Background(), &v1.ListStreamsRequest{PageSize: 2, Search: "cam_1"})
+	if err != nil || len(got) != 2 || next == "" {
+		t.Fatalf("got %d streams, next=%q err=%v", len(got), next, err)
+	}
+	if p := repo.listed[0]; p.PageLimit != 3 || p.Pattern != `%cam\_1%` || p.AfterCreatedAt.Valid {
+		t.Fatalf("unexpected first page params: %#v", p)
+	}
+
+	// Следующая страница начинается после последнего стрима предыдущей
+	if _, _, err = uc.ListStreams(context.Background(), &v1.ListStreamsRequest{PageSize: 2, Search: "cam_1", PageToken: next}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	p := repo.listed[1]
+	if !p.AfterCreatedAt.Time.Equal(rows[1].CreatedAt.Time) || p.AfterID != rows[1].ID {
+		t.Fatalf("keyset must point at last row of previous page, got %v %v", p.AfterCreatedAt.Time, p.AfterID)
+	}
+
+	// Токен привязан к search и order_by
+	for _, in := range []*v1.ListStreamsRequest{
+		{PageToken: next, Search: "other"},
+		{PageToken: next, Search: "cam_1", OrderBy: "created_at asc"},
+		{PageToken: "garbage"},
+	} {
+		if _, _, err = uc.ListStreams(context.Background(), in); !errors.Is(err, ErrBadPageToken) {
+			t.Fatalf("%v: expected ErrBadPageToken, got %v", in, err)
+		}
+	}
+}
+
+func TestParseOrderBy(t *testing.T) {
+	cases := map[string]bool{"": false, "created_at desc": false, "CREATED_AT  DESC": false, "created_at": true, "created_at asc": true}
+	for in, want := range cases {
+		got, err := parseOrderBy(in)
+		if err != nil || got != want {
+			t.Fatalf("%q: got %v err=%v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseOrderBy("title"); !errors.Is(err, ErrBadOrderBy) {
+		t.Fatalf("expected ErrBadOrderBy, got %v", err)
+	}
+}
+
+func TestSearchPatternEscapesWildcards(t *testing.T) {
+	if got := searchPattern(`  50%_off\ `); got != `%50\%\_off\\%` {
+		t.Fatalf("got %q", got)
+	}
+	if got := searchPattern("  "); got != "" {
+		t.Fatalf("blank search must disable filter, got %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"stream-server/internal/converters"
+)
+
+const (
+	// PosterWidth ширина постера стрима в списках
+	PosterWidth = 320
+
+	// posterBackfillBatch стримов за один проход дозаполнения
+	posterBackfillBatch = 16
+)
+
+// makePoster — постер из первого кадра стрима
+// Кадр, который не удалось декодировать, даёт пустой постер: так он помечается и больше не перебирается
+func makePoster(payload []byte) []byte {
+	thumb, resized, err := Thumbnail(payload, PosterWidth)
+	switch {
+	case err != nil:
+		return []byte{}
+	case !resized:
+		return payload // и так маленький
+	}
+	return thumb
+}
+
+// savePoster — постер при загрузке первого кадра стрима; ошибка не должна ронять загрузку
+func (u *StreamUsecase) savePoster(ctx context.Context, streamID pgtype.UUID, firstFrame []byte) {
+	if err := u.repo.SetStreamThumbnail(ctx, streamID, makePoster(firstFrame)); err != nil {
+		u.log.Errorf("error save poster for stream %s: %v", streamID.String(), err)
+	}
+}
+
+// BackfillPosters — дозаполнить постеры стримов, загруженных до появления постеров
+// Идёт пачками, пока находятся стримы с кадрами, но без постера
+func (u *StreamUsecase) BackfillPosters(ctx context.Context) (filled int, err error) {
+	for {
+		rows, err := u.repo.ListStreamsWithoutThumbnail(ctx, posterBackfillBatch)
+		if err != nil {
+			return filled, fmt.Errorf("error list streams without poster: %w", err)
+		}
+		if len(rows) == 0 {
+			return filled, nil
+		}
+		for _, row := range rows {
+			if err = u.repo.SetStreamThumbnail(ctx, row.ID, makePoster(row.Payload)); err != nil {
+				return filled, fmt.Errorf("error save poster: %w", err)
+			}
+			filled++
+		}
+	}
+}
+
+// GetStreamThumbnail постер стрима и версия его кадров (постер пересобирается при правке кадров); пустой — постера нет
+func (u *StreamUsecase) GetStreamThumbnail(ctx context.Context, streamID string) (_ []byte, _ int64, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	uuid, err := converters.StringToPgUUID(streamID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error converting uuid: %w", err)
+	}
+
+	row, err := u.repo.GetStreamThumbnail(ctx, uuid)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error get thumbnail: %w", err)
+	}
+	return row.Thumbnail, row.FramesVersion, nil
+}
@@ -0,0 +1,63 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"testing"
+
+	"stream-server/internal/converters"
+	dbrepo "stream-server/internal/data/repo"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestStreamUsecase_IngestFrames_PosterFromFirstFrame(t *testing.T) {
+	repo := &stubRepo{}
+	uc := newIngestUsecase(repo, nil, 1)
+	id := uuid.NewString()
+
+	src := &sliceSource{mime: "image/jpeg", frames: [][]byte{testJPEG(t, 640, 320), testJPEG(t, 8, 8)}}
+	if _, err := uc.IngestFrames(context.Background(), id, src); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	pgID, _ := converters.StringToPgUUID(id)
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(repo.posters[pgID]))
+	if err != nil || cfg.Width != PosterWidth || cfg.Height != PosterWidth/2 {
+		t.Fatalf("poster %dx%d err=%v, want %dx%d", cfg.Width, cfg.Height, err, PosterWidth, PosterWidth/2)
+	}
+
+	// Дозапись в существующий стрим постер не трогает
+	repo.posters = nil
+	src = &sliceSource{mime: "image/jpeg", frames: [][]byte{testJPEG(t, 8, 8)}}
+	if _, err = uc.IngestFrames(context.Background(), id, src); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(repo.posters) != 0 {
+		t.Fatal("poster must be made only from the first frame")
+	}
+}
+
+func TestStreamUsecase_BackfillPosters(t *testing.T) {
+	var small, broken pgtype.UUID
+	_ = small.Scan("84a1c6a6-96ee-4d7b-94a9-0f3fbb29e7a1")
+	_ = broken.Scan("0c7c8d3e-1f53-4c43-9b3a-2f0b5a6c1d11")
+	frame := testJPEG(t, 16, 16)
+	repo := &stubRepo{noPosters: []dbrepo.ListStreamsWithoutThumbnailRow{
+		{ID: small, Payload: frame},
+		{ID: broken, Payload: []byte("not a jpeg")},
+	}}
+	uc := newIngestUsecase(repo, nil, 1)
+
+	filled, err := uc.BackfillPosters(context.Background())
+	if err != nil || filled != 2 {
+		t.Fatalf("filled=%d err=%v", filled, err)
+	}
+	if !bytes.Equal(repo.posters[small], frame) {
+		t.Fatal("small frame must be stored as is")
+	}
+	if p, ok := repo.posters[broken]; !ok || p == nil || len(p) != 0 {
+		t.Fatalf("undecodable frame must be marked with empty poster, got %v", p)
+	}
+}
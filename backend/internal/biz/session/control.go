@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"errors"
+	"time"
+)
+
+// controlQueue команд клиента ждут применения; при переполнении Submit блокирует reader
+const controlQueue = 8
+
+// CommandKind — что просит клиент
+type CommandKind int
+
+const (
+	CmdPause CommandKind = iota + 1
+	CmdResume
+	CmdSeek
+	CmdSetRate
+	CmdStop
+)
+
+// Command — команда управления воспроизведением
+// Done вызывается из горутины Run после применения (там же пишутся кадры, поэтому ответ можно писать в conn)
+// Err != nil — команда уже отклонена при разборе: Run только сообщит об ошибке через Done, соблюдая порядок
+type Command struct {
+	Kind CommandKind
+	Seek SeekTarget
+	Rate float64
+	Err  error
+	Done func(State, error)
+}
+
+// State — состояние сессии после применения команды
+type State struct {
+	Paused   bool
+	Seq      int64 // следующий кадр к отправке
+	Playback Playback
+}
+
+var errUnknownCommand = errors.New("unknown command")
+
+// Submit — поставить команду в очередь; безопасно вызывать из другой горутины (например, reader'а WS)
+func (s *StreamSession) Submit(cmd Command) error {
+	select {
+	case s.control <- cmd:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func (s *StreamSession) state() State {
+	return State{Paused: s.paused, Seq: s.cm.seq, Playback: s.playback}
+}
+
+// handleControl — применить накопившиеся команды; на паузе блокирует до resume/seek/stop или отмены ctx
+func (s *StreamSession) handleControl() (stop bool, err error) {
+	for {
+		if s.paused {
+			select {
+			case <-s.ctx.Done():
+				return true, s.ctx.Err()
+			case cmd := <-s.control:
+				if s.apply(cmd) {
+					return true, nil
+				}
+			}
+			continue
+		}
+		select {
+		case cmd := <-s.control:
+			if s.apply(cmd) {
+				return true, nil
+			}
+		default:
+			return false, nil
+		}
+	}
+}
+
+// apply — применить команду (только из горутины Run); true — клиент попросил остановиться
+// Пауза и смена скорости не должны давать "догонялок": шкалу переносим так,
+// чтобы уже пройденные слоты соответствовали текущему моменту
+func (s *StreamSession) apply(cmd Command) (stop bool) {
+	err := cmd.Err
+	if err == nil {
+		switch cmd.Kind {
+		case CmdPause:
+			s.paused = true
+		case CmdResume:
+			if s.paused {
+				s.paused = false
+				s.rebase()
+			}
+		case CmdSeek:
+			s.seekTo(cmd.Seek)
+		case CmdSetRate:
+			var p Playback
+			if p, err = NewPlayback(s.meta.IntervalMS, cmd.Rate, s.playback.MaxFPS); err == nil {
+				s.playback = p
+				s.interval = p.Interval
+				s.rebase()
+			}
+		case CmdStop:
+			stop = true
+			s.closed = CloseStopped
+		default:
+			err = errUnknownCommand
+		}
+	}
+	if cmd.Done != nil {
+		cmd.Done(s.state(), err)
+	}
+	return stop
+}
+
+// rebase — продолжить шкалу времени с текущего момента, не теряя счёт слотов
+func (s *StreamSession) rebase() {
+	s.base = time.Now().Add(-time.Duration(s.slots) * s.interval)
+}
@@ -0,0 +1,213 @@
+package httpapi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stream-server/internal/biz/session/store_pool"
+)
+
+// fakeWriter — вместо ws: запоминает отправленные кадры (первый байт кадра = seq) и их метаданные
+type fakeWriter struct {
+	mu    sync.Mutex
+	seqs  []int64
+	infos []FrameInfo
+	sent  chan int64
+}
+
+func newFakeWriter() *fakeWriter {
+	return &fakeWriter{sent: make(chan int64, 64)}
+}
+
+func (c *fakeWriter) WriteFrame(f store_pool.Frame, info FrameInfo) error {
+	c.mu.Lock()
+	c.seqs = append(c.seqs, int64(f.Data[0]))
+	c.infos = append(c.infos, info)
+	c.mu.Unlock()
+	c.sent <- int64(f.Data[0])
+	return nil
+}
+
+func (c *fakeWriter) written() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64(nil), c.seqs...)
+}
+
+// controlSession — сессия над закэшированными кадрами 0..n-1 с интервалом слота interval
+func controlSession(t *testing.T, n int64, interval time.Duration) (*StreamSession, *fakeWriter, context.CancelFunc) {
+	t.Helper()
+	cs := newStore(1<<20, 4)
+	stream := uuid.New()
+	for idx := int64(0); idx*4 < n; idx++ {
+		ch := &store_pool.Chunk{StartSeq: idx * 4}
+		for seq := idx * 4; seq < (idx+1)*4 && seq < n; seq++ {
+			ch.Frames = append(ch.Frames, store_pool.Frame{Seq: seq, Data: []byte{byte(seq)}})
+			ch.BytesLen++
+			ch.BytesCap++
+		}
+		store_pool.InjectChunkForTest(store_pool.ChunkKey{Stream: stream, Index: idx}, ch, cs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := newFakeWriter()
+	s := NewStreamSession(ctx, conn, cs, store_pool.StreamMeta{ID: stream, IntervalMS: 40, MinSeq: 0, MaxSeq: n - 1}, stream)
+	p, err := NewPlayback(40, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Interval = interval
+	s.SetPlayback(p)
+	return s, conn, cancel
+}
+
+// submitWait — отправить команду и дождаться, пока Run её применит
+func submitWait(t *testing.T, s *StreamSession, cmd Command) (State, error) {
+	t.Helper()
+	type result struct {
+		st  State
+		err error
+	}
+	done := make(chan result, 1)
+	cmd.Done = func(st State, err error) { done <- result{st, err} }
+	if err := s.Submit(cmd); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case r := <-done:
+		return r.st, r.err
+	case <-time.After(2 * time.Second):
+		t.Fatalf("command %v was not applied", cmd.Kind)
+		return State{}, nil
+	}
+}
+
+func TestStreamSessionPauseResumeKeepsTimeline(t *testing.T) {
+	s, conn, cancel := controlSession(t, 12, 20*time.Millisecond)
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- s.Run() }()
+
+	<-conn.sent
+	st, err := submitWait(t, s, Command{Kind: CmdPause})
+	if err != nil || !st.Paused {
+		t.Fatalf("pause: state=%+v err=%v", st, err)
+	}
+	paused := len(conn.written())
+	time.Sleep(100 * time.Millisecond) // за это время прошло бы ~5 слотов
+	if got := len(conn.written()); got != paused {
+		t.Fatalf("frames sent while paused: %d -> %d", paused, got)
+	}
+
+	if st, err = submitWait(t, s, Command{Kind: CmdResume}); err != nil || st.Paused {
+		t.Fatalf("resume: state=%+v err=%v", st, err)
+	}
+	if err = <-runErr; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	// после resume шкала перенесена: догонять скипами нечего, первый кадр после паузы — следующий по порядку
+	// (дальше под нагрузкой планировщик может пропустить слот — это обычное догоняние, не про паузу)
+	got := conn.written()
+	if len(got) <= paused || got[paused] != got[paused-1]+1 {
+		t.Fatalf("frames skipped around pause (paused after %d): %v", paused, got)
+	}
+	if got[len(got)-1] != 11 {
+		t.Fatalf("stream not finished: %v", got)
+	}
+}
+
+func TestStreamSessionSetRate(t *testing.T) {
+	s, _, cancel := controlSession(t, 4, time.Hour)
+	defer cancel()
+	go func() { _ = s.Run() }()
+
+	st, err := submitWait(t, s, Command{Kind: CmdSetRate, Rate: 2})
+	if err != nil || st.Playback.Rate != 2 || st.Playback.Interval != 20*time.Millisecond {
+		t.Fatalf("set_rate 2: playback=%+v err=%v", st.Playback, err)
+	}
+
+	// недопустимая скорость отклоняется, текущая остаётся
+	st, err = submitWait(t, s, Command{Kind: CmdSetRate, Rate: 100})
+	if err == nil || st.Playback.Rate != 2 {
+		t.Fatalf("set_rate 100: playback=%+v err=%v", st.Playback, err)
+	}
+}
+
+func TestStreamSessionStopAndRejectedCommands(t *testing.T) {
+	s, _, cancel := controlSession(t, 4, time.Hour)
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- s.Run() }()
+
+	if _, err := submitWait(t, s, Command{Err: errUnknownCommand}); err != errUnknownCommand {
+		t.Fatalf("rejected command: err=%v", err)
+	}
+	if _, err := submitWait(t, s, Command{Kind: CmdSeek, Seek: SeekTarget{Seq: 3}}); err != nil {
+		t.Fatalf("seek: %v", err)
+	}
+	if _, err := submitWait(t, s, Command{Kind: CmdStop}); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Fatalf("stop must end the session cleanly, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("session did not stop")
+	}
+}
+
+func TestStreamSessionReportsSkippedFrames(t *testing.T) {
+	s, conn, cancel := controlSession(t, 8, 10*time.Millisecond)
+	defer cancel()
+	s.base = time.Now().Add(-35 * time.Millisecond) // опоздали на 3 слота
+
+	go func() { _ = s.Run() }()
+	if seq := <-conn.sent; seq != 3 {
+		t.Fatalf("first sent seq=%d, want 3 after catching up", seq)
+	}
+	<-conn.sent
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if got := conn.infos[0]; got.Skipped != 3 || got.TimestampMS != 3*40 {
+		t.Fatalf("first frame info %+v, want skipped=3 timestamp=120", got)
+	}
+	if got := conn.infos[1]; got.Skipped != 0 || got.TimestampMS != 4*40 {
+		t.Fatalf("second frame info %+v, want skipped=0 timestamp=160", got)
+	}
+}
+
+func TestStreamSessionUnpacedDeliversAll(t *testing.T) {
+	// интервал слота час: с темпом успел бы уйти только первый кадр
+	s, conn, cancel := controlSession(t, 12, time.Hour)
+	defer cancel()
+	s.SetUnpaced()
+
+	done := make(chan error, 1)
+	go func() { done <- s.Run() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("unpaced session must not wait for slots")
+	}
+
+	got := conn.written()
+	if len(got) != 12 {
+		t.Fatalf("expected all 12 frames, got %v", got)
+	}
+	for i, seq := range got {
+		if seq != int64(i) || conn.infos[i].Skipped != 0 {
+			t.Fatalf("frames must go in order without skips: %v %+v", got, conn.infos)
+		}
+	}
+}
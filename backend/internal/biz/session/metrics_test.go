@@ -0,0 +1,129 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"stream-server/internal/biz/session/store_pool"
+)
+
+// sumInt64 — сумма точек int64-счётчика name (по всем атрибутам) и число точек с ненулевым значением
+func sumInt64(t *testing.T, reader *sdkmetric.ManualReader, name string) (total int64, nonzero int) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == name {
+				for _, p := range sum.DataPoints {
+					total += p.Value
+					if p.Value != 0 {
+						nonzero++
+					}
+				}
+			}
+		}
+	}
+	return total, nonzero
+}
+
+func registerTestMetrics(t *testing.T, s *StreamSession) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	if err := s.store.RegisterMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")); err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+// slowWriter — клиент, который принимает кадр дольше слота: сессия догоняет шкалу скипами
+type slowWriter struct {
+	delay time.Duration
+	fail  bool
+}
+
+func (w slowWriter) WriteFrame(store_pool.Frame, FrameInfo) error {
+	if w.fail {
+		return errors.New("broken pipe")
+	}
+	time.Sleep(w.delay)
+	return nil
+}
+
+func TestStreamSessionMetrics(t *testing.T) {
+	s, _, cancel := controlSession(t, 12, time.Millisecond)
+	defer cancel()
+	s.out = slowWriter{delay: 5 * time.Millisecond}
+	reader := registerTestMetrics(t, s)
+
+	// пока сессия идёт, она видна в активных по своему стриму
+	s.out = &activeProbe{t: t, reader: reader, next: s.out}
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	delivered, _ := sumInt64(t, reader, "stream_frames_delivered")
+	skipped, _ := sumInt64(t, reader, "stream_frames_skipped")
+	if skipped == 0 || delivered+skipped != 12 {
+		t.Fatalf("expected delivered+skipped = 12 with skips, got %d+%d", delivered, skipped)
+	}
+	if active, _ := sumInt64(t, reader, "stream_sessions_active"); active != 0 {
+		t.Fatalf("finished session must leave active sessions, got %d", active)
+	}
+}
+
+// activeProbe — на первом кадре проверяет, что сессия учтена в stream_sessions_active с её stream_id
+type activeProbe struct {
+	t       *testing.T
+	reader  *sdkmetric.ManualReader
+	next    FrameWriter
+	checked bool
+}
+
+func (p *activeProbe) WriteFrame(f store_pool.Frame, info FrameInfo) error {
+	if !p.checked {
+		p.checked = true
+		var rm metricdata.ResourceMetrics
+		_ = p.reader.Collect(context.Background(), &rm)
+		found := false
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "stream_sessions_active" {
+					for _, dp := range sum.DataPoints {
+						_, hasStream := dp.Attributes.Value(attribute.Key("stream_id"))
+						found = found || (dp.Value == 1 && hasStream)
+					}
+				}
+			}
+		}
+		if !found {
+			p.t.Errorf("running session is not reported as active")
+		}
+	}
+	return p.next.WriteFrame(f, info)
+}
+
+func TestStreamSessionMetricsWriteError(t *testing.T) {
+	s, _, cancel := controlSession(t, 4, time.Millisecond)
+	defer cancel()
+	s.out = slowWriter{fail: true}
+	reader := registerTestMetrics(t, s)
+
+	if err := s.Run(); err == nil {
+		t.Fatal("expected write error")
+	}
+	if n, _ := sumInt64(t, reader, "stream_session_write_errors"); n != 1 {
+		t.Fatalf("expected 1 write error, got %d", n)
+	}
+	if n, _ := sumInt64(t, reader, "stream_frames_delivered"); n != 0 {
+		t.Fatalf("failed write must not count as delivered, got %d", n)
+	}
+}
@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultInterval интервал слота, если у стрима не задан frame_interval_ms (25fps)
+	DefaultInterval = 40 * time.Millisecond
+
+	// MinRate / MaxRate допустимый множитель скорости, запрошенный клиентом
+	MinRate = 0.25
+	MaxRate = 8.0
+)
+
+// Playback эффективные параметры воспроизведения сессии
+type Playback struct {
+	StreamInterval time.Duration // интервал стрима (frame_interval_ms)
+	Interval       time.Duration // интервал слота после rate и лимита fps
+	Rate           float64       // фактический множитель: может быть меньше запрошенного из-за лимита fps
+	MaxFPS         int           // лимит fps, с которым считали Interval (нужен при смене rate на лету)
+}
+
+// FPS фактическая частота слотов
+func (p Playback) FPS() float64 {
+	return float64(time.Second) / float64(p.Interval)
+}
+
+// NewPlayback интервал слота = интервал стрима / rate, но не чаще maxFPS (0 — без лимита)
+func NewPlayback(intervalMS int32, rate float64, maxFPS int) (Playback, error) {
+	if math.IsNaN(rate) || rate < MinRate || rate > MaxRate {
+		return Playback{}, fmt.Errorf("rate must be within [%g, %g], got %g", MinRate, MaxRate, rate)
+	}
+
+	base := time.Duration(intervalMS) * time.Millisecond
+	if base <= 0 {
+		base = DefaultInterval
+	}
+
+	interval := time.Duration(float64(base) / rate)
+	if maxFPS > 0 {
+		if minInterval := time.Second / time.Duration(maxFPS); interval < minInterval {
+			interval = minInterval
+		}
+	}
+	if interval <= 0 {
+		interval = 1 // защита от деления на ноль в Run
+	}
+
+	return Playback{
+		StreamInterval: base,
+		Interval:       interval,
+		Rate:           float64(base) / float64(interval),
+		MaxFPS:         maxFPS,
+	}, nil
+}
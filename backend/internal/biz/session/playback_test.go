@@ -0,0 +1,53 @@
+package httpapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewPlayback(t *testing.T) {
+	cases := []struct {
+		name         string
+		intervalMS   int32
+		rate         float64
+		maxFPS       int
+		wantInterval time.Duration
+		wantRate     float64
+	}{
+		{"stream interval", 100, 1, 60, 100 * time.Millisecond, 1},
+		{"default interval", 0, 1, 60, DefaultInterval, 1},
+		{"double speed", 40, 2, 60, 20 * time.Millisecond, 2},
+		{"slow motion", 40, 0.25, 60, 160 * time.Millisecond, 0.25},
+		{"capped by max fps", 40, 8, 50, 20 * time.Millisecond, 2},
+		{"no fps cap", 40, 8, 0, 5 * time.Millisecond, 8},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewPlayback(tc.intervalMS, tc.rate, tc.maxFPS)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if p.Interval != tc.wantInterval || p.Rate != tc.wantRate {
+				t.Fatalf("got interval=%v rate=%v, want %v %v", p.Interval, p.Rate, tc.wantInterval, tc.wantRate)
+			}
+		})
+	}
+}
+
+func TestNewPlaybackRejectsRateOutOfRange(t *testing.T) {
+	for _, rate := range []float64{0, 0.1, 8.5, -1} {
+		if _, err := NewPlayback(40, rate, 60); err == nil {
+			t.Fatalf("expected error for rate %v", rate)
+		}
+	}
+}
+
+func TestNewStreamSessionUsesStreamInterval(t *testing.T) {
+	s := NewStreamSession(context.Background(), nil, newStore(1<<20, 2), storeMeta(100), uuid.New())
+	if s.interval != 100*time.Millisecond {
+		t.Fatalf("expected stream interval, got %v", s.interval)
+	}
+}
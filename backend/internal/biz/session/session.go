@@ -2,11 +2,14 @@ package httpapi
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"stream-server/internal/biz/session/store_pool"
 )
@@ -19,12 +22,13 @@ type ChunkManager struct {
 
 	chunk     *store_pool.Chunk // текущий чанк (держим refs)
 	pos       int               // позиция внутри текущего чанка
-	seq       int64             // следующая желаемая sequence (двигается строго вперёд)
+	seq       int64             // следующая желаемая sequence (двигается вперёд, назад — только через seek)
 	emptyRuns int               // подряд "пустых" попаданий по чанкам (для страховки)
+	prefetch  int64             // StartSeq чанка, для которого уже запрошен read-ahead (-1 — ни для какого)
 }
 
 func NewChunkManager(store *store_pool.ChunkStore, streamID uuid.UUID, meta store_pool.StreamMeta) *ChunkManager {
-	return &ChunkManager{store: store, streamID: streamID, meta: meta, seq: meta.MinSeq}
+	return &ChunkManager{store: store, streamID: streamID, meta: meta, seq: meta.MinSeq, prefetch: -1}
 }
 
 // get гарантирует кадр с sequence >= cm.seq (если существует)
@@ -58,9 +62,30 @@ func (cm *ChunkManager) get(ctx context.Context) (bool, store_pool.Frame) {
 		cm.pos = p
 		cm.emptyRuns = 0
 	}
+	cm.prefetchNext(ctx)
 	return true, cm.chunk.Frames[cm.pos]
 }
 
+// prefetchNext — курсор прошёл долю PrefetchAt текущего чанка: следующий грузим заранее в фоне,
+// чтобы переход через границу брал его из LRU, а не ждал БД (до LoadChunkTimeout) с потерей слотов
+// Запрашиваем один раз на чанк; за концом снимка (max_seq) не грузим
+func (cm *ChunkManager) prefetchNext(ctx context.Context) {
+	at := cm.store.PrefetchAt()
+	if at <= 0 {
+		return
+	}
+	n := cm.store.ChunkSize()
+	next := cm.chunk.StartSeq + n
+	if next > cm.meta.MaxSeq || next == cm.prefetch {
+		return
+	}
+	if float64(cm.chunk.Frames[cm.pos].Seq-cm.chunk.StartSeq) < at*float64(n) {
+		return
+	}
+	cm.prefetch = next
+	cm.store.Prefetch(ctx, cm.streamID, cm.meta.MinSeq, next)
+}
+
 // advance — сдвинуть курсор на следующий кадр, обновив seq
 func (cm *ChunkManager) advance() {
 	if cm.chunk == nil {
@@ -70,6 +95,25 @@ func (cm *ChunkManager) advance() {
 	cm.pos++
 }
 
+// seek — переставить курсор на seq (или ближайший следующий кадр)
+// Если seq внутри удерживаемого чанка — только сдвигаем pos; иначе отпускаем чанк,
+// и следующий get возьмёт нужный через GetChunk (из LRU без запроса в БД, если чанк закэширован)
+func (cm *ChunkManager) seek(seq int64) {
+	cm.seq = seq
+	cm.emptyRuns = 0
+	if cm.chunk != nil && len(cm.chunk.Frames) > 0 {
+		frames := cm.chunk.Frames
+		if seq >= frames[0].Seq && seq <= frames[len(frames)-1].Seq {
+			cm.pos = sort.Search(len(frames), func(i int) bool {
+				return frames[i].Seq >= seq
+			})
+			return
+		}
+	}
+	cm.release()
+	cm.pos = 0
+}
+
 // release — отпустить текущий чанк (refs--)
 func (cm *ChunkManager) release() {
 	if cm.chunk != nil {
@@ -78,50 +122,188 @@ func (cm *ChunkManager) release() {
 	}
 }
 
-// StreamSession временная шкала + отправка в ws
+// FrameWriter — куда сессия отправляет кадры (ws в нужном режиме кадрирования; в тестах — фейк)
+// Вызывается только из горутины Run; ошибка записи завершает сессию
+type FrameWriter interface {
+	WriteFrame(f store_pool.Frame, info FrameInfo) error
+}
+
+// FrameInfo метаданные отправки кадра
+type FrameInfo struct {
+	TimestampMS int64 // время стрима: (seq - min_seq) * frame_interval_ms
+	Skipped     int64 // пропущено кадров с предыдущей отправки (догоняли шкалу времени)
+}
+
+// StreamSession временная шкала + отправка кадров
 type StreamSession struct {
 	ctx       context.Context
-	conn      *websocket.Conn
+	out       FrameWriter
 	store     *store_pool.ChunkStore
 	meta      store_pool.StreamMeta
 	cm        *ChunkManager
 	base      time.Time     // старт времени воспроизведения
-	interval  time.Duration // интервал между кадрами (например, 40ms)
+	interval  time.Duration // интервал между слотами (frame_interval_ms стрима / rate)
 	slots     int64         // пройдено слотов по времени (скипы + отправки)
 	delivered int64         // реально отправлено кадров
+	skipped   int64         // пропущено кадров с последней отправки
+	dropped   int64         // пропущено кадров за всю сессию
+	closed    string        // причина завершения, известная в момент выхода (stop, write_error)
+
+	playback Playback     // текущая скорость (меняется командой set_rate)
+	paused   bool         // пауза: слоты не идут, кадры не шлём
+	unpaced  bool         // без темпа: кадры подряд, без скипов и ожидания слотов (пакетная обработка)
+	updates  <-chan int64 // live: свежий max_seq после дозаписи кадров (nil — VOD по снимку)
+	control  chan Command // команды клиента, применяются в Run между слотами
 }
 
-func NewStreamSession(ctx context.Context, conn *websocket.Conn, store *store_pool.ChunkStore, meta store_pool.StreamMeta, streamID uuid.UUID) *StreamSession {
+// Причины завершения сессии (атрибут close_reason её спана)
+const (
+	CloseEndOfStream = "end_of_stream" // кадры кончились (VOD) или стрим удалён (live)
+	CloseStopped     = "stop"          // клиент прислал stop
+	CloseClientGone  = "client_gone"   // контекст отменён: клиент закрыл соединение
+	CloseWriteError  = "write_error"   // кадр не записался (обрыв, таймаут)
+)
+
+// SeekTarget точка перехода: sequence кадра или время стрима (мс от первого кадра)
+type SeekTarget struct {
+	Seq  int64
+	Ms   int64
+	ByMs bool
+}
+
+// NewStreamSession по умолчанию играет с интервалом стрима (frame_interval_ms), скорость 1x
+func NewStreamSession(ctx context.Context, out FrameWriter, store *store_pool.ChunkStore, meta store_pool.StreamMeta, streamID uuid.UUID) *StreamSession {
+	interval := time.Duration(meta.IntervalMS) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	playback := Playback{StreamInterval: interval, Interval: interval, Rate: 1}
 	return &StreamSession{
-		ctx:   ctx,
-		conn:  conn,
-		store: store,
-		meta:  meta,
-		cm:    NewChunkManager(store, streamID, meta),
-		base:  time.Now(),
-		// в задании указано воспроизводить кадры с частотой 25fps
-		// но также можно использовать значение стрима, если использовать строку ниже
-		// p.s. при использовании значения стрима через его изменение можно задавать
-		// скорость воспроизведения чисто с фронта, меняя параметр интервала
-		//interval:  time.Duration(meta.IntervalMS) * time.Millisecond,
-		interval:  40 * time.Millisecond, // 25fps
+		ctx:       ctx,
+		out:       out,
+		store:     store,
+		meta:      meta,
+		cm:        NewChunkManager(store, streamID, meta),
+		base:      time.Now(),
+		interval:  interval,
 		slots:     0,
 		delivered: 0,
+		playback:  playback,
+		control:   make(chan Command, controlQueue),
+	}
+}
+
+// seekTo — применить переход (только из горутины Run)
+// Время стрима переводим в sequence по frame_interval_ms, цель зажимаем в [min_seq, max_seq]
+// Шкала времени начинается заново: после перехода ничего не "догоняем"
+func (s *StreamSession) seekTo(t SeekTarget) {
+	seq := t.Seq
+	if t.ByMs {
+		seq = s.meta.MinSeq + t.Ms/s.streamIntervalMS()
+	}
+	if seq > s.meta.MaxSeq {
+		seq = s.meta.MaxSeq
+	}
+	if seq < s.meta.MinSeq {
+		seq = s.meta.MinSeq
+	}
+
+	s.cm.seek(seq)
+	s.base = time.Now()
+	s.slots = 0
+	s.skipped = 0
+}
+
+// streamIntervalMS — frame_interval_ms стрима (не зависит от rate сессии)
+func (s *StreamSession) streamIntervalMS() int64 {
+	if s.meta.IntervalMS <= 0 {
+		return DefaultInterval.Milliseconds()
+	}
+	return int64(s.meta.IntervalMS)
+}
+
+// timestampMS — время кадра на шкале стрима (та же шкала, что у seek по ms)
+func (s *StreamSession) timestampMS(seq int64) int64 {
+	return (seq - s.meta.MinSeq) * s.streamIntervalMS()
+}
+
+// SetPlayback задать скорость воспроизведения (см. NewPlayback); вызывать до Run
+func (s *StreamSession) SetPlayback(p Playback) {
+	s.playback = p
+	s.interval = p.Interval
+}
+
+// SetUnpaced — отдавать кадры подряд так быстро, как их принимает out (темп задаёт клиент); вызывать до Run
+// Скипов нет: доставляются все кадры снимка. Ждём только дозаписи (live) и повторов после пустых попаданий
+func (s *StreamSession) SetUnpaced() {
+	s.unpaced = true
+}
+
+// FollowLive — live-режим: по концу снимка не завершаться, а ждать дозаписи кадров из updates
+// Канал обычно получают из ChunkStore.SubscribeAppends ДО LoadStreamMeta, чтобы не потерять дозапись между ними
+func (s *StreamSession) FollowLive(updates <-chan int64) {
+	s.updates = updates
+}
+
+// waitAppend — live: ждём, пока max_seq вырастет, и расширяем снимок meta
+// Пока ждали, слоты не шли: переносим начало шкалы времени, иначе новые кадры ушли бы в скип на "догонялках"
+// Закрытый канал (стрим удалён) — штатный конец данных. Команда клиента тоже прерывает ожидание
+func (s *StreamSession) waitAppend() (bool, error) {
+	// удерживаемый хвостовой чанк уже устарел (InvalidateTail) — отпускаем, чтобы он освободился
+	s.cm.release()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return false, s.ctx.Err()
+		case cmd := <-s.control:
+			if s.apply(cmd) {
+				return false, nil
+			}
+			return true, nil
+		case maxSeq, ok := <-s.updates:
+			if !ok {
+				return false, nil
+			}
+			if maxSeq <= s.meta.MaxSeq {
+				continue
+			}
+			s.meta.MaxSeq = maxSeq
+			s.cm.meta.MaxSeq = maxSeq
+			s.rebase()
+			return true, nil
+		}
 	}
 }
 
 // Run — главный цикл: догоняем временную шкалу скипами, затем в текущем слоте отправляем один кадр
 // Завершаемся по концу данных (seq > max_seq) или по ошибке/разрыву соединения
+// В live-режиме по концу данных не завершаемся, а ждём дозаписи (waitAppend)
 // Внимание: мы НЕ требуем "delivered == Count". Это сознательно, так как важно отсутствие запаздывания стрима
-func (s *StreamSession) Run() error {
+func (s *StreamSession) Run() (err error) {
 	defer s.cm.release()
+	defer func() { s.traceClose(err) }()
+	metrics := s.store.Metrics()
+	defer metrics.SessionStarted(s.cm.streamID)()
 
 	const emptyChunkGuard = 3 // страховка от редких "вакуумов" в конце
 
 	for {
+		// команды клиента применяем между слотами; на паузе ждём resume/seek/stop
+		if stop, err := s.handleControl(); stop {
+			return err
+		}
+
 		// конец данных
 		if s.cm.seq > s.meta.MaxSeq {
-			return nil
+			if s.updates == nil {
+				return nil
+			}
+			extended, err := s.waitAppend()
+			if !extended {
+				return err
+			}
+			continue
 		}
 
 		elapsed := time.Since(s.base)              // сколько слотов времени уже прошло на текущий момент
@@ -131,8 +313,8 @@ func (s *StreamSession) Run() error {
 			targetSlots = 0
 		}
 
-		// Догоняем временную шкалу скипами (без отправки)
-		for s.slots < targetSlots && s.cm.seq <= s.meta.MaxSeq {
+		// Догоняем временную шкалу скипами (без отправки); без темпа догонять нечего
+		for !s.unpaced && s.slots < targetSlots && s.cm.seq <= s.meta.MaxSeq {
 			ok, _ := s.cm.get(s.ctx)
 			if !ok {
 				if s.cm.emptyRuns >= emptyChunkGuard {
@@ -143,20 +325,27 @@ func (s *StreamSession) Run() error {
 			}
 			s.cm.advance()
 			s.slots++ // слот времени пропускаем
+			s.skipped++
+			s.dropped++
+			metrics.FramesSkipped(1)
 		}
 
 		// Текущий слот — пытаемся отправить один кадр (если он есть)
 		if s.cm.seq > s.meta.MaxSeq {
-			return nil
+			continue // конец данных: VOD завершится, live подождёт дозаписи
 		}
 		ok, f := s.cm.get(s.ctx)
 		if ok {
-			_ = s.conn.SetWriteDeadline(time.Now().Add(2 * time.Second)) // защита от медленных клиентов
-			if err := s.conn.WriteMessage(websocket.BinaryMessage, f.Data); err != nil {
+			info := FrameInfo{TimestampMS: s.timestampMS(f.Seq), Skipped: s.skipped}
+			if err := s.out.WriteFrame(f, info); err != nil {
+				metrics.WriteFailed()
+				s.closed = CloseWriteError
 				return err // клиент ушёл/таймаут
 			}
 			s.cm.advance()
 			s.delivered++
+			metrics.FrameDelivered()
+			s.skipped = 0
 		} else {
 			// Нечего отправлять в этот слот, такое возможно при больших дырках
 			if s.cm.emptyRuns >= emptyChunkGuard {
@@ -165,6 +354,14 @@ func (s *StreamSession) Run() error {
 		}
 		s.slots++ // слот времени завершён (либо скип, либо отправка)
 
+		if s.unpaced {
+			if ok {
+				continue
+			}
+			// кадра не нашлось — не крутимся вхолостую, ждём один интервал
+			s.rebase()
+		}
+
 		// Доспать до начала следующего слота (прерываемый контекстом)
 		nextSlotTime := s.base.Add(time.Duration(s.slots) * s.interval)
 		if d := time.Until(nextSlotTime); d > 0 {
@@ -173,8 +370,41 @@ func (s *StreamSession) Run() error {
 			case <-s.ctx.Done():
 				timer.Stop()
 				return s.ctx.Err()
+			case cmd := <-s.control:
+				timer.Stop()
+				if s.apply(cmd) {
+					return nil
+				}
 			case <-timer.C:
 			}
 		}
 	}
 }
+
+// traceClose — итоги сессии в спан из её контекста (спан WS-сессии; без него noop)
+func (s *StreamSession) traceClose(err error) {
+	span := trace.SpanFromContext(s.ctx)
+	if !span.IsRecording() {
+		return
+	}
+	reason := s.closed
+	switch {
+	case reason != "":
+	case err == nil:
+		reason = CloseEndOfStream
+	case errors.Is(err, context.Canceled):
+		reason = CloseClientGone
+	default:
+		reason = "error"
+	}
+	span.SetAttributes(
+		attribute.String("stream_id", s.cm.streamID.String()),
+		attribute.Int64("frames.delivered", s.delivered),
+		attribute.Int64("frames.skipped", s.dropped),
+		attribute.String("close_reason", reason),
+	)
+	if err != nil && reason != CloseClientGone {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
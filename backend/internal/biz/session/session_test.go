@@ -3,6 +3,7 @@ package httpapi
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -69,3 +70,220 @@ func TestChunkManagerSequentialAcrossChunks(t *testing.T) {
 		}
 	}
 }
+
+func TestStreamSessionWaitAppendExtendsSnapshot(t *testing.T) {
+	cs := newStore(1<<20, 2)
+	stream := uuid.New()
+	meta := store_pool.StreamMeta{ID: stream, IntervalMS: 40, MinSeq: 0, MaxSeq: 3}
+
+	s := NewStreamSession(context.Background(), nil, cs, meta, stream)
+	updates := make(chan int64, 1)
+	s.FollowLive(updates)
+	s.slots = 10
+
+	// старое значение (не больше снимка) пропускается, свежее — расширяет снимок
+	go func() {
+		updates <- 3
+		updates <- 7
+	}()
+	extended, err := s.waitAppend()
+	if err != nil || !extended {
+		t.Fatalf("expected extension, got extended=%v err=%v", extended, err)
+	}
+	if s.meta.MaxSeq != 7 || s.cm.meta.MaxSeq != 7 {
+		t.Fatalf("max seq not extended: session=%d cm=%d", s.meta.MaxSeq, s.cm.meta.MaxSeq)
+	}
+	// шкала перенесена: уже пройденные слоты соответствуют "сейчас", а не старту
+	if lag := time.Since(s.base) - time.Duration(s.slots)*s.interval; lag < 0 || lag > time.Second {
+		t.Fatalf("time base not rebased, lag=%v", lag)
+	}
+
+	close(updates)
+	if extended, err = s.waitAppend(); extended || err != nil {
+		t.Fatalf("closed updates must end the session cleanly, got extended=%v err=%v", extended, err)
+	}
+}
+
+func TestStreamSessionLiveStopsOnContextCancel(t *testing.T) {
+	cs := newStore(1<<20, 2)
+	stream := uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := NewStreamSession(ctx, nil, cs, store_pool.StreamMeta{ID: stream, IntervalMS: 40, MaxSeq: -1}, stream)
+	s.FollowLive(make(chan int64))
+	if err := s.Run(); err == nil {
+		t.Fatal("expected context error from waiting live session")
+	}
+}
+
+func storeMeta(intervalMS int32) store_pool.StreamMeta {
+	return store_pool.StreamMeta{IntervalMS: intervalMS, MaxSeq: -1}
+}
+
+// injectFrames кладёт в кэш чанк idx с кадрами seqs (по 1 байту)
+func injectFrames(cs *store_pool.ChunkStore, stream uuid.UUID, idx int64, seqs ...int64) {
+	ch := &store_pool.Chunk{StartSeq: seqs[0]}
+	for _, seq := range seqs {
+		ch.Frames = append(ch.Frames, store_pool.Frame{Seq: seq, Data: make([]byte, 1)})
+		ch.BytesLen++
+		ch.BytesCap++
+	}
+	store_pool.InjectChunkForTest(store_pool.ChunkKey{Stream: stream, Index: idx}, ch, cs)
+}
+
+func TestChunkManagerSeekUsesCachedChunks(t *testing.T) {
+	// db == nil: любой промах кэша упал бы, значит переходы обслуживаются из LRU
+	cs := newStore(1<<20, 2)
+	stream := uuid.New()
+	meta := store_pool.StreamMeta{ID: stream, IntervalMS: 40, MinSeq: 10, MaxSeq: 15}
+	injectFrames(cs, stream, 0, 10, 11)
+	injectFrames(cs, stream, 1, 12, 13)
+	injectFrames(cs, stream, 2, 14, 15)
+
+	cm := NewChunkManager(cs, stream, meta)
+	ctx := context.Background()
+	defer cm.release()
+
+	for _, tc := range []struct{ seek, want int64 }{
+		{14, 14}, // вперёд через чанк
+		{15, 15}, // внутри удерживаемого чанка
+		{11, 11}, // назад в другой чанк
+		{10, 10}, // назад внутри чанка
+	} {
+		cm.seek(tc.seek)
+		ok, f := cm.get(ctx)
+		if !ok || f.Seq != tc.want {
+			t.Fatalf("seek %d: got ok=%v seq=%d, want %d", tc.seek, ok, f.Seq, tc.want)
+		}
+	}
+
+	// после перехода чтение продолжается последовательно
+	cm.advance()
+	if ok, f := cm.get(ctx); !ok || f.Seq != 11 {
+		t.Fatalf("after seek: got ok=%v seq=%d, want 11", ok, f.Seq)
+	}
+}
+
+func TestStreamSessionSeekToResolvesAndClamps(t *testing.T) {
+	cs := newStore(1<<20, 2)
+	stream := uuid.New()
+	meta := store_pool.StreamMeta{ID: stream, IntervalMS: 40, MinSeq: 10, MaxSeq: 100}
+
+	s := NewStreamSession(context.Background(), nil, cs, meta, stream)
+	for _, tc := range []struct {
+		target SeekTarget
+		want   int64
+	}{
+		{SeekTarget{Seq: 50}, 50},
+		{SeekTarget{Seq: 3}, 10},
+		{SeekTarget{Seq: 500}, 100},
+		{SeekTarget{Ms: 1000, ByMs: true}, 35}, // 1000мс / 40мс = 25 кадров от min_seq
+		{SeekTarget{Ms: 1 << 40, ByMs: true}, 100},
+	} {
+		s.slots = 7
+		s.seekTo(tc.target)
+		if s.cm.seq != tc.want {
+			t.Fatalf("seek %+v: seq=%d, want %d", tc.target, s.cm.seq, tc.want)
+		}
+		if s.slots != 0 {
+			t.Fatalf("seek %+v: time base not reset", tc.target)
+		}
+	}
+}
+
+func TestStreamSessionSeekInterruptsLiveWait(t *testing.T) {
+	cs := newStore(1<<20, 2)
+	stream := uuid.New()
+	meta := store_pool.StreamMeta{ID: stream, IntervalMS: 40, MinSeq: 0, MaxSeq: 3}
+
+	s := NewStreamSession(context.Background(), nil, cs, meta, stream)
+	s.FollowLive(make(chan int64))
+	_ = s.Submit(Command{Kind: CmdSeek, Seek: SeekTarget{Seq: 1}})
+
+	resumed, err := s.waitAppend()
+	if err != nil || !resumed {
+		t.Fatalf("waitAppend: resumed=%v err=%v", resumed, err)
+	}
+	if s.cm.seq != 1 {
+		t.Fatalf("seq=%d, want 1", s.cm.seq)
+	}
+}
+
+func TestChunkManagerPrefetchAvoidsBoundaryStall(t *testing.T) {
+	const loadDelay = 200 * time.Millisecond
+	cs := newStore(1<<20, 4)
+	cs.SetPrefetchAt(0.5)
+	stream := uuid.New()
+	injectFrames(cs, stream, 0, 0, 1, 2, 3)
+
+	loaded := make(chan int64, 1)
+	store_pool.SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*store_pool.Chunk, error) {
+		time.Sleep(loadDelay) // медленная БД
+		ch := &store_pool.Chunk{StartSeq: startSeq}
+		for seq := startSeq; seq < startSeq+4; seq++ {
+			ch.Frames = append(ch.Frames, store_pool.Frame{Seq: seq, Data: make([]byte, 1)})
+			ch.BytesLen++
+			ch.BytesCap++
+		}
+		loaded <- startSeq
+		return ch, nil
+	})
+
+	cm := NewChunkManager(cs, stream, store_pool.StreamMeta{ID: stream, IntervalMS: 40, MinSeq: 0, MaxSeq: 7})
+	defer cm.release()
+	ctx := context.Background()
+	for seq := int64(0); seq < 4; seq++ {
+		if ok, f := cm.get(ctx); !ok || f.Seq != seq {
+			t.Fatalf("expected frame %d, got ok=%v %+v", seq, ok, f)
+		}
+		cm.advance()
+	}
+
+	// read-ahead стартовал на середине чанка 0; пока играются его кадры, следующий успевает загрузиться
+	select {
+	case start := <-loaded:
+		if start != 4 {
+			t.Fatalf("prefetched chunk %d, want 4", start)
+		}
+	case <-time.After(2 * loadDelay):
+		t.Fatalf("next chunk was not prefetched")
+	}
+	time.Sleep(10 * time.Millisecond) // загрузка вернулась, чанк кладётся в LRU
+
+	began := time.Now()
+	if ok, f := cm.get(ctx); !ok || f.Seq != 4 {
+		t.Fatalf("expected frame 4, got ok=%v %+v", ok, f)
+	}
+	if d := time.Since(began); d >= loadDelay/2 {
+		t.Fatalf("chunk boundary blocked for %v", d)
+	}
+	// за последним чанком снимка грузить нечего
+	for seq := int64(5); seq < 8; seq++ {
+		cm.advance()
+		cm.get(ctx)
+	}
+	select {
+	case start := <-loaded:
+		t.Fatalf("unexpected prefetch past max_seq: %d", start)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestChunkManagerPrefetchDisabled(t *testing.T) {
+	cs := newStore(1<<20, 4)
+	stream := uuid.New()
+	injectFrames(cs, stream, 0, 0, 1, 2, 3)
+	store_pool.SetLoaderForTest(cs, func(context.Context, uuid.UUID, int64) (*store_pool.Chunk, error) {
+		t.Error("unexpected load with prefetch disabled")
+		return nil, context.Canceled
+	})
+
+	cm := NewChunkManager(cs, stream, store_pool.StreamMeta{ID: stream, IntervalMS: 40, MaxSeq: 7})
+	defer cm.release()
+	for seq := int64(0); seq < 4; seq++ {
+		cm.get(context.Background())
+		cm.advance()
+	}
+	time.Sleep(20 * time.Millisecond)
+}
@@ -0,0 +1,70 @@
+package store_pool
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// appendBroadcaster — in-process рассылка "в стрим дописаны кадры" для live-сессий
+// Канал подписчика с буфером 1 хранит только самое свежее max_seq (latest wins),
+// поэтому медленный подписчик никогда не блокирует ingest
+type appendBroadcaster struct {
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan int64]struct{}
+}
+
+func newAppendBroadcaster() *appendBroadcaster {
+	return &appendBroadcaster{subs: make(map[uuid.UUID]map[chan int64]struct{})}
+}
+
+func (b *appendBroadcaster) subscribe(stream uuid.UUID) (<-chan int64, func()) {
+	ch := make(chan int64, 1)
+
+	b.mu.Lock()
+	set := b.subs[stream]
+	if set == nil {
+		set = make(map[chan int64]struct{})
+		b.subs[stream] = set
+	}
+	set[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// канал мог уже закрыть closeStream — тогда его нет в карте
+		if set := b.subs[stream]; set != nil {
+			if _, ok := set[ch]; ok {
+				delete(set, ch)
+				close(ch)
+			}
+			if len(set) == 0 {
+				delete(b.subs, stream)
+			}
+		}
+	}
+}
+
+func (b *appendBroadcaster) publish(stream uuid.UUID, maxSeq int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[stream] {
+		// выкидываем непрочитанное старое значение и кладём свежее
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- maxSeq
+	}
+}
+
+// closeStream — стрим удалён: закрываем каналы, live-сессии завершатся как по концу данных
+func (b *appendBroadcaster) closeStream(stream uuid.UUID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[stream] {
+		close(ch)
+	}
+	delete(b.subs, stream)
+}
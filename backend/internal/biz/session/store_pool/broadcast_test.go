@@ -0,0 +1,43 @@
+package store_pool
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFramesAppendedLatestWins(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	stream := uuid.New()
+
+	ch, cancel := cs.SubscribeAppends(stream)
+	defer cancel()
+	other, cancelOther := cs.SubscribeAppends(uuid.New())
+	defer cancelOther()
+
+	// Подписчик не читал — ingest не должен блокироваться, а в канале остаётся свежее значение
+	cs.FramesAppended(stream, 10)
+	cs.FramesAppended(stream, 20)
+
+	if got := <-ch; got != 20 {
+		t.Fatalf("expected latest max_seq 20, got %d", got)
+	}
+	select {
+	case v := <-other:
+		t.Fatalf("other stream must not be notified, got %d", v)
+	default:
+	}
+}
+
+func TestDropStreamClosesSubscriptions(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	stream := uuid.New()
+
+	ch, cancel := cs.SubscribeAppends(stream)
+	cs.DropStream(stream)
+
+	if _, ok := <-ch; ok {
+		t.Fatal("expected closed channel after DropStream")
+	}
+	cancel() // повторное закрытие не должно паниковать
+}
@@ -0,0 +1,457 @@
+package store_pool
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DiskSegmentBytes размер файла-сегмента дискового кэша по умолчанию
+const DiskSegmentBytes = 64 << 20
+
+// spillQueueLen сколько вытесненных чанков может ждать записи на диск; сверх — не пишем (это кэш)
+const spillQueueLen = 64
+
+// Запись сегмента: заголовок diskHeaderLen байт + тело (кадры чанка)
+//
+//	0  magic "MJC1"
+//	4  crc32c заголовка (байты 8..56)
+//	8  длина тела
+//	12 crc32c тела
+//	16 stream uuid
+//	32 index, 40 version стрима, 48 startSeq
+//
+// Тело: число кадров uint32, затем на кадр: seq int64, длина mime uint16, длина данных uint32, mime, данные
+const diskHeaderLen = 56
+
+var diskMagic = [4]byte{'M', 'J', 'C', '1'}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+var (
+	// ErrDiskChunkCorrupt запись чанка на диске не сошлась с контрольной суммой
+	ErrDiskChunkCorrupt = errors.New("disk chunk corrupt")
+)
+
+// diskLoc — где лежит чанк
+type diskLoc struct {
+	seg      *diskSegment
+	off      int64 // начало записи (заголовка)
+	bodyLen  int64
+	bodyCRC  uint32
+	version  int64
+	startSeq int64
+}
+
+type diskSegment struct {
+	id   uint64
+	f    *os.File
+	size int64 // зарезервировано под записи (конец файла после дописывания)
+}
+
+type spillItem struct {
+	key     ChunkKey
+	version int64
+	chunk   *Chunk
+	done    func(*Chunk) // отпустить чанк после записи (ReleaseChunk)
+}
+
+// DiskTier — второй уровень кэша чанков: вытесненные из RAM полные чанки дописываются в файлы-сегменты,
+// промах в RAM сначала ищется здесь и только потом идёт в БД
+// Сегменты только дописываются; при превышении бюджета целиком удаляется самый старый (FIFO по сегментам)
+// Индекс ChunkKey → смещение живёт в памяти и восстанавливается при старте сканированием заголовков;
+// оборванная при падении запись в конце сегмента отрезается. Тело проверяется crc32c при каждом чтении
+// Читаем pread'ом: кадры всё равно копируются в буферы пула, mmap не сэкономил бы копию
+type DiskTier struct {
+	dir      string
+	limitB   int64
+	segBytes int64
+
+	mu     sync.Mutex
+	index  map[ChunkKey]diskLoc
+	segs   []*diskSegment // от старых к новым; последний — активный
+	usedB  int64
+	nextID uint64
+
+	spillq chan spillItem
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// OpenDiskTier — открыть (создать) дисковый кэш в dir с бюджетом limitBytes; segmentBytes — размер файла-сегмента
+func OpenDiskTier(dir string, limitBytes, segmentBytes int64) (*DiskTier, error) {
+	if limitBytes <= 0 {
+		return nil, fmt.Errorf("disk cache: bad budget %d", limitBytes)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("disk cache: %w", err)
+	}
+	d := &DiskTier{
+		dir:      dir,
+		limitB:   limitBytes,
+		segBytes: max(min(segmentBytes, limitBytes/4), 1),
+		index:    make(map[ChunkKey]diskLoc),
+		spillq:   make(chan spillItem, spillQueueLen),
+	}
+	if err := d.scan(); err != nil {
+		_ = d.closeFiles()
+		return nil, err
+	}
+	d.mu.Lock()
+	d.enforceBudgetLocked()
+	d.mu.Unlock()
+
+	d.wg.Add(1)
+	go d.spillLoop()
+	return d, nil
+}
+
+// scan — восстановить индекс по сегментам (по возрастанию id: более поздняя запись ключа перекрывает раннюю)
+func (d *DiskTier) scan() error {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("disk cache: %w", err)
+	}
+	var ids []uint64
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".seg")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if id, err := strconv.ParseUint(name, 16, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		f, err := os.OpenFile(d.segPath(id), os.O_RDWR, 0o644)
+		if err != nil {
+			return fmt.Errorf("disk cache: %w", err)
+		}
+		seg := &diskSegment{id: id, f: f}
+		if err = d.scanSegment(seg); err != nil {
+			_ = f.Close()
+			return err
+		}
+		d.segs = append(d.segs, seg)
+		d.usedB += seg.size
+		d.nextID = id + 1
+	}
+	return nil
+}
+
+// scanSegment — пройти заголовки; на первой битой/оборванной записи сегмент обрезается (хвост недописанной записи)
+func (d *DiskTier) scanSegment(seg *diskSegment) error {
+	st, err := seg.f.Stat()
+	if err != nil {
+		return fmt.Errorf("disk cache: %w", err)
+	}
+	fileSize := st.Size()
+	var hdr [diskHeaderLen]byte
+	var off int64
+	for off < fileSize {
+		if _, err = seg.f.ReadAt(hdr[:], off); err != nil {
+			break
+		}
+		key, loc, ok := parseDiskHeader(hdr[:])
+		if !ok || off+diskHeaderLen+loc.bodyLen > fileSize {
+			break
+		}
+		loc.seg, loc.off = seg, off
+		d.index[key] = loc
+		off += diskHeaderLen + loc.bodyLen
+	}
+	if off < fileSize {
+		if err = seg.f.Truncate(off); err != nil {
+			return fmt.Errorf("disk cache: truncate torn segment: %w", err)
+		}
+	}
+	seg.size = off
+	return nil
+}
+
+func (d *DiskTier) segPath(id uint64) string {
+	return filepath.Join(d.dir, fmt.Sprintf("%016x.seg", id))
+}
+
+// Has — есть ли на диске чанк для этой версии стрима (тогда повторно не пишем)
+func (d *DiskTier) Has(key ChunkKey, version int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	loc, ok := d.index[key]
+	return ok && loc.version == version
+}
+
+// read — тело чанка key, если он записан для этой версии стрима с тем же startSeq
+// Битая запись забывается (ErrDiskChunkCorrupt), отсутствие — (nil, nil)
+func (d *DiskTier) read(key ChunkKey, version, startSeq int64) ([]byte, error) {
+	d.mu.Lock()
+	loc, ok := d.index[key]
+	if ok && (loc.version != version || loc.startSeq != startSeq) {
+		// стрим с тех пор правили (admin) — запись устарела
+		delete(d.index, key)
+		ok = false
+	}
+	d.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	body := make([]byte, loc.bodyLen)
+	if _, err := loc.seg.f.ReadAt(body, loc.off+diskHeaderLen); err != nil {
+		d.forget(key, loc)
+		return nil, err
+	}
+	if crc32.Checksum(body, castagnoli) != loc.bodyCRC {
+		d.forget(key, loc)
+		return nil, fmt.Errorf("%w: %s:%d", ErrDiskChunkCorrupt, key.Stream, key.Index)
+	}
+	return body, nil
+}
+
+// forget — снять ключ из индекса, если он всё ещё указывает на loc
+func (d *DiskTier) forget(key ChunkKey, loc diskLoc) {
+	d.mu.Lock()
+	if cur, ok := d.index[key]; ok && cur.seg == loc.seg && cur.off == loc.off {
+		delete(d.index, key)
+	}
+	d.mu.Unlock()
+}
+
+// DropStream — забыть чанки стрима (стрим удалён). Место освободится вместе с сегментом
+func (d *DiskTier) DropStream(stream uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key := range d.index {
+		if key.Stream == stream {
+			delete(d.index, key)
+		}
+	}
+}
+
+// spill — поставить чанк в очередь на запись; false — очередь полна или tier закрыт (done не вызывается)
+// Чанк должен оставаться валидным до done: вызывающий держит на него ссылку
+func (d *DiskTier) spill(key ChunkKey, version int64, chunk *Chunk, done func(*Chunk)) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return false
+	}
+	select {
+	case d.spillq <- spillItem{key: key, version: version, chunk: chunk, done: done}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *DiskTier) spillLoop() {
+	defer d.wg.Done()
+	for it := range d.spillq {
+		_ = d.write(it.key, it.version, it.chunk) // не записали — значит, в следующий раз чанк придёт из БД
+		it.done(it.chunk)
+	}
+}
+
+// write — дописать чанк в активный сегмент: место резервируется под мьютексом, сама запись — без него
+func (d *DiskTier) write(key ChunkKey, version int64, chunk *Chunk) error {
+	rec := encodeDiskChunk(key, version, chunk)
+
+	d.mu.Lock()
+	seg, err := d.activeLocked(int64(len(rec)))
+	if err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	off := seg.size
+	seg.size += int64(len(rec))
+	d.usedB += int64(len(rec))
+	d.mu.Unlock()
+
+	if _, err = seg.f.WriteAt(rec, off); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.hasSegmentLocked(seg) {
+		return nil // сегмент успели удалить по бюджету
+	}
+	_, loc, _ := parseDiskHeader(rec)
+	loc.seg, loc.off = seg, off
+	d.index[key] = loc
+	d.enforceBudgetLocked()
+	return nil
+}
+
+// activeLocked — сегмент, в который влезет ещё n байт; при необходимости открывает новый
+func (d *DiskTier) activeLocked(n int64) (*diskSegment, error) {
+	if len(d.segs) > 0 {
+		if seg := d.segs[len(d.segs)-1]; seg.size == 0 || seg.size+n <= d.segBytes {
+			return seg, nil
+		}
+	}
+	id := d.nextID
+	f, err := os.OpenFile(d.segPath(id), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	d.nextID++
+	seg := &diskSegment{id: id, f: f}
+	d.segs = append(d.segs, seg)
+	return seg, nil
+}
+
+func (d *DiskTier) hasSegmentLocked(seg *diskSegment) bool {
+	for _, s := range d.segs {
+		if s == seg {
+			return true
+		}
+	}
+	return false
+}
+
+// enforceBudgetLocked — удаляем самые старые сегменты, пока занято больше бюджета (активный не трогаем)
+func (d *DiskTier) enforceBudgetLocked() {
+	for d.usedB > d.limitB && len(d.segs) > 1 {
+		old := d.segs[0]
+		d.segs = d.segs[1:]
+		d.usedB -= old.size
+		for key, loc := range d.index {
+			if loc.seg == old {
+				delete(d.index, key)
+			}
+		}
+		// чтения, уже взявшие loc этого сегмента, получат ошибку и уйдут в БД
+		_ = old.f.Close()
+		_ = os.Remove(d.segPath(old.id))
+	}
+}
+
+// UsedBytes — занято на диске (сумма размеров сегментов)
+func (d *DiskTier) UsedBytes() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.usedB
+}
+
+// Close — дописать очередь и закрыть сегменты
+func (d *DiskTier) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	close(d.spillq)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	return d.closeFiles()
+}
+
+func (d *DiskTier) closeFiles() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var errs []error
+	for _, seg := range d.segs {
+		errs = append(errs, seg.f.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// -------- кодирование записи --------
+
+func encodeDiskChunk(key ChunkKey, version int64, chunk *Chunk) []byte {
+	bodyLen := 4
+	for _, f := range chunk.Frames {
+		bodyLen += 8 + 2 + 4 + len(f.Mime) + len(f.Data)
+	}
+	rec := make([]byte, diskHeaderLen+bodyLen)
+
+	body := rec[diskHeaderLen:]
+	binary.LittleEndian.PutUint32(body, uint32(len(chunk.Frames)))
+	p := 4
+	for _, f := range chunk.Frames {
+		binary.LittleEndian.PutUint64(body[p:], uint64(f.Seq))
+		binary.LittleEndian.PutUint16(body[p+8:], uint16(len(f.Mime)))
+		binary.LittleEndian.PutUint32(body[p+10:], uint32(len(f.Data)))
+		p += 14
+		p += copy(body[p:], f.Mime)
+		p += copy(body[p:], f.Data)
+	}
+
+	copy(rec, diskMagic[:])
+	binary.LittleEndian.PutUint32(rec[8:], uint32(bodyLen))
+	binary.LittleEndian.PutUint32(rec[12:], crc32.Checksum(body, castagnoli))
+	copy(rec[16:32], key.Stream[:])
+	binary.LittleEndian.PutUint64(rec[32:], uint64(key.Index))
+	binary.LittleEndian.PutUint64(rec[40:], uint64(version))
+	binary.LittleEndian.PutUint64(rec[48:], uint64(chunk.StartSeq))
+	binary.LittleEndian.PutUint32(rec[4:], crc32.Checksum(rec[8:diskHeaderLen], castagnoli))
+	return rec
+}
+
+func parseDiskHeader(hdr []byte) (ChunkKey, diskLoc, bool) {
+	if [4]byte(hdr[:4]) != diskMagic || binary.LittleEndian.Uint32(hdr[4:]) != crc32.Checksum(hdr[8:diskHeaderLen], castagnoli) {
+		return ChunkKey{}, diskLoc{}, false
+	}
+	key := ChunkKey{Stream: uuid.UUID(hdr[16:32]), Index: int64(binary.LittleEndian.Uint64(hdr[32:]))}
+	return key, diskLoc{
+		bodyLen:  int64(binary.LittleEndian.Uint32(hdr[8:])),
+		bodyCRC:  binary.LittleEndian.Uint32(hdr[12:]),
+		version:  int64(binary.LittleEndian.Uint64(hdr[40:])),
+		startSeq: int64(binary.LittleEndian.Uint64(hdr[48:])),
+	}, true
+}
+
+// decodeDiskChunk — кадры тела записи в буферы пула (как loadChunk из БД)
+func (cs *ChunkStore) decodeDiskChunk(body []byte, startSeq int64) (*Chunk, error) {
+	if len(body) < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	n := int(binary.LittleEndian.Uint32(body))
+	frames := cs.getFrameSlice()
+	var totalLen, totalCap int64
+	p := 4
+	for range n {
+		if p+14 > len(body) {
+			break
+		}
+		seq := int64(binary.LittleEndian.Uint64(body[p:]))
+		mimeLen := int(binary.LittleEndian.Uint16(body[p+8:]))
+		dataLen := int(binary.LittleEndian.Uint32(body[p+10:]))
+		p += 14
+		if p+mimeLen+dataLen > len(body) {
+			break
+		}
+		mime := string(body[p : p+mimeLen])
+		p += mimeLen
+		dst := cs.pool.Get(dataLen)
+		copy(dst, body[p:p+dataLen])
+		p += dataLen
+		frames = append(frames, Frame{Seq: seq, Data: dst[:dataLen], Mime: mime})
+		totalLen += int64(dataLen)
+		totalCap += int64(cap(dst))
+	}
+	if len(frames) != n {
+		// crc сошёлся, а разметка нет — запись другой версии формата; не используем
+		for i := range frames {
+			cs.pool.Put(frames[i].Data)
+		}
+		cs.putFrameSlice(frames)
+		return nil, io.ErrUnexpectedEOF
+	}
+	return &Chunk{StartSeq: startSeq, Frames: frames, BytesLen: totalLen, BytesCap: totalCap}, nil
+}
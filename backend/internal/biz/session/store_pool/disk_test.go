@@ -0,0 +1,269 @@
+package store_pool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// diskChunk — полный чанк из n кадров по size байт (содержимое зависит от seq)
+func diskChunk(cs *ChunkStore, startSeq int64, n, size int) *Chunk {
+	ch := &Chunk{StartSeq: startSeq}
+	for i := range n {
+		f := makeFrame(cs, startSeq+int64(i), size)
+		f.Data[0] = byte(startSeq + int64(i))
+		ch.Frames = append(ch.Frames, f)
+		ch.BytesLen += int64(len(f.Data))
+		ch.BytesCap += int64(cap(f.Data))
+	}
+	return ch
+}
+
+func openTestDisk(t *testing.T, dir string, limit, seg int64) *DiskTier {
+	t.Helper()
+	d, err := OpenDiskTier(dir, limit, seg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDiskTierRoundTripAndRestart(t *testing.T) {
+	dir := t.TempDir()
+	cs := newTestStore(1<<20, 4)
+	key := ChunkKey{Stream: uuid.New(), Index: 3}
+	ch := diskChunk(cs, 12, 4, 100)
+
+	d := openTestDisk(t, dir, 1<<20, 64<<10)
+	if err := d.write(key, 7, ch); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Has(key, 7) || d.Has(key, 8) {
+		t.Fatalf("unexpected Has")
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// после перезапуска индекс восстанавливается сканированием
+	d = openTestDisk(t, dir, 1<<20, 64<<10)
+	defer d.Close()
+	body, err := d.read(key, 7, 12)
+	if err != nil || body == nil {
+		t.Fatalf("expected chunk after restart, err=%v", err)
+	}
+	got, err := cs.decodeDiskChunk(body, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Frames) != 4 || got.Frames[2].Seq != 14 || got.Frames[2].Data[0] != 14 || got.Frames[2].Mime != "image/jpeg" || got.BytesLen != 400 {
+		t.Fatalf("unexpected chunk %+v", got)
+	}
+	// другая версия стрима (стрим правили) — записи не подходят
+	if body, _ = d.read(key, 8, 12); body != nil {
+		t.Fatalf("stale version must miss")
+	}
+}
+
+func TestDiskTierTruncatesTornTail(t *testing.T) {
+	dir := t.TempDir()
+	cs := newTestStore(1<<20, 4)
+	a, b := ChunkKey{Stream: uuid.New()}, ChunkKey{Stream: uuid.New()}
+
+	d := openTestDisk(t, dir, 1<<20, 64<<10)
+	_ = d.write(a, 1, diskChunk(cs, 0, 4, 100))
+	_ = d.write(b, 1, diskChunk(cs, 0, 4, 100))
+	_ = d.Close()
+
+	// падение посреди второй записи: от неё остался обрывок
+	path := filepath.Join(dir, "0000000000000000.seg")
+	st, _ := os.Stat(path)
+	if err := os.Truncate(path, st.Size()-50); err != nil {
+		t.Fatal(err)
+	}
+
+	d = openTestDisk(t, dir, 1<<20, 64<<10)
+	defer d.Close()
+	if !d.Has(a, 1) || d.Has(b, 1) {
+		t.Fatalf("expected only the intact record to survive")
+	}
+	if st, _ = os.Stat(path); st.Size() != d.UsedBytes() {
+		t.Fatalf("torn tail must be cut: file %d, used %d", st.Size(), d.UsedBytes())
+	}
+	// дописывание продолжается с места обрыва
+	if err := d.write(b, 1, diskChunk(cs, 0, 4, 100)); err != nil || !d.Has(b, 1) {
+		t.Fatalf("rewrite after truncation failed: %v", err)
+	}
+}
+
+func TestDiskTierDetectsCorruptBody(t *testing.T) {
+	dir := t.TempDir()
+	cs := newTestStore(1<<20, 4)
+	key := ChunkKey{Stream: uuid.New()}
+	d := openTestDisk(t, dir, 1<<20, 64<<10)
+	defer d.Close()
+	_ = d.write(key, 1, diskChunk(cs, 0, 4, 100))
+
+	// порча тела на диске
+	path := filepath.Join(dir, "0000000000000000.seg")
+	data, _ := os.ReadFile(path)
+	data[diskHeaderLen+20] ^= 0xFF
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.read(key, 1, 0); !errors.Is(err, ErrDiskChunkCorrupt) {
+		t.Fatalf("expected ErrDiskChunkCorrupt, got %v", err)
+	}
+	if d.Has(key, 1) {
+		t.Fatalf("corrupt record must be forgotten")
+	}
+}
+
+func TestDiskTierDropsOldestSegmentsOverBudget(t *testing.T) {
+	dir := t.TempDir()
+	cs := newTestStore(1<<20, 4)
+	d := openTestDisk(t, dir, 4<<10, 1<<10) // запись ~0.5 KiB: по две в сегменте
+	defer d.Close()
+
+	var ks []ChunkKey
+	for i := range 20 {
+		key := ChunkKey{Stream: uuid.New(), Index: int64(i)}
+		ks = append(ks, key)
+		if err := d.write(key, 1, diskChunk(cs, 0, 4, 100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d.UsedBytes() > 4<<10 {
+		t.Fatalf("disk budget exceeded: %d", d.UsedBytes())
+	}
+	if d.Has(ks[0], 1) || !d.Has(ks[19], 1) {
+		t.Fatalf("expected oldest records dropped and newest kept")
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.seg"))
+	if len(files) != len(d.segs) {
+		t.Fatalf("dropped segments must be removed from disk: %d files, %d segments", len(files), len(d.segs))
+	}
+}
+
+func TestChunkStoreSpillsToDiskAndServesMisses(t *testing.T) {
+	cs := newTestStore(160<<10, 4) // в RAM помещается один чанк (4 кадра по ведру 32 KiB): каждый новый вытесняет предыдущий
+	d := openTestDisk(t, t.TempDir(), 1<<20, 256<<10)
+	cs.SetDiskTier(d)
+	stream := uuid.New()
+	cs.NoteStreamVersion(stream, 1)
+
+	loads := 0
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		loads++
+		return diskChunk(cs, startSeq, 4, 100), nil
+	})
+	get := func(seq int64) *Chunk {
+		ch, err := cs.GetChunk(context.Background(), stream, 0, seq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs.ReleaseChunk(ch)
+		return ch
+	}
+
+	get(0)
+	get(4) // чанк 0 вытесняется и уходит на диск
+	waitSpilled(t, d, ChunkKey{Stream: stream, Index: 0}, 1)
+
+	first := get(0)
+	if loads != 2 {
+		t.Fatalf("expected chunk 0 served from disk, loads=%d", loads)
+	}
+	if len(first.Frames) != 4 || first.Frames[3].Seq != 3 || first.Frames[3].Data[0] != 3 {
+		t.Fatalf("unexpected chunk from disk %+v", first)
+	}
+
+	// стрим правили: новая версия — диск больше не подходит
+	cs.NoteStreamVersion(stream, 2)
+	get(0)
+	if loads != 3 {
+		t.Fatalf("expected reload from DB after version change, loads=%d", loads)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitSpilled — запись на диск асинхронная: ждём, пока фоновая очередь допишет чанк
+func waitSpilled(t *testing.T, d *DiskTier, key ChunkKey, version int64) {
+	t.Helper()
+	for range 200 {
+		if d.Has(key, version) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("chunk %v was not spilled to disk", key)
+}
+
+func TestEncodeDiskChunkHeader(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	key := ChunkKey{Stream: uuid.New(), Index: 5}
+	rec := encodeDiskChunk(key, 9, diskChunk(cs, 20, 2, 10))
+	got, loc, ok := parseDiskHeader(rec)
+	if !ok || got != key || loc.version != 9 || loc.startSeq != 20 || loc.bodyLen != int64(len(rec)-diskHeaderLen) {
+		t.Fatalf("bad header: %v %+v %v", got, loc, ok)
+	}
+	bad := bytes.Clone(rec)
+	bad[33] ^= 1 // index
+	if _, _, ok = parseDiskHeader(bad); ok {
+		t.Fatalf("header crc must catch corruption")
+	}
+}
+
+func TestOverBudgetLoadSpilledToDiskIsFreedOnce(t *testing.T) {
+	cs := newTestStore(40<<10, 4)
+	d := openTestDisk(t, t.TempDir(), 1<<20, 256<<10)
+	defer d.Close()
+	cs.SetDiskTier(d)
+
+	// удерживаемый сессией чанк: эвикт его снимет, но память не вернёт
+	held := addChunk(cs, ChunkKey{Stream: uuid.New()}, []Frame{makeFrame(cs, 0, 100), makeFrame(cs, 1, 100)})
+	atomic.AddInt32(&held.refs, 1)
+
+	stream := uuid.New()
+	cs.NoteStreamVersion(stream, 1)
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		return diskChunk(cs, startSeq, 4, 100), nil
+	})
+	// загруженный полный чанк тут же вытесняется на диск, а бюджет всё равно превышен — ошибка
+	if _, err := cs.GetChunk(context.Background(), stream, 0, 0); !errors.Is(err, ErrCachePressure) {
+		t.Fatalf("expected cache pressure, got %v", err)
+	}
+
+	// запись на диск получила целые кадры, а не буферы, уже отданные в пул
+	key := ChunkKey{Stream: stream}
+	waitSpilled(t, d, key, 1)
+	body, err := d.read(key, 1, 0)
+	if err != nil || body == nil {
+		t.Fatalf("expected spilled chunk, err=%v", err)
+	}
+	got, err := cs.decodeDiskChunk(body, 0)
+	if err != nil || len(got.Frames) != 4 || got.Frames[3].Data[0] != 3 {
+		t.Fatalf("spilled chunk corrupted: %+v %v", got, err)
+	}
+
+	// после записи память чанка учтена ровно один раз
+	for range 200 {
+		cs.mu.Lock()
+		used := cs.usedCapB
+		cs.mu.Unlock()
+		if used == held.BytesCap {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected only the held chunk to stay accounted, used %d want %d", cs.usedCapB, held.BytesCap)
+}
@@ -0,0 +1,142 @@
+package store_pool
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// Metrics — метрики кэша чанков и сессий воспроизведения поверх него (otel → prometheus на /metrics)
+// Сессии берут их у ChunkStore, через который читают кадры, — отдельная прокладка по хендлерам не нужна
+// По умолчанию инструменты noop: пока RegisterMetrics не вызван, запись ничего не стоит
+type Metrics struct {
+	requests   metric.Int64Counter     // обращения к кэшу: result=hit|miss|dedup
+	loads      metric.Float64Histogram // загрузка чанка (секунды): source=db|disk
+	evictions  metric.Int64Counter     // вытеснения: reason=budget|drop
+	rejections metric.Int64Counter     // отказы в загрузке под давлением на кэш
+
+	sessions  metric.Int64UpDownCounter // активные сессии: stream_id
+	delivered metric.Int64Counter       // отправленные кадры
+	skipped   metric.Int64Counter       // пропущенные кадры (догоняли шкалу времени)
+	writeErrs metric.Int64Counter       // ошибки записи кадра клиенту (обрыв, таймаут)
+}
+
+// Атрибуты, собранные заранее: запись на горячем пути без аллокаций
+var (
+	attrHit         = metric.WithAttributeSet(attribute.NewSet(attribute.String("result", "hit")))
+	attrMiss        = metric.WithAttributeSet(attribute.NewSet(attribute.String("result", "miss")))
+	attrDedup       = metric.WithAttributeSet(attribute.NewSet(attribute.String("result", "dedup")))
+	attrSourceDB    = metric.WithAttributeSet(attribute.NewSet(attribute.String("source", "db")))
+	attrSourceDisk  = metric.WithAttributeSet(attribute.NewSet(attribute.String("source", "disk")))
+	attrEvictBudget = metric.WithAttributeSet(attribute.NewSet(attribute.String("reason", "budget")))
+	attrEvictDrop   = metric.WithAttributeSet(attribute.NewSet(attribute.String("reason", "drop")))
+	attrLen         = metric.WithAttributeSet(attribute.NewSet(attribute.String("kind", "len")))
+	attrCap         = metric.WithAttributeSet(attribute.NewSet(attribute.String("kind", "cap")))
+)
+
+// newMetrics — инструменты на meter; ошибки регистрации собираются вместе
+func newMetrics(meter metric.Meter) (*Metrics, error) {
+	var m Metrics
+	var err, e error
+	m.requests, e = meter.Int64Counter("stream_chunk_cache_requests", metric.WithDescription("Chunk cache lookups by result (hit, miss, dedup)"))
+	err = errors.Join(err, e)
+	m.loads, e = meter.Float64Histogram("stream_chunk_load_duration", metric.WithUnit("s"), metric.WithDescription("Chunk load latency by source (db, disk)"))
+	err = errors.Join(err, e)
+	m.evictions, e = meter.Int64Counter("stream_chunk_cache_evictions", metric.WithDescription("Chunks removed from the cache by reason (budget, drop)"))
+	err = errors.Join(err, e)
+	m.rejections, e = meter.Int64Counter("stream_chunk_cache_pressure_rejections", metric.WithDescription("Chunk loads refused because the cache is far over budget"))
+	err = errors.Join(err, e)
+	m.sessions, e = meter.Int64UpDownCounter("stream_sessions_active", metric.WithDescription("Active playback sessions per stream"))
+	err = errors.Join(err, e)
+	m.delivered, e = meter.Int64Counter("stream_frames_delivered", metric.WithDescription("Frames sent to playback clients"))
+	err = errors.Join(err, e)
+	m.skipped, e = meter.Int64Counter("stream_frames_skipped", metric.WithDescription("Frames skipped to keep playback on the stream timeline"))
+	err = errors.Join(err, e)
+	m.writeErrs, e = meter.Int64Counter("stream_session_write_errors", metric.WithDescription("Failed frame writes to playback clients"))
+	err = errors.Join(err, e)
+	return &m, err
+}
+
+var noopMetrics, _ = newMetrics(noop.NewMeterProvider().Meter(""))
+
+// RegisterMetrics — завести метрики кэша и сессий на meter (dep.NewMeter); вызывать до начала работы
+// Объём кэша (len/cap), число чанков и объём дискового уровня снимаются callback'ом на каждом сборе
+func (cs *ChunkStore) RegisterMetrics(meter metric.Meter) error {
+	m, err := newMetrics(meter)
+	if err != nil {
+		return err
+	}
+	bytes, err := meter.Int64ObservableGauge("stream_chunk_cache_bytes", metric.WithUnit("By"),
+		metric.WithDescription("Chunk cache size: kind=len (payload) or cap (pool buckets, the real RAM budget)"))
+	if err != nil {
+		return err
+	}
+	chunks, err := meter.Int64ObservableGauge("stream_chunk_cache_chunks", metric.WithDescription("Chunks in the RAM cache"))
+	if err != nil {
+		return err
+	}
+	disk, err := meter.Int64ObservableGauge("stream_chunk_disk_bytes", metric.WithUnit("By"), metric.WithDescription("Disk tier segments size"))
+	if err != nil {
+		return err
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		cs.mu.Lock()
+		usedLen, usedCap, n := cs.usedLenB, cs.usedCapB, len(cs.items)
+		cs.mu.Unlock()
+		o.ObserveInt64(bytes, usedLen, attrLen)
+		o.ObserveInt64(bytes, usedCap, attrCap)
+		o.ObserveInt64(chunks, int64(n))
+		if cs.disk != nil {
+			o.ObserveInt64(disk, cs.disk.UsedBytes())
+		}
+		return nil
+	}, bytes, chunks, disk)
+	if err != nil {
+		return err
+	}
+	cs.metrics = m
+	return nil
+}
+
+// Metrics — метрики для сессий, читающих через этот кэш
+func (cs *ChunkStore) Metrics() *Metrics {
+	return cs.metrics
+}
+
+// observeLoad — задержка загрузки чанка из БД или с диска
+func (m *Metrics) observeLoad(start time.Time, fromDisk bool) {
+	src := attrSourceDB
+	if fromDisk {
+		src = attrSourceDisk
+	}
+	m.loads.Record(context.Background(), time.Since(start).Seconds(), src)
+}
+
+// SessionStarted — сессия стрима началась; вернёт функцию завершения (вызвать ровно один раз)
+func (m *Metrics) SessionStarted(stream uuid.UUID) func() {
+	attrs := metric.WithAttributeSet(attribute.NewSet(attribute.String("stream_id", stream.String())))
+	m.sessions.Add(context.Background(), 1, attrs)
+	return func() { m.sessions.Add(context.Background(), -1, attrs) }
+}
+
+// FrameDelivered — кадр отправлен клиенту
+func (m *Metrics) FrameDelivered() {
+	m.delivered.Add(context.Background(), 1)
+}
+
+// FramesSkipped — n кадров пропущено на догонялках
+func (m *Metrics) FramesSkipped(n int64) {
+	if n > 0 {
+		m.skipped.Add(context.Background(), n)
+	}
+}
+
+// WriteFailed — запись кадра клиенту не удалась
+func (m *Metrics) WriteFailed() {
+	m.writeErrs.Add(context.Background(), 1)
+}
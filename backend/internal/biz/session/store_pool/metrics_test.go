@@ -0,0 +1,118 @@
+package store_pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectInt64 — значения int64-метрики name по значению атрибута key ("" — без атрибута)
+func collectInt64(t *testing.T, reader *sdkmetric.ManualReader, name, key string) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	res := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			var points []metricdata.DataPoint[int64]
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				points = data.DataPoints
+			case metricdata.Gauge[int64]:
+				points = data.DataPoints
+			}
+			for _, p := range points {
+				v, _ := p.Attributes.Value(attribute.Key(key))
+				res[v.AsString()] += p.Value
+			}
+		}
+	}
+	return res
+}
+
+func TestChunkStoreMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	cs := newTestStore(160<<10, 4) // помещается один чанк из 4 кадров по ведру 32 KiB
+	if err := cs.RegisterMetrics(meter); err != nil {
+		t.Fatal(err)
+	}
+
+	gate, entered := make(chan struct{}), make(chan struct{}, 1)
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		select {
+		case entered <- struct{}{}:
+		default:
+		}
+		<-gate
+		return diskChunk(cs, startSeq, 4, 100), nil
+	})
+	stream := uuid.New()
+	get := func() {
+		ch, err := cs.GetChunk(context.Background(), stream, 0, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		cs.ReleaseChunk(ch)
+	}
+
+	// три одновременных промаха по одному чанку: одна загрузка, две — dedup
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get()
+		}()
+	}
+	<-entered
+	time.Sleep(50 * time.Millisecond) // остальные успевают присоединиться к висящей загрузке
+	close(gate)
+	wg.Wait()
+	get() // попадание
+
+	reqs := collectInt64(t, reader, "stream_chunk_cache_requests", "result")
+	if reqs["miss"] != 1 || reqs["dedup"] < 1 || reqs["miss"]+reqs["dedup"]+reqs["hit"] != 4 {
+		t.Fatalf("unexpected requests %v", reqs)
+	}
+	if b := collectInt64(t, reader, "stream_chunk_cache_bytes", "kind"); b["len"] != 400 || b["cap"] != 4*(32<<10) {
+		t.Fatalf("unexpected cache bytes %v", b)
+	}
+	if n := collectInt64(t, reader, "stream_chunk_cache_chunks", ""); n[""] != 1 {
+		t.Fatalf("unexpected chunk count %v", n)
+	}
+
+	// следующий чанк вытесняет первый по бюджету, удаление стрима снимает оставшийся
+	ch, err := cs.GetChunk(context.Background(), stream, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.ReleaseChunk(ch)
+	cs.DropStream(stream)
+	if ev := collectInt64(t, reader, "stream_chunk_cache_evictions", "reason"); ev["budget"] != 1 || ev["drop"] != 1 {
+		t.Fatalf("unexpected evictions %v", ev)
+	}
+
+	// давление на кэш: отказ в загрузке
+	cs.mu.Lock()
+	cs.usedCapB = cs.limitB*PressureGuardFactor + 1
+	cs.mu.Unlock()
+	if _, err = cs.GetChunk(context.Background(), stream, 0, 8); err == nil {
+		t.Fatalf("expected cache pressure")
+	}
+	if r := collectInt64(t, reader, "stream_chunk_cache_pressure_rejections", ""); r[""] != 1 {
+		t.Fatalf("unexpected rejections %v", r)
+	}
+}
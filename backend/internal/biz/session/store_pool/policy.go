@@ -0,0 +1,350 @@
+package store_pool
+
+import (
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// EvictionPolicy — порядок вытеснения чанков из кэша. Бюджет (cap-байты) считает ChunkStore,
+// политика только решает, кого снимать следующим. Все методы вызываются под cs.mu
+type EvictionPolicy interface {
+	// Add — чанк положен в кэш (промах: его только что загрузили)
+	Add(key ChunkKey, size int64)
+	// Touch — попадание в кэш
+	Touch(key ChunkKey)
+	// Remove — чанк снят из кэша (вытеснен по Victim или сброшен DropStream/InvalidateTail)
+	Remove(key ChunkKey)
+	// Victim — кого вытеснить следующим; false — кэш пуст. Сам чанк не снимает: ChunkStore затем вызовет Remove
+	Victim() (ChunkKey, bool)
+}
+
+// holdObserver — политике важно, какие чанки сейчас держат сессии (где курсоры зрителей)
+// delta: +1 — чанк взят (GetChunk), -1 — отпущен (ReleaseChunk)
+type holdObserver interface {
+	Held(key ChunkKey, delta int)
+}
+
+// Имена политик для конфига (STREAM_CACHE_EVICTION)
+const (
+	PolicyLRU     = "lru"
+	PolicyTinyLFU = "tinylfu"
+	PolicyCursor  = "cursor"
+)
+
+// ErrUnknownPolicy неизвестное имя политики вытеснения
+var ErrUnknownPolicy = errors.New("unknown eviction policy")
+
+// NewEvictionPolicy — политика по имени; limitCapBytes — бюджет кэша (W-TinyLFU делит его на окно и основную часть)
+func NewEvictionPolicy(name string, limitCapBytes int64) (EvictionPolicy, error) {
+	switch name {
+	case PolicyLRU, "":
+		return NewLRUPolicy(), nil
+	case PolicyTinyLFU:
+		return NewTinyLFUPolicy(limitCapBytes), nil
+	case PolicyCursor:
+		return NewCursorPolicy(DefaultProtectAhead), nil
+	}
+	return nil, fmt.Errorf("%w: %q (want %s, %s or %s)", ErrUnknownPolicy, name, PolicyLRU, PolicyTinyLFU, PolicyCursor)
+}
+
+// -------- LRU --------
+
+// LRUPolicy — вытесняем давно не использованный чанк
+type LRUPolicy struct {
+	ll    *list.List // front — свежий
+	items map[ChunkKey]*list.Element
+}
+
+func NewLRUPolicy() *LRUPolicy {
+	return &LRUPolicy{ll: list.New(), items: make(map[ChunkKey]*list.Element)}
+}
+
+func (p *LRUPolicy) Add(key ChunkKey, _ int64) {
+	if el := p.items[key]; el != nil {
+		p.ll.MoveToFront(el)
+		return
+	}
+	p.items[key] = p.ll.PushFront(key)
+}
+
+func (p *LRUPolicy) Touch(key ChunkKey) {
+	if el := p.items[key]; el != nil {
+		p.ll.MoveToFront(el)
+	}
+}
+
+func (p *LRUPolicy) Remove(key ChunkKey) {
+	if el := p.items[key]; el != nil {
+		p.ll.Remove(el)
+		delete(p.items, key)
+	}
+}
+
+func (p *LRUPolicy) Victim() (ChunkKey, bool) {
+	el := p.ll.Back()
+	if el == nil {
+		return ChunkKey{}, false
+	}
+	return el.Value.(ChunkKey), true
+}
+
+// -------- W-TinyLFU --------
+
+// Доли бюджета W-TinyLFU (как в Caffeine): окно 1%, защищённый сегмент — 80% основной части
+const (
+	tinyLFUWindowShare    = 0.01
+	tinyLFUProtectedShare = 0.8
+)
+
+// сегменты W-TinyLFU
+const (
+	segWindow = iota
+	segProbation
+	segProtected
+)
+
+type tinyLFUEntry struct {
+	key  ChunkKey
+	size int64
+	seg  int
+}
+
+// TinyLFUPolicy — W-TinyLFU: новые чанки попадают в маленькое LRU-окно, а из окна в основную (SLRU) часть проходят,
+// только если по частотному скетчу их спрашивали чаще, чем кандидата на вытеснение оттуда
+// Разовый seek по холодному стриму не вытесняет чанки, которые смотрят постоянно
+type TinyLFUPolicy struct {
+	items map[ChunkKey]*list.Element
+	segs  [3]*list.List // front — свежий
+	bytes [3]int64
+
+	windowQuota    int64
+	protectedQuota int64
+
+	sketch *countMinSketch
+}
+
+func NewTinyLFUPolicy(limitCapBytes int64) *TinyLFUPolicy {
+	window := int64(float64(limitCapBytes) * tinyLFUWindowShare)
+	return &TinyLFUPolicy{
+		items:          make(map[ChunkKey]*list.Element),
+		segs:           [3]*list.List{list.New(), list.New(), list.New()},
+		windowQuota:    window,
+		protectedQuota: int64(float64(limitCapBytes-window) * tinyLFUProtectedShare),
+		sketch:         newCountMinSketch(),
+	}
+}
+
+func (p *TinyLFUPolicy) Add(key ChunkKey, size int64) {
+	p.sketch.increment(key, len(p.items))
+	if el := p.items[key]; el != nil {
+		p.touch(el)
+		return
+	}
+	p.items[key] = p.segs[segWindow].PushFront(&tinyLFUEntry{key: key, size: size, seg: segWindow})
+	p.bytes[segWindow] += size
+}
+
+func (p *TinyLFUPolicy) Touch(key ChunkKey) {
+	p.sketch.increment(key, len(p.items))
+	if el := p.items[key]; el != nil {
+		p.touch(el)
+	}
+}
+
+// touch — попадание: в окне и защищённом сегменте — в начало, из испытательного — повышение в защищённый
+func (p *TinyLFUPolicy) touch(el *list.Element) {
+	e := el.Value.(*tinyLFUEntry)
+	if e.seg != segProbation {
+		p.segs[e.seg].MoveToFront(el)
+		return
+	}
+	p.move(el, segProtected)
+	// защищённый переполнен — его хвост обратно на испытание (один чанк остаётся в любом случае)
+	for p.bytes[segProtected] > p.protectedQuota && p.segs[segProtected].Len() > 1 {
+		p.move(p.segs[segProtected].Back(), segProbation)
+	}
+}
+
+func (p *TinyLFUPolicy) Remove(key ChunkKey) {
+	el := p.items[key]
+	if el == nil {
+		return
+	}
+	e := el.Value.(*tinyLFUEntry)
+	p.segs[e.seg].Remove(el)
+	p.bytes[e.seg] -= e.size
+	delete(p.items, key)
+}
+
+// Victim — окно сверх квоты: его хвост соревнуется по частоте с кандидатом основной части, проигравший уходит
+// Окно в пределах квоты: вытесняем из основной части (испытательный сегмент, затем защищённый)
+func (p *TinyLFUPolicy) Victim() (ChunkKey, bool) {
+	// последний чанк окна не трогаем: это, как правило, только что загруженный — пусть успеет набрать попадания
+	for p.bytes[segWindow] > p.windowQuota && p.segs[segWindow].Len() > 1 {
+		candidate := p.segs[segWindow].Back()
+		victim := p.mainVictim()
+		if victim == nil {
+			// основная часть пуста — окно переливается в неё без конкурса
+			p.move(candidate, segProbation)
+			continue
+		}
+		ck, vk := candidate.Value.(*tinyLFUEntry).key, victim.Value.(*tinyLFUEntry).key
+		if p.sketch.estimate(ck) > p.sketch.estimate(vk) {
+			p.move(candidate, segProbation)
+			return vk, true
+		}
+		return ck, true
+	}
+	if victim := p.mainVictim(); victim != nil {
+		return victim.Value.(*tinyLFUEntry).key, true
+	}
+	if el := p.segs[segWindow].Back(); el != nil {
+		return el.Value.(*tinyLFUEntry).key, true
+	}
+	return ChunkKey{}, false
+}
+
+func (p *TinyLFUPolicy) mainVictim() *list.Element {
+	if el := p.segs[segProbation].Back(); el != nil {
+		return el
+	}
+	return p.segs[segProtected].Back()
+}
+
+// move — перенести чанк в начало сегмента seg
+func (p *TinyLFUPolicy) move(el *list.Element, seg int) {
+	e := el.Value.(*tinyLFUEntry)
+	p.segs[e.seg].Remove(el)
+	p.bytes[e.seg] -= e.size
+	e.seg = seg
+	p.items[e.key] = p.segs[seg].PushFront(e)
+	p.bytes[seg] += e.size
+}
+
+// countMinSketch — приблизительные частоты обращений к чанкам: 4 строки 4-битных (до 15) счётчиков
+// Каждые 10×(чанков в кэше) обращений все счётчики делятся пополам, чтобы старая популярность выветривалась:
+// зрители уходят вперёд, и пройденные ими чанки не должны вечно удерживать основную часть
+type countMinSketch struct {
+	rows    [sketchDepth][]uint8
+	samples int
+}
+
+const (
+	sketchDepth      = 4
+	sketchWidth      = 4096 // степень двойки; чанков в кэше — сотни, с запасом на историю промахов
+	sketchMaxCount   = 15
+	sketchMinEntries = 16 // нижняя граница "размера кэша" для периода сброса
+)
+
+var sketchSeeds = [sketchDepth]uint64{0x9e3779b97f4a7c15, 0xbf58476d1ce4e5b9, 0x94d049bb133111eb, 0xd6e8feb86659fd93}
+
+func newCountMinSketch() *countMinSketch {
+	s := &countMinSketch{}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, sketchWidth)
+	}
+	return s
+}
+
+// increment — entries: сколько чанков сейчас в кэше (задаёт период сброса)
+func (s *countMinSketch) increment(key ChunkKey, entries int) {
+	h := keyHash(key)
+	for i := range s.rows {
+		if c := &s.rows[i][slot(h, i)]; *c < sketchMaxCount {
+			*c++
+		}
+	}
+	if s.samples++; s.samples >= 10*max(entries, sketchMinEntries) {
+		for i := range s.rows {
+			for j := range s.rows[i] {
+				s.rows[i][j] >>= 1
+			}
+		}
+		s.samples /= 2
+	}
+}
+
+func (s *countMinSketch) estimate(key ChunkKey) uint8 {
+	h := keyHash(key)
+	est := uint8(sketchMaxCount)
+	for i := range s.rows {
+		est = min(est, s.rows[i][slot(h, i)])
+	}
+	return est
+}
+
+func slot(h uint64, row int) uint64 {
+	return mix64(h^sketchSeeds[row]) & (sketchWidth - 1)
+}
+
+func keyHash(key ChunkKey) uint64 {
+	return binary.LittleEndian.Uint64(key.Stream[:8]) ^ mix64(binary.LittleEndian.Uint64(key.Stream[8:])^uint64(key.Index))
+}
+
+// mix64 — финализатор splitmix64
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// -------- защита чанков впереди курсоров --------
+
+// DefaultProtectAhead сколько чанков впереди курсора (включая текущий) защищать от вытеснения
+const DefaultProtectAhead = 4
+
+// CursorPolicy — LRU с учётом зрителей: чанк, на котором или чуть впереди которого стоят курсоры сессий,
+// вытесняется последним, а среди таких — тот, за которым меньше зрителей
+// Популярный стрим с десятком зрителей не теряет следующие чанки из-за разовых seek по холодным стримам
+type CursorPolicy struct {
+	lru   *LRUPolicy
+	held  map[ChunkKey]int // сколько сессий держат чанк (курсоры)
+	ahead int64
+}
+
+func NewCursorPolicy(ahead int) *CursorPolicy {
+	return &CursorPolicy{lru: NewLRUPolicy(), held: make(map[ChunkKey]int), ahead: int64(max(ahead, 1))}
+}
+
+func (p *CursorPolicy) Add(key ChunkKey, size int64) { p.lru.Add(key, size) }
+func (p *CursorPolicy) Touch(key ChunkKey)           { p.lru.Touch(key) }
+func (p *CursorPolicy) Remove(key ChunkKey)          { p.lru.Remove(key) }
+
+func (p *CursorPolicy) Held(key ChunkKey, delta int) {
+	if n := p.held[key] + delta; n > 0 {
+		p.held[key] = n
+	} else {
+		delete(p.held, key)
+	}
+}
+
+// viewers — сколько курсоров стоит на чанке или за ahead-1 чанков до него
+func (p *CursorPolicy) viewers(key ChunkKey) int {
+	n := 0
+	for d := int64(0); d < p.ahead && key.Index-d >= 0; d++ {
+		n += p.held[ChunkKey{Stream: key.Stream, Index: key.Index - d}]
+	}
+	return n
+}
+
+// Victim — с хвоста LRU первый чанк без зрителей; если все под защитой — с наименьшим их числом (при равенстве — старейший)
+func (p *CursorPolicy) Victim() (ChunkKey, bool) {
+	var best ChunkKey
+	bestViewers := -1
+	for el := p.lru.ll.Back(); el != nil; el = el.Prev() {
+		key := el.Value.(ChunkKey)
+		n := p.viewers(key)
+		if n == 0 {
+			return key, true
+		}
+		if bestViewers < 0 || n < bestViewers {
+			best, bestViewers = key, n
+		}
+	}
+	return best, bestViewers >= 0
+}
@@ -0,0 +1,153 @@
+package store_pool
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Синтетические нагрузки зрителей для сравнения политик вытеснения по hit rate:
+//   go test -run '^$' -bench EvictionPolicies ./internal/biz/session/store_pool/
+
+// traceStep — сессия viewer переходит на чанк key (предыдущий отпускает); viewer < 0 — разовый seek:
+// чанк берётся и сразу отпускается
+type traceStep struct {
+	viewer int
+	key    ChunkKey
+}
+
+type viewerTrace struct {
+	name   string
+	chunks int64 // ёмкость кэша в чанках
+	steps  []traceStep
+}
+
+const traceChunkN = 4
+
+func traceStream(name string, i int) uuid.UUID {
+	return uuid.NewSHA1(uuid.Nil, []byte(name+strconv.Itoa(i)))
+}
+
+// hotSeeksTrace — восемь зрителей смотрят один популярный стрим, отставая друг от друга на два чанка,
+// а между их шагами идут разовые seek по двум сотням холодных стримов
+func hotSeeksTrace() viewerTrace {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tr := viewerTrace{name: "hot+seeks", chunks: 16}
+	hot := traceStream("hot", 0)
+	const viewers = 8
+	for round := int64(0); round < 500; round++ {
+		for v := range viewers {
+			if idx := round - 2*int64(v); idx >= 0 {
+				tr.steps = append(tr.steps, traceStep{viewer: v, key: ChunkKey{Stream: hot, Index: idx % 256}})
+			}
+		}
+		for range 4 {
+			cold := ChunkKey{Stream: traceStream("cold", rng.IntN(200)), Index: rng.Int64N(64)}
+			tr.steps = append(tr.steps, traceStep{viewer: -1, key: cold})
+		}
+	}
+	return tr
+}
+
+// zipfTrace — 24 одновременных сессии по 16 чанков, чаще с начала стрима; стрим выбирается по Zipf из 40:
+// несколько популярных и длинный хвост
+func zipfTrace() viewerTrace {
+	rng := rand.New(rand.NewPCG(3, 4))
+	zipf := rand.NewZipf(rng, 1.1, 1, 39)
+	tr := viewerTrace{name: "zipf", chunks: 32}
+
+	type session struct {
+		stream uuid.UUID
+		idx    int64
+		left   int
+	}
+	sessions := make([]session, 24)
+	for round := 0; round < 400; round++ {
+		for v := range sessions {
+			s := &sessions[v]
+			if s.left == 0 {
+				*s = session{stream: traceStream("zipf", int(zipf.Uint64())), left: 16}
+				if rng.IntN(10) < 3 { // большинство смотрит с начала, остальные — с середины
+					s.idx = rng.Int64N(48)
+				}
+			}
+			tr.steps = append(tr.steps, traceStep{viewer: v, key: ChunkKey{Stream: s.stream, Index: s.idx}})
+			s.idx++
+			s.left--
+		}
+	}
+	return tr
+}
+
+// replayTrace — прогон нагрузки через ChunkStore с политикой p; загрузка из БД подменена, все чанки одного размера
+// Возвращает долю обращений, обслуженных из кэша
+func replayTrace(tb testing.TB, tr viewerTrace, p EvictionPolicy) float64 {
+	const chunkCap = 1 << 20
+	cs := NewChunkStore(nil, []int{32 << 10}, tr.chunks*chunkCap, traceChunkN)
+	cs.SetEvictionPolicy(p)
+	loads := 0
+	cs.load = func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		loads++
+		return &Chunk{StartSeq: startSeq, BytesLen: chunkCap, BytesCap: chunkCap}, nil
+	}
+
+	ctx := context.Background()
+	held := map[int]*Chunk{}
+	for _, st := range tr.steps {
+		chunk, err := cs.GetChunk(ctx, st.key.Stream, 0, st.key.Index*traceChunkN)
+		if err != nil {
+			tb.Fatalf("%s: %v", tr.name, err)
+		}
+		if st.viewer < 0 {
+			cs.ReleaseChunk(chunk)
+			continue
+		}
+		cs.ReleaseChunk(held[st.viewer])
+		held[st.viewer] = chunk
+	}
+	for _, chunk := range held {
+		cs.ReleaseChunk(chunk)
+	}
+	return 1 - float64(loads)/float64(len(tr.steps))
+}
+
+var benchPolicies = []string{PolicyLRU, PolicyTinyLFU, PolicyCursor}
+
+func BenchmarkEvictionPolicies(b *testing.B) {
+	for _, tr := range []viewerTrace{hotSeeksTrace(), zipfTrace()} {
+		for _, name := range benchPolicies {
+			b.Run(tr.name+"/"+name, func(b *testing.B) {
+				var hit float64
+				for b.Loop() {
+					p, _ := NewEvictionPolicy(name, tr.chunks<<20)
+					hit = replayTrace(b, tr, p)
+				}
+				b.ReportMetric(hit*100, "hit%")
+			})
+		}
+	}
+}
+
+func TestEvictionPoliciesOnViewerTraces(t *testing.T) {
+	for _, tr := range []viewerTrace{hotSeeksTrace(), zipfTrace()} {
+		hits := map[string]float64{}
+		for _, name := range benchPolicies {
+			p, _ := NewEvictionPolicy(name, tr.chunks<<20)
+			hits[name] = replayTrace(t, tr, p)
+		}
+		t.Logf("%s: %v", tr.name, hits)
+		if hits[PolicyTinyLFU] < hits[PolicyLRU] || hits[PolicyCursor] < hits[PolicyLRU] {
+			t.Fatalf("%s: expected tinylfu and cursor not worse than lru: %v", tr.name, hits)
+		}
+	}
+	// на hot+seeks разовые seek выбивают из LRU чанки между зрителями популярного стрима, cursor их держит
+	tr := hotSeeksTrace()
+	lru, _ := NewEvictionPolicy(PolicyLRU, tr.chunks<<20)
+	cursor, _ := NewEvictionPolicy(PolicyCursor, tr.chunks<<20)
+	if l, c := replayTrace(t, tr, lru), replayTrace(t, tr, cursor); c < l+0.3 {
+		t.Fatalf("expected cursor policy to keep the hot stream: lru %.2f, cursor %.2f", l, c)
+	}
+}
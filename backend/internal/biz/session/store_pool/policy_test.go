@@ -0,0 +1,158 @@
+package store_pool
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func keys(stream uuid.UUID, n int) []ChunkKey {
+	ks := make([]ChunkKey, n)
+	for i := range ks {
+		ks[i] = ChunkKey{Stream: stream, Index: int64(i)}
+	}
+	return ks
+}
+
+func TestLRUPolicyVictimOrder(t *testing.T) {
+	p := NewLRUPolicy()
+	ks := keys(uuid.New(), 3)
+	for _, k := range ks {
+		p.Add(k, 1)
+	}
+	p.Touch(ks[0])
+	if v, _ := p.Victim(); v != ks[1] {
+		t.Fatalf("victim %v, want %v", v, ks[1])
+	}
+	p.Remove(ks[1])
+	p.Remove(ks[2])
+	p.Remove(ks[0])
+	if _, ok := p.Victim(); ok {
+		t.Fatalf("expected no victim in empty policy")
+	}
+}
+
+// policyCache — кэш поверх политики с бюджетом в байтах: вытесняем, пока занято больше лимита, как ChunkStore
+type policyCache struct {
+	p     EvictionPolicy
+	used  int64
+	limit int64
+	in    map[ChunkKey]int64
+}
+
+func newPolicyCache(p EvictionPolicy, limit int64) *policyCache {
+	return &policyCache{p: p, limit: limit, in: make(map[ChunkKey]int64)}
+}
+
+// access — true, если чанк был в кэше
+func (c *policyCache) access(key ChunkKey, size int64) bool {
+	if _, ok := c.in[key]; ok {
+		c.p.Touch(key)
+		return true
+	}
+	c.in[key] = size
+	c.used += size
+	c.p.Add(key, size)
+	for c.used > c.limit {
+		v, ok := c.p.Victim()
+		if !ok {
+			break
+		}
+		c.p.Remove(v)
+		c.used -= c.in[v]
+		delete(c.in, v)
+	}
+	return false
+}
+
+func TestTinyLFUPolicyKeepsFrequentOverOneOffs(t *testing.T) {
+	c := newPolicyCache(NewTinyLFUPolicy(30), 30) // 3 чанка по 10
+	hot := ChunkKey{Stream: uuid.New(), Index: 0}
+	for range 6 {
+		c.access(hot, 10)
+	}
+	// поток разовых чанков (seek по холодным стримам) не вытесняет часто запрашиваемый
+	for _, k := range keys(uuid.New(), 10) {
+		c.access(k, 10)
+	}
+	if !c.access(hot, 10) {
+		t.Fatalf("frequent chunk evicted by one-off chunks")
+	}
+
+	// LRU на том же потоке его теряет
+	c = newPolicyCache(NewLRUPolicy(), 30)
+	for range 6 {
+		c.access(hot, 10)
+	}
+	for _, k := range keys(uuid.New(), 10) {
+		c.access(k, 10)
+	}
+	if c.access(hot, 10) {
+		t.Fatalf("expected LRU to evict the frequent chunk")
+	}
+}
+
+func TestTinyLFUPolicyPromotesOnHit(t *testing.T) {
+	p := NewTinyLFUPolicy(30)
+	c := newPolicyCache(p, 30)
+	ks := keys(uuid.New(), 4)
+	for _, k := range ks {
+		c.access(k, 10)
+	}
+	// переполнение перелило ks[0] из окна в испытательный сегмент
+	if e := p.items[ks[0]].Value.(*tinyLFUEntry); e.seg != segProbation {
+		t.Fatalf("expected %v in probation, got segment %d", ks[0], e.seg)
+	}
+	c.access(ks[0], 10)
+	if e := p.items[ks[0]].Value.(*tinyLFUEntry); e.seg != segProtected {
+		t.Fatalf("hit in probation must promote, got segment %d", e.seg)
+	}
+}
+
+func TestCursorPolicyProtectsChunksAheadOfViewers(t *testing.T) {
+	p := NewCursorPolicy(2)
+	hot, cold := keys(uuid.New(), 3), keys(uuid.New(), 2)
+	// LRU-порядок от старых: hot0, hot1, hot2, cold0, cold1
+	for _, k := range append(hot, cold...) {
+		p.Add(k, 1)
+	}
+	p.Held(hot[0], 1) // курсор на hot0: hot0 и следующий hot1 под защитой
+
+	for _, want := range []ChunkKey{hot[2], cold[0], cold[1]} {
+		v, _ := p.Victim()
+		if v != want {
+			t.Fatalf("victim %v, want %v", v, want)
+		}
+		p.Remove(v)
+	}
+	// остались только защищённые — вытесняем всё равно, старейший из них
+	if v, ok := p.Victim(); !ok || v != hot[0] {
+		t.Fatalf("victim %v, want %v", v, hot[0])
+	}
+	p.Held(hot[0], -1)
+	if len(p.held) != 0 {
+		t.Fatalf("released holds must be forgotten: %v", p.held)
+	}
+}
+
+func TestNewEvictionPolicy(t *testing.T) {
+	for name, want := range map[string]string{
+		"":            "*store_pool.LRUPolicy",
+		PolicyLRU:     "*store_pool.LRUPolicy",
+		PolicyTinyLFU: "*store_pool.TinyLFUPolicy",
+		PolicyCursor:  "*store_pool.CursorPolicy",
+	} {
+		p, err := NewEvictionPolicy(name, 1<<20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprintf("%T", p); got != want {
+			t.Fatalf("%q: got %s, want %s", name, got, want)
+		}
+	}
+	if _, err := NewEvictionPolicy("fifo", 1<<20); !errors.Is(err, ErrUnknownPolicy) {
+		t.Fatalf("expected ErrUnknownPolicy, got %v", err)
+	}
+}
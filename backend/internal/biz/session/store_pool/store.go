@@ -1,7 +1,6 @@
 package store_pool
 
 import (
-	"container/list"
 	"context"
 	"errors"
 	"fmt"
@@ -10,8 +9,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"golang.org/x/sync/singleflight"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // -------- Конфиг для store --------
@@ -23,6 +25,9 @@ const MaxFrameBytes = 4 << 20 // 4 MiB
 // PressureGuardFactor Когда после эвикта cap-бюджет всё ещё больше лимита в N раз — не грузим новые чанки
 const PressureGuardFactor = 2 // 200% лимита — отказываем в загрузке чанка
 
+// tracerName Имя трейсера спанов кэша (загрузки чанков и ожидания их в singleflight)
+const tracerName = "ChunkStore"
+
 // LoadChunkTimeout Таймаут на загрузку чанка из БД (для защиты от повисшей БД)
 const LoadChunkTimeout = 500 * time.Millisecond
 
@@ -36,6 +41,7 @@ type StreamMeta struct {
 	MinSeq     int64
 	MaxSeq     int64
 	Count      int64
+	Version    int64 // streams.frames_version: растёт только при правке кадров (admin-командами), не при UpdateStream
 }
 
 // Frame — один JPEG-кадр. Data — буфер из ByteBucketPool (len — реальный размер, cap — размер ведра)
@@ -52,9 +58,12 @@ type Chunk struct {
 	BytesLen int64 // сумма len(Data) — для метрик
 	BytesCap int64 // сумма cap(Data) — честный объём RAM
 
+	key     ChunkKey // ключ в кэше (для учёта удержаний политикой)
+	version int64    // версия кадров стрима на момент загрузки (0 — неизвестна, на диск такой чанк не пишем)
+
 	// atomics
 	refs    int32  // сколько клиентов держат чанк
-	evicted uint32 // снят из LRU (ожидает освобождение при refs==0)
+	evicted uint32 // снят из кэша (ожидает освобождение при refs==0)
 	freed   uint32 // буферы уже возвращены в пулы
 }
 
@@ -63,20 +72,16 @@ type ChunkKey struct {
 	Index  int64 // floor((seq - minSeq)/chunkN)
 }
 
-type lruEntry struct {
-	key   ChunkKey
-	chunk *Chunk
-}
-
-// ChunkStore — LRU-кэш чанков с бюджетом по cap-байтам и одинарной загрузкой (singleflight)
-// Все изменения LRU и учёта памяти под мьютексом
+// ChunkStore — кэш чанков с бюджетом по cap-байтам и одинарной загрузкой (singleflight)
+// Порядок вытеснения задаёт EvictionPolicy (по умолчанию LRU). Все изменения кэша и учёта памяти под мьютексом
 type ChunkStore struct {
-	db    *pgxpool.Pool
-	group singleflight.Group
+	db *pgxpool.Pool
 
-	mu    sync.Mutex
-	lru   *list.List
-	items map[ChunkKey]*list.Element
+	mu     sync.Mutex
+	items  map[ChunkKey]*Chunk
+	loads  map[ChunkKey]*chunkLoad // загрузки в полёте (singleflight по ключу чанка)
+	policy EvictionPolicy
+	holds  holdObserver // policy, если ей нужны удержания чанков сессиями; иначе nil
 
 	usedLenB int64 // метрика (сумма len)
 	usedCapB int64 // реальный бюджет (сумма cap)
@@ -85,27 +90,71 @@ type ChunkStore struct {
 	chunkN int64           // кадров в чанке (например, 256)
 	pool   *ByteBucketPool // пул буферов
 
+	disk     *DiskTier           // второй уровень на диске (nil — выключен)
+	metrics  *Metrics            // метрики кэша и сессий (noop до RegisterMetrics)
+	versions map[uuid.UUID]int64 // последняя виденная LoadStreamMeta версия кадров стрима
+
+	live *appendBroadcaster // уведомления live-сессий о дозаписи кадров
+
+	load       func(ctx context.Context, stream uuid.UUID, startSeq int64) (*Chunk, error) // loadChunk; в тестах подменяется
+	prefetchAt float64                                                                     // доля чанка, после которой сессии подгружают следующий (0 — выключено)
+
 	frameSlicePool sync.Pool // пул []Frame
 }
 
 func NewChunkStore(db *pgxpool.Pool, sizes []int, limitCapBytes int64, chunkFrames int64) *ChunkStore {
-	return &ChunkStore{
-		db:     db,
-		lru:    list.New(),
-		items:  make(map[ChunkKey]*list.Element),
-		limitB: limitCapBytes,
-		chunkN: chunkFrames,
-		pool:   NewByteBucketPool(sizes),
+	cs := &ChunkStore{
+		db:       db,
+		items:    make(map[ChunkKey]*Chunk),
+		loads:    make(map[ChunkKey]*chunkLoad),
+		policy:   NewLRUPolicy(),
+		versions: make(map[uuid.UUID]int64),
+		metrics:  noopMetrics,
+		limitB:   limitCapBytes,
+		chunkN:   chunkFrames,
+		pool:     NewByteBucketPool(sizes),
+		live:     newAppendBroadcaster(),
 		frameSlicePool: sync.Pool{
 			New: func() any { return make([]Frame, 0, int(chunkFrames)) },
 		},
 	}
+	cs.load = cs.loadChunk
+	return cs
 }
 
 func (cs *ChunkStore) ChunkSize() int64 {
 	return cs.chunkN
 }
 
+// SetEvictionPolicy — сменить политику вытеснения; вызывать до начала работы (кэш пуст)
+func (cs *ChunkStore) SetEvictionPolicy(p EvictionPolicy) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.policy = p
+	cs.holds, _ = p.(holdObserver)
+	for key, chunk := range cs.items {
+		p.Add(key, chunk.BytesCap)
+	}
+}
+
+// SetDiskTier — включить дисковый второй уровень: вытесненные полные чанки пишутся туда, промахи ищутся там до БД
+// На диск попадают только чанки стримов, для которых известна версия (LoadStreamMeta): правка стрима
+// меняет версию, и старые записи перестают совпадать. Вызывать до начала работы
+func (cs *ChunkStore) SetDiskTier(d *DiskTier) {
+	cs.disk = d
+}
+
+// SetPrefetchAt — read-ahead: дойдя до доли at текущего чанка (0 < at <= 1), сессия заранее подгружает следующий
+// 0 — без prefetch (следующий чанк грузится синхронно на границе); вызывать до начала работы
+func (cs *ChunkStore) SetPrefetchAt(at float64) {
+	cs.prefetchAt = min(max(at, 0), 1)
+}
+
+// PrefetchAt — доля чанка для read-ahead (0 — выключено)
+func (cs *ChunkStore) PrefetchAt() float64 {
+	return cs.prefetchAt
+}
+
 func (cs *ChunkStore) getFrameSlice() []Frame {
 	fs := cs.frameSlicePool.Get().([]Frame)
 	if cap(fs) < int(cs.chunkN) {
@@ -178,6 +227,17 @@ func (cs *ChunkStore) loadChunk(ctx context.Context, stream uuid.UUID, startSeq
 	}, nil
 }
 
+// chunkLoad — загрузка чанка в полёте; промахи по тому же ключу ждут её, а не идут в БД второй раз (singleflight)
+// Ссылки на чанк ведущий берёт за всех ждущих под cs.mu, в той же критической секции, где чанк становится
+// виден кэшу: между концом загрузки и пробуждением ждущих чанк не может освободиться (refs > 0)
+type chunkLoad struct {
+	done    chan struct{}
+	holders int  // ведущий + присоединившиеся; меняется только под cs.mu, пока загрузка в cs.loads
+	stale   bool // пока грузили, стрим сбросили (DropStream/InvalidateTail): в кэш не кладём
+	chunk   *Chunk
+	err     error
+}
+
 // GetChunk — вернуть чанк по желаемой sequence; увеличивает refs — вызывающий обязан ReleaseChunk
 // Против переполнения RAM: если после эвикта usedCapB > limit*PressureGuardFactor, то возвращаем ошибку
 func (cs *ChunkStore) GetChunk(ctx context.Context, stream uuid.UUID, minSeq, wantSeq int64) (*Chunk, error) {
@@ -187,80 +247,218 @@ func (cs *ChunkStore) GetChunk(ctx context.Context, stream uuid.UUID, minSeq, wa
 	idx := (wantSeq - minSeq) / cs.chunkN
 	key := ChunkKey{Stream: stream, Index: idx}
 
-	// 1) Попытка взять из LRU за O(1)
+	// 1) Попытка взять из кэша за O(1); иначе — присоединиться к загрузке в полёте или начать свою
 	cs.mu.Lock()
-	if el := cs.items[key]; el != nil {
-		cs.lru.MoveToFront(el)
-		chunk := el.Value.(*lruEntry).chunk
-		atomic.AddInt32(&chunk.refs, 1)
+	if chunk := cs.items[key]; chunk != nil {
+		cs.policy.Touch(key)
+		cs.holdLocked(chunk)
 		cs.mu.Unlock()
+		cs.metrics.requests.Add(ctx, 1, attrHit)
 		return chunk, nil
 	}
+	load := cs.loads[key]
+	leader := load == nil // эта горутина выполняет загрузку; остальные с тем же ключом её дождутся (dedup)
+	if leader {
+		load = &chunkLoad{done: make(chan struct{}), holders: 1}
+		cs.loads[key] = load
+	} else {
+		load.holders++
+	}
 	cs.mu.Unlock()
 
-	// 2) Загрузка (dedup через singleflight) + LRU put
-	v, err, _ := cs.group.Do(fmt.Sprintf("%s:%d", stream, idx), func() (any, error) {
-		// double-check под замком
-		cs.mu.Lock()
-		if el := cs.items[key]; el != nil {
-			cs.lru.MoveToFront(el)
-			chunk := el.Value.(*lruEntry).chunk
-			cs.mu.Unlock()
-			return chunk, nil
-		}
+	// 2) Загрузка + put в кэш
+	// Спан ожидания есть у каждого промаха: у ведущего внутри него спан загрузки, у остальных dedup=true
+	ctx, wait := otel.Tracer(tracerName).Start(ctx, "ChunkStore.singleflight", trace.WithAttributes(
+		attribute.String("stream_id", stream.String()),
+		attribute.Int64("chunk_index", idx),
+		attribute.Bool("dedup", !leader),
+	))
+	defer wait.End()
+	if leader {
+		cs.metrics.requests.Add(ctx, 1, attrMiss)
+		cs.loadShared(ctx, key, load, minSeq)
+	} else {
+		cs.metrics.requests.Add(ctx, 1, attrDedup)
+		<-load.done
+	}
+	if load.err != nil {
+		traceError(wait, load.err)
+		return nil, load.err
+	}
+	return load.chunk, nil
+}
+
+// loadShared — загрузка ведущим: чанк с диска или из БД, в кэш и по ссылке каждому из load.holders
+func (cs *ChunkStore) loadShared(ctx context.Context, key ChunkKey, load *chunkLoad, minSeq int64) {
+	stream := key.Stream
+
+	// Мягкая защита: если уже в 2+ раза выше бюджета — откажем до освобождения.
+	cs.mu.Lock()
+	if cs.usedCapB > cs.limitB*PressureGuardFactor {
+		cs.finishLoadLocked(key, load, nil, fmt.Errorf("%w: cap budget exceeded", ErrCachePressure))
 		cs.mu.Unlock()
+		close(load.done)
+		cs.metrics.rejections.Add(ctx, 1)
+		return
+	}
+	version := cs.versions[stream]
+	cs.mu.Unlock()
 
-		// Мягкая защита: если уже в 2+ раза выше бюджета — откажем до освобождения.
-		cs.mu.Lock()
-		if cs.usedCapB > cs.limitB*PressureGuardFactor {
+	startSeq := minSeq + key.Index*cs.chunkN
+	began := time.Now()
+	chunk := cs.loadFromDisk(key, version, startSeq)
+	if chunk != nil {
+		cs.metrics.observeLoad(began, true)
+	} else {
+		var err error
+		began = time.Now()
+		if chunk, err = cs.traceLoad(ctx, stream, startSeq); err != nil {
+			cs.mu.Lock()
+			cs.finishLoadLocked(key, load, nil, err)
 			cs.mu.Unlock()
-			return nil, errors.New("cache pressure: cap budget exceeded")
+			close(load.done)
+			return
 		}
-		cs.mu.Unlock()
+		cs.metrics.observeLoad(began, false)
+	}
+	chunk.key, chunk.version = key, version
 
-		startSeq := minSeq + idx*cs.chunkN
-		chunk, err := cs.loadChunk(ctx, stream, startSeq)
-		if err != nil {
-			return nil, err
+	cs.mu.Lock()
+	defer close(load.done)
+	defer cs.mu.Unlock()
+	cs.usedLenB += chunk.BytesLen
+	cs.usedCapB += chunk.BytesCap
+	if load.stale {
+		// Пока грузили, стрим удалили/дописали — чанк мог прочитать устаревшие кадры
+		// В кэш не кладём: отдаём как "эвикнутый", буферы вернутся в пул на ReleaseChunk
+		atomic.StoreUint32(&chunk.evicted, 1)
+		cs.finishLoadLocked(key, load, chunk, nil)
+		return
+	}
+	cs.items[key] = chunk
+	cs.policy.Add(key, chunk.BytesCap)
+	// ссылки ждущих — до эвикта: снятый политикой чанк освободится только на их ReleaseChunk
+	cs.finishLoadLocked(key, load, chunk, nil)
+
+	// Эвиктим по cap, насколько возможно
+	cs.evictLocked()
+
+	// Если и после эвикта бюджет всё ещё дико выше лимита, то не пойдём дальше
+	if cs.usedCapB > cs.limitB*PressureGuardFactor {
+		// Уберём только что вставленный элемент обратно (если его не сняла сама политика)
+		if cs.items[key] == chunk {
+			delete(cs.items, key)
+			cs.policy.Remove(key)
 		}
+		atomic.StoreUint32(&chunk.evicted, 1)
+		// ссылки ждущих отпускаем сами: им вернётся ошибка
+		for range load.holders {
+			if cs.holds != nil {
+				cs.holds.Held(key, -1)
+			}
+			atomic.AddInt32(&chunk.refs, -1)
+		}
+		load.chunk, load.err = nil, fmt.Errorf("%w: over budget after eviction", ErrCachePressure)
 
-		cs.mu.Lock()
-		el := cs.lru.PushFront(&lruEntry{key: key, chunk: chunk})
-		cs.items[key] = el
-		cs.usedLenB += chunk.BytesLen
-		cs.usedCapB += chunk.BytesCap
+		// Буферы и учёт — только через tryFinalizeChunkLocked: если эвикт успел поставить чанк в очередь
+		// на диск, запись держит ссылку, и чанк освободится после неё (на unref)
+		cs.tryFinalizeChunkLocked(chunk)
+		cs.metrics.rejections.Add(ctx, 1)
+	}
+}
 
-		// Эвиктим по cap, насколько возможно
-		cs.evictLocked()
+// finishLoadLocked — загрузка завершена: снять её из cs.loads и взять по ссылке на каждого ждущего (под мьютексом)
+// После снятия к ней больше никто не присоединится, поэтому holders окончателен
+func (cs *ChunkStore) finishLoadLocked(key ChunkKey, load *chunkLoad, chunk *Chunk, err error) {
+	delete(cs.loads, key)
+	load.chunk, load.err = chunk, err
+	if chunk == nil {
+		return
+	}
+	for range load.holders {
+		cs.holdLocked(chunk)
+	}
+}
 
-		// Если и после эвикта бюджет всё ещё дико выше лимита, то не пойдём дальше
-		if cs.usedCapB > cs.limitB*PressureGuardFactor {
-			// Уберём только что вставленный элемент обратно
-			delete(cs.items, key)
-			cs.lru.Remove(el)
-			cs.usedLenB -= chunk.BytesLen
-			cs.usedCapB -= chunk.BytesCap
-			cs.mu.Unlock()
+// traceLoad — загрузка чанка из БД (cs.load) в своём спане
+func (cs *ChunkStore) traceLoad(ctx context.Context, stream uuid.UUID, startSeq int64) (*Chunk, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ChunkStore.loadChunk", trace.WithAttributes(
+		attribute.String("stream_id", stream.String()),
+		attribute.Int64("start_seq", startSeq),
+	))
+	defer span.End()
+	chunk, err := cs.load(ctx, stream, startSeq)
+	if err != nil {
+		traceError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("frames", len(chunk.Frames)), attribute.Int64("bytes", chunk.BytesLen))
+	return chunk, nil
+}
 
-			// Вернём буферы (чтобы не протечь)
-			for i := range chunk.Frames {
-				cs.pool.Put(chunk.Frames[i].Data)
-				chunk.Frames[i].Data = nil
-			}
-			cs.putFrameSlice(chunk.Frames)
-			return nil, errors.New("cache pressure: over budget after eviction")
-		}
+// traceError — ошибка в спане (как в обёртках internal/wrapper)
+func traceError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetAttributes(
+		attribute.String("event", "error"),
+		attribute.String("message", err.Error()),
+	)
+}
 
-		cs.mu.Unlock()
-		return chunk, nil
-	})
+// loadFromDisk — чанк из дискового уровня; nil — нет (или запись битая/устарела): грузим из БД
+func (cs *ChunkStore) loadFromDisk(key ChunkKey, version, startSeq int64) *Chunk {
+	if cs.disk == nil || version == 0 {
+		return nil
+	}
+	body, err := cs.disk.read(key, version, startSeq)
+	if err != nil || body == nil {
+		return nil
+	}
+	chunk, err := cs.decodeDiskChunk(body, startSeq)
 	if err != nil {
-		return nil, err
+		return nil
 	}
+	return chunk
+}
 
-	chunk := v.(*Chunk)
+// holdLocked — refs++ и учёт удержания для политики (под мьютексом)
+func (cs *ChunkStore) holdLocked(chunk *Chunk) {
 	atomic.AddInt32(&chunk.refs, 1)
-	return chunk, nil
+	if cs.holds != nil {
+		cs.holds.Held(chunk.key, 1)
+	}
+}
+
+// Prefetch — фоновая загрузка чанка с wantSeq в кэш, если его там нет (read-ahead сессий)
+// Загрузка та же, что у GetChunk (singleflight по ключу чанка): сколько бы сессий ни попросили чанк, запрос в БД один,
+// а сессия, дошедшая до границы раньше конца prefetch, просто дождётся этой загрузки
+// Под давлением на кэш (занято больше бюджета — эвикт упирается в удерживаемые чанки) не грузим:
+// read-ahead не должен вытеснять то, что смотрят сейчас. Возвращает, запущена ли загрузка
+func (cs *ChunkStore) Prefetch(ctx context.Context, stream uuid.UUID, minSeq, wantSeq int64) bool {
+	if wantSeq < minSeq {
+		wantSeq = minSeq
+	}
+	key := ChunkKey{Stream: stream, Index: (wantSeq - minSeq) / cs.chunkN}
+
+	cs.mu.Lock()
+	_, cached := cs.items[key]
+	pressure := cs.usedCapB > cs.limitB
+	cs.mu.Unlock()
+	if cached || pressure {
+		return false
+	}
+
+	// сессия может уйти раньше, чем чанк догрузится, а к загрузке уже присоединились другие — не отменяем её вместе с сессией
+	// (сверху загрузку всё равно ограничивает LoadChunkTimeout)
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		// GetChunk+Release, а не голая загрузка: если чанк вернулся "эвикнутым" (гонка с DropStream), он освободится здесь
+		// Ссылки остальных ждущих взяты до пробуждения, поэтому ранний Release prefetch их чанк не освобождает
+		if chunk, err := cs.GetChunk(ctx, stream, minSeq, wantSeq); err == nil {
+			cs.ReleaseChunk(chunk)
+		}
+	}()
+	return true
 }
 
 // ReleaseChunk — уменьшаем refs; если чанк эвикнут и refs==0, то освобождаем буферы в пулы
@@ -268,6 +466,16 @@ func (cs *ChunkStore) ReleaseChunk(chunk *Chunk) {
 	if chunk == nil {
 		return
 	}
+	if cs.holds != nil {
+		cs.mu.Lock()
+		cs.holds.Held(chunk.key, -1)
+		cs.mu.Unlock()
+	}
+	cs.unref(chunk)
+}
+
+// unref — refs--; эвикнутый и никем не удерживаемый чанк освобождается
+func (cs *ChunkStore) unref(chunk *Chunk) {
 	if atomic.AddInt32(&chunk.refs, -1) == 0 && atomic.LoadUint32(&chunk.evicted) == 1 {
 		cs.mu.Lock()
 		cs.tryFinalizeChunkLocked(chunk)
@@ -275,32 +483,137 @@ func (cs *ChunkStore) ReleaseChunk(chunk *Chunk) {
 	}
 }
 
-// evictLocked — снимаем хвостовые элементы LRU, пока usedCapB > limitB
+var (
+	// ErrFrameNotFound кадра с такой sequence нет (вне диапазона или дырка)
+	ErrFrameNotFound = errors.New("frame not found")
+	// ErrStreamNotFound стрима нет в БД
+	ErrStreamNotFound = errors.New("stream not found")
+	// ErrCachePressure кэш далеко за бюджетом: новый чанк не грузим, пока сессии не отпустят старые
+	ErrCachePressure = errors.New("cache pressure")
+)
+
+// GetFrame — один кадр через общий с сессиями кэш чанков
+// Data живёт в буфере чанка: пока кадр используется, чанк нужно держать, затем ReleaseChunk
+func (cs *ChunkStore) GetFrame(ctx context.Context, stream uuid.UUID, minSeq, seq int64) (Frame, *Chunk, error) {
+	if seq < minSeq {
+		return Frame{}, nil, ErrFrameNotFound
+	}
+	chunk, err := cs.GetChunk(ctx, stream, minSeq, seq)
+	if err != nil {
+		return Frame{}, nil, err
+	}
+	for _, f := range chunk.Frames {
+		if f.Seq == seq {
+			return f, chunk, nil
+		}
+	}
+	cs.ReleaseChunk(chunk)
+	return Frame{}, nil, ErrFrameNotFound
+}
+
+// DropStream — снять из кэша все чанки стрима (например, после удаления стрима)
+// Чанки, которые ещё держат активные сессии, остаются валидными для них и освобождаются на ReleaseChunk
+func (cs *ChunkStore) DropStream(stream uuid.UUID) {
+	cs.dropChunks(stream, false)
+	cs.mu.Lock()
+	delete(cs.versions, stream)
+	cs.mu.Unlock()
+	cs.live.closeStream(stream)
+}
+
+// InvalidateTail — снять из кэша неполные чанки стрима (после дозаписи кадров в конец)
+// Полный чанк (chunkN кадров) дозапись не меняет: новые sequence всегда больше уже существующих
+func (cs *ChunkStore) InvalidateTail(stream uuid.UUID) {
+	cs.dropChunks(stream, true)
+}
+
+// FramesAppended — в стрим дописаны кадры до maxSeq включительно
+// Сбрасываем неполный хвостовой чанк и будим live-сессии этого стрима
+func (cs *ChunkStore) FramesAppended(stream uuid.UUID, maxSeq int64) {
+	cs.InvalidateTail(stream)
+	cs.live.publish(stream, maxSeq)
+}
+
+// SubscribeAppends — подписка live-сессии на дозапись кадров в стрим
+// В канал приходит свежий max_seq; канал закрывается при удалении стрима. cancel вызывать обязательно
+func (cs *ChunkStore) SubscribeAppends(stream uuid.UUID) (<-chan int64, func()) {
+	return cs.live.subscribe(stream)
+}
+
+func (cs *ChunkStore) dropChunks(stream uuid.UUID, partialOnly bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.dropChunksLocked(stream, partialOnly)
+}
+
+func (cs *ChunkStore) dropChunksLocked(stream uuid.UUID, partialOnly bool) {
+	// загрузки стрима в полёте могли прочитать кадры до сброса; загрузки других стримов сброс не задевает
+	for key, load := range cs.loads {
+		if key.Stream == stream {
+			load.stale = true
+		}
+	}
+	if cs.disk != nil && !partialOnly {
+		cs.disk.DropStream(stream) // на диске только полные чанки: дозапись их не трогает
+	}
+	for key, chunk := range cs.items {
+		if key.Stream != stream {
+			continue
+		}
+		if partialOnly && int64(len(chunk.Frames)) >= cs.chunkN {
+			continue
+		}
+
+		delete(cs.items, key)
+		cs.policy.Remove(key)
+		atomic.StoreUint32(&chunk.evicted, 1)
+		cs.metrics.evictions.Add(context.Background(), 1, attrEvictDrop)
+
+		cs.tryFinalizeChunkLocked(chunk)
+	}
+}
+
+// evictLocked — снимаем выбранные политикой чанки, пока usedCapB > limitB
 // Буферы реально освобождаются только при refs==0 (иначе ждём ReleaseChunk)
 func (cs *ChunkStore) evictLocked() {
 	for cs.usedCapB > cs.limitB {
-		el := cs.lru.Back()
-		if el == nil {
+		key, ok := cs.policy.Victim()
+		if !ok {
 			break
 		}
-		entry := el.Value.(*lruEntry)
-		chunk := entry.chunk
-
-		delete(cs.items, entry.key)
-		cs.lru.Remove(el)
+		chunk := cs.items[key]
+		delete(cs.items, key)
+		cs.policy.Remove(key)
+		if chunk == nil {
+			continue // политика рассинхронизирована с кэшем — просто забываем ключ
+		}
 		atomic.StoreUint32(&chunk.evicted, 1)
+		cs.metrics.evictions.Add(context.Background(), 1, attrEvictBudget)
+		cs.spillLocked(key, chunk)
 
 		// Пробуем освободить прямо сейчас, если никто не держит
 		cs.tryFinalizeChunkLocked(chunk)
 	}
 }
 
+// spillLocked — вытесненный полный чанк в очередь на запись в дисковый уровень (если его там ещё нет)
+// На время записи очередь держит ссылку: буферы вернутся в пул после записи
+func (cs *ChunkStore) spillLocked(key ChunkKey, chunk *Chunk) {
+	if cs.disk == nil || chunk.version == 0 || int64(len(chunk.Frames)) < cs.chunkN || cs.disk.Has(key, chunk.version) {
+		return
+	}
+	atomic.AddInt32(&chunk.refs, 1)
+	if !cs.disk.spill(key, chunk.version, chunk, cs.unref) {
+		atomic.AddInt32(&chunk.refs, -1)
+	}
+}
+
 // tryFinalizeChunkLocked — вернуть буферы в пулы и скорректировать учёт (под мьютексом)
 func (cs *ChunkStore) tryFinalizeChunkLocked(chunk *Chunk) {
 	if atomic.LoadUint32(&chunk.freed) == 1 {
 		return
 	}
-	// освобождаем только если снят с LRU и никто не держит
+	// освобождаем только если снят из кэша и никто не держит
 	if atomic.LoadUint32(&chunk.evicted) != 1 || atomic.LoadInt32(&chunk.refs) != 0 {
 		return
 	}
@@ -334,14 +647,30 @@ func (cs *ChunkStore) LoadStreamMeta(ctx context.Context, id uuid.UUID) (StreamM
             s.frame_interval_ms,
             COALESCE(MIN(f.sequence), 0)  AS min_seq,
             COALESCE(MAX(f.sequence), -1) AS max_seq,
-            COALESCE(COUNT(f.sequence), 0) AS cnt
+            COALESCE(COUNT(f.sequence), 0) AS cnt,
+            s.frames_version
         FROM streams s
         LEFT JOIN frames f ON f.stream_id = s.id
         WHERE s.id = $1
-        GROUP BY s.id, s.frame_interval_ms
+        GROUP BY s.id, s.frame_interval_ms, s.frames_version
     `, id)
-	if err := row.Scan(&m.IntervalMS, &m.MinSeq, &m.MaxSeq, &m.Count); err != nil {
-		return m, errors.New("stream not found")
+	if err := row.Scan(&m.IntervalMS, &m.MinSeq, &m.MaxSeq, &m.Count, &m.Version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m, ErrStreamNotFound
+		}
+		return m, fmt.Errorf("load stream meta: %w", err)
 	}
+	cs.NoteStreamVersion(id, m.Version)
 	return m, nil
 }
+
+// NoteStreamVersion — запомнить версию кадров стрима; если она сменилась (кадры правили, в том числе из другого процесса
+// admin-командой), чанки стрима снимаются из кэша, а записи на диске перестают подходить
+func (cs *ChunkStore) NoteStreamVersion(id uuid.UUID, version int64) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if old, ok := cs.versions[id]; ok && old != version {
+		cs.dropChunksLocked(id, false)
+	}
+	cs.versions[id] = version
+}
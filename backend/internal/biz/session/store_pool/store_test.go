@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -27,7 +28,7 @@ func makeFrame(cs *ChunkStore, seq int64, size int) Frame {
 	}
 }
 
-// helper to push a chunk directly into LRU/map (unit test within same package)
+// helper to push a chunk directly into cache map/policy (unit test within same package)
 func addChunk(cs *ChunkStore, key ChunkKey, frames []Frame) *Chunk {
 	ch := &Chunk{
 		StartSeq: key.Index*cs.chunkN + 0,
@@ -38,13 +39,9 @@ func addChunk(cs *ChunkStore, key ChunkKey, frames []Frame) *Chunk {
 		ch.BytesCap += int64(cap(f.Data))
 	}
 	cs.mu.Lock()
-	el := &lruEntry{
-		key:   key,
-		chunk: ch,
-	}
-
-	le := cs.lru.PushFront(el)
-	cs.items[key] = le
+	ch.key = key
+	cs.items[key] = ch
+	cs.policy.Add(key, ch.BytesCap)
 	cs.usedLenB += ch.BytesLen
 	cs.usedCapB += ch.BytesCap
 	cs.mu.Unlock()
@@ -152,3 +149,254 @@ func TestPressureGuardBlocksLoads(t *testing.T) {
 		t.Fatalf("expected cache pressure error, got nil")
 	}
 }
+
+func TestDropStreamKeepsHeldChunksUntilRelease(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	stream := uuid.New()
+	other := uuid.New()
+
+	held := addChunk(cs, ChunkKey{Stream: stream, Index: 0}, []Frame{makeFrame(cs, 0, 100)})
+	idle := addChunk(cs, ChunkKey{Stream: stream, Index: 1}, []Frame{makeFrame(cs, 4, 100)})
+	kept := addChunk(cs, ChunkKey{Stream: other, Index: 0}, []Frame{makeFrame(cs, 0, 100)})
+
+	c, _ := cs.GetChunk(context.Background(), stream, 0, 0)
+	if c != held {
+		t.Fatal("unexpected chunk returned")
+	}
+
+	cs.DropStream(stream)
+
+	if _, ok := cs.items[ChunkKey{Stream: stream, Index: 0}]; ok {
+		t.Fatal("dropped stream chunks must leave the LRU")
+	}
+	if atomic.LoadUint32(&idle.freed) != 1 {
+		t.Fatal("unreferenced chunk should be freed immediately")
+	}
+	if atomic.LoadUint32(&held.freed) == 1 || held.Frames[0].Data == nil {
+		t.Fatal("held chunk must not be freed while refs > 0")
+	}
+	if atomic.LoadUint32(&kept.evicted) == 1 {
+		t.Fatal("other streams must not be touched")
+	}
+
+	cs.ReleaseChunk(c)
+	if atomic.LoadUint32(&held.freed) != 1 {
+		t.Fatal("held chunk should be freed after release")
+	}
+	if cs.usedCapB != kept.BytesCap {
+		t.Fatalf("only the other stream should remain accounted, got cap=%d want %d", cs.usedCapB, kept.BytesCap)
+	}
+}
+
+func TestGetFrameFromCachedChunk(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	stream := uuid.New()
+	ch := addChunk(cs, ChunkKey{Stream: stream, Index: 0}, []Frame{makeFrame(cs, 0, 10), makeFrame(cs, 2, 20)})
+
+	f, held, err := cs.GetFrame(context.Background(), stream, 0, 2)
+	if err != nil || held != ch || len(f.Data) != 20 {
+		t.Fatalf("got frame seq=%d len=%d chunk=%p err=%v", f.Seq, len(f.Data), held, err)
+	}
+	if atomic.LoadInt32(&ch.refs) != 1 {
+		t.Fatal("returned frame must hold its chunk")
+	}
+	cs.ReleaseChunk(held)
+
+	// дырка в sequence — не найден, чанк отпущен
+	if _, _, err = cs.GetFrame(context.Background(), stream, 0, 1); err != ErrFrameNotFound {
+		t.Fatalf("expected ErrFrameNotFound, got %v", err)
+	}
+	if atomic.LoadInt32(&ch.refs) != 0 {
+		t.Fatal("chunk must be released when frame is missing")
+	}
+}
+
+func TestPrefetchSharesLoadWithGetChunk(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	cs.SetPrefetchAt(0.5)
+	stream := uuid.New()
+
+	var calls atomic.Int32
+	gate := make(chan struct{})
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		calls.Add(1)
+		<-gate
+		return &Chunk{StartSeq: startSeq, Frames: []Frame{makeFrame(cs, startSeq, 10)}}, nil
+	})
+
+	if !cs.Prefetch(context.Background(), stream, 0, 5) {
+		t.Fatalf("expected prefetch to start")
+	}
+	// сессия дошла до границы раньше конца prefetch — ждёт ту же загрузку, а не запускает вторую
+	done := make(chan *Chunk)
+	go func() {
+		ch, err := cs.GetChunk(context.Background(), stream, 0, 4)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- ch
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(gate)
+	ch := <-done
+	if ch == nil || ch.StartSeq != 4 {
+		t.Fatalf("unexpected chunk %+v", ch)
+	}
+	cs.ReleaseChunk(ch)
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected single load, got %d", n)
+	}
+	// уже в кэше — повторно не грузим
+	if cs.Prefetch(context.Background(), stream, 0, 7) {
+		t.Fatalf("expected no prefetch for cached chunk")
+	}
+}
+
+func TestPrefetchSkippedUnderPressure(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	SetLoaderForTest(cs, func(context.Context, uuid.UUID, int64) (*Chunk, error) {
+		t.Error("unexpected load under pressure")
+		return nil, context.Canceled
+	})
+	cs.mu.Lock()
+	cs.usedCapB = cs.limitB + 1
+	cs.mu.Unlock()
+
+	if cs.Prefetch(context.Background(), uuid.New(), 0, 4) {
+		t.Fatalf("expected prefetch to be skipped under pressure")
+	}
+}
+
+func TestLoadedChunkEvictedByPolicyStaysValidUntilRelease(t *testing.T) {
+	cs := newTestStore(20<<10, 4) // бюджет меньше одного ведра: политика тут же снимает загруженный чанк
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		f := makeFrame(cs, startSeq, 10)
+		return &Chunk{StartSeq: startSeq, Frames: []Frame{f}, BytesLen: 10, BytesCap: int64(cap(f.Data))}, nil
+	})
+
+	ch, err := cs.GetChunk(context.Background(), uuid.New(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ch.Frames) != 1 || ch.Frames[0].Data == nil || atomic.LoadUint32(&ch.freed) == 1 {
+		t.Fatalf("chunk returned to caller must not be freed: %+v", ch)
+	}
+	if len(cs.items) != 0 {
+		t.Fatalf("chunk over budget must not stay cached")
+	}
+	cs.ReleaseChunk(ch)
+	if atomic.LoadUint32(&ch.freed) != 1 || cs.usedCapB != 0 {
+		t.Fatalf("expected chunk freed on release, cap=%d", cs.usedCapB)
+	}
+}
+
+func TestDropDuringLoadAffectsOnlyItsStream(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	gate, entered := make(chan struct{}), make(chan struct{}, 2)
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		entered <- struct{}{}
+		<-gate
+		return diskChunk(cs, startSeq, 2, 100), nil // неполный хвостовой чанк
+	})
+	other, dropped := uuid.New(), uuid.New()
+
+	done := make(chan *Chunk, 2)
+	for _, stream := range []uuid.UUID{other, dropped} {
+		go func() {
+			ch, err := cs.GetChunk(context.Background(), stream, 0, 0)
+			if err != nil {
+				t.Error(err)
+			}
+			done <- ch
+		}()
+	}
+	<-entered
+	<-entered
+	// пока обе загрузки в полёте, дописали кадры только в один стрим
+	cs.FramesAppended(dropped, 10)
+	close(gate)
+	cs.ReleaseChunk(<-done)
+	cs.ReleaseChunk(<-done)
+
+	cs.mu.Lock()
+	_, otherCached := cs.items[ChunkKey{Stream: other}]
+	_, droppedCached := cs.items[ChunkKey{Stream: dropped}]
+	cs.mu.Unlock()
+	if !otherCached || droppedCached {
+		t.Fatalf("expected only the appended stream's load to skip the cache: other %v, dropped %v", otherCached, droppedCached)
+	}
+}
+
+func TestLiveAppendDuringSharedPrefetchKeepsSessionChunk(t *testing.T) {
+	for range 50 {
+		cs := newTestStore(1<<20, 4)
+		cs.SetPrefetchAt(0.5)
+		stream := uuid.New()
+		key := ChunkKey{Stream: stream, Index: 1}
+		gate := make(chan struct{})
+		SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+			<-gate
+			return diskChunk(cs, startSeq, 2, 100), nil // неполный хвостовой чанк live-стрима
+		})
+
+		if !cs.Prefetch(context.Background(), stream, 0, 4) {
+			t.Fatal("expected prefetch to start")
+		}
+		done := make(chan *Chunk)
+		go func() {
+			ch, err := cs.GetChunk(context.Background(), stream, 0, 4)
+			if err != nil {
+				t.Error(err)
+			}
+			done <- ch
+		}()
+		// сессия встала в ту же загрузку, что и prefetch
+		for {
+			cs.mu.Lock()
+			load := cs.loads[key]
+			joined := load != nil && load.holders == 2
+			cs.mu.Unlock()
+			if joined {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		// flush ingest во время загрузки: чанк уйдёт "эвикнутым", а prefetch отпустит его сразу
+		cs.FramesAppended(stream, 5)
+		close(gate)
+
+		ch := <-done
+		for atomic.LoadInt32(&ch.refs) != 1 {
+			time.Sleep(time.Millisecond)
+		}
+		if atomic.LoadUint32(&ch.freed) == 1 || len(ch.Frames) != 2 || ch.Frames[0].Data == nil {
+			t.Fatalf("session chunk freed by prefetch release: %+v", ch)
+		}
+		cs.ReleaseChunk(ch)
+		if atomic.LoadUint32(&ch.freed) != 1 {
+			t.Fatal("expected chunk freed after the last release")
+		}
+	}
+}
+
+func TestStreamBookkeepingDoesNotOutliveStream(t *testing.T) {
+	cs := newTestStore(1<<20, 4)
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		return diskChunk(cs, startSeq, 2, 100), nil
+	})
+	stream := uuid.New()
+	cs.NoteStreamVersion(stream, 1)
+	ch, err := cs.GetChunk(context.Background(), stream, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.ReleaseChunk(ch)
+	cs.FramesAppended(stream, 2)
+	cs.DropStream(stream)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if len(cs.loads) != 0 || len(cs.versions) != 0 || len(cs.items) != 0 {
+		t.Fatalf("stream state left behind: loads %d, versions %d, items %d", len(cs.loads), len(cs.versions), len(cs.items))
+	}
+}
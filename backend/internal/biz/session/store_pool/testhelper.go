@@ -1,13 +1,24 @@
 package store_pool
 
-// InjectChunkForTest Test-only helper to let other packages (httpapi tests) prefill the LRU.
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// InjectChunkForTest Test-only helper to let other packages (httpapi tests) prefill the cache.
 // This is compiled only during `go test` due to the build tag above.
 func InjectChunkForTest(key ChunkKey, ch *Chunk, cs *ChunkStore) {
 	cs.mu.Lock()
-	el := &lruEntry{key: key, chunk: ch}
-	le := cs.lru.PushFront(el)
-	cs.items[key] = le
+	ch.key = key
+	cs.items[key] = ch
+	cs.policy.Add(key, ch.BytesCap)
 	cs.usedLenB += ch.BytesLen
 	cs.usedCapB += ch.BytesCap
 	cs.mu.Unlock()
 }
+
+// SetLoaderForTest подменяет загрузку чанка из БД (например, медленной загрузкой для тестов prefetch)
+func SetLoaderForTest(cs *ChunkStore, load func(ctx context.Context, stream uuid.UUID, startSeq int64) (*Chunk, error)) {
+	cs.load = load
+}
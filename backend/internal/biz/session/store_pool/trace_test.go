@@ -0,0 +1,121 @@
+package store_pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans — глобальный провайдер, пишущий спаны в память (кэш берёт трейсер через otel.Tracer)
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return rec
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestChunkStoreTracesLoadsAndWaits(t *testing.T) {
+	rec := recordSpans(t)
+	cs := newTestStore(1<<20, 4)
+	gate, entered := make(chan struct{}), make(chan struct{}, 1)
+	SetLoaderForTest(cs, func(_ context.Context, _ uuid.UUID, startSeq int64) (*Chunk, error) {
+		select {
+		case entered <- struct{}{}:
+		default:
+		}
+		<-gate
+		return diskChunk(cs, startSeq, 4, 100), nil
+	})
+	stream := uuid.New()
+
+	// три одновременных промаха: у каждого спан ожидания, загрузка одна — внутри спана ведущего
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch, err := cs.GetChunk(context.Background(), stream, 0, 0)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			cs.ReleaseChunk(ch)
+		}()
+	}
+	<-entered
+	time.Sleep(50 * time.Millisecond) // остальные успевают встать в singleflight
+	close(gate)
+	wg.Wait()
+
+	waits := map[bool][]sdktrace.ReadOnlySpan{}
+	var loads []sdktrace.ReadOnlySpan
+	for _, span := range rec.Ended() {
+		switch span.Name() {
+		case "ChunkStore.singleflight":
+			dedup, _ := spanAttr(span, "dedup")
+			waits[dedup.AsBool()] = append(waits[dedup.AsBool()], span)
+		case "ChunkStore.loadChunk":
+			loads = append(loads, span)
+		}
+	}
+	if len(loads) != 1 || len(waits[false]) != 1 || len(waits[true]) != 2 {
+		t.Fatalf("expected 1 load, 1 leading wait and 2 dedup waits, got %d, %d, %d", len(loads), len(waits[false]), len(waits[true]))
+	}
+	if loads[0].Parent().SpanID() != waits[false][0].SpanContext().SpanID() {
+		t.Fatalf("load span must be a child of the leader's wait span")
+	}
+	if seq, _ := spanAttr(loads[0], "start_seq"); seq.AsInt64() != 0 {
+		t.Fatalf("unexpected start_seq %v", seq.AsInt64())
+	}
+	if id, _ := spanAttr(waits[true][0], "stream_id"); id.AsString() != stream.String() {
+		t.Fatalf("unexpected stream_id %q", id.AsString())
+	}
+
+	// попадание в кэш спанов не даёт
+	ch, err := cs.GetChunk(context.Background(), stream, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.ReleaseChunk(ch)
+	if n := len(rec.Ended()); n != 4 {
+		t.Fatalf("cache hit must not be traced, got %d spans", n)
+	}
+}
+
+func TestChunkStoreTracesLoadError(t *testing.T) {
+	rec := recordSpans(t)
+	cs := newTestStore(1<<20, 4)
+	SetLoaderForTest(cs, func(context.Context, uuid.UUID, int64) (*Chunk, error) {
+		return nil, errors.New("db down")
+	})
+	if _, err := cs.GetChunk(context.Background(), uuid.New(), 0, 0); err == nil {
+		t.Fatal("expected load error")
+	}
+	for _, span := range rec.Ended() {
+		if msg, _ := spanAttr(span, "message"); msg.AsString() != "db down" || len(span.Events()) != 1 {
+			t.Fatalf("%s: load error must be recorded", span.Name())
+		}
+	}
+	if n := len(rec.Ended()); n != 2 {
+		t.Fatalf("expected wait and load spans, got %d", n)
+	}
+}
@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// runTraced — Run внутри спана (как под обёрткой WS-ручки); вернёт завершённый спан сессии
+func runTraced(t *testing.T, s *StreamSession) (sdktrace.ReadOnlySpan, error) {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test").Start(s.ctx, "session")
+	s.ctx = ctx
+	err := s.Run()
+	span.End()
+	return rec.Ended()[0], err
+}
+
+func sessionAttrs(span sdktrace.ReadOnlySpan) map[string]any {
+	res := map[string]any{}
+	for _, kv := range span.Attributes() {
+		res[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return res
+}
+
+func TestStreamSessionTracesSummary(t *testing.T) {
+	s, _, cancel := controlSession(t, 12, time.Millisecond)
+	defer cancel()
+	s.out = slowWriter{delay: 5 * time.Millisecond}
+
+	span, err := runTraced(t, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := sessionAttrs(span)
+	delivered, _ := attrs["frames.delivered"].(int64)
+	skipped, _ := attrs["frames.skipped"].(int64)
+	if attrs["close_reason"] != CloseEndOfStream || attrs["stream_id"] != s.cm.streamID.String() {
+		t.Fatalf("unexpected span attributes %v", attrs)
+	}
+	// skipped — за всю сессию, а не с последней отправки
+	if skipped == 0 || delivered+skipped != 12 {
+		t.Fatalf("expected delivered+skipped = 12 with skips, got %v", attrs)
+	}
+	if span.Status().Code == codes.Error {
+		t.Fatalf("end of stream is not an error")
+	}
+}
+
+func TestStreamSessionTracesCloseReason(t *testing.T) {
+	t.Run("stop", func(t *testing.T) {
+		s, _, cancel := controlSession(t, 12, time.Hour)
+		defer cancel()
+		if err := s.Submit(Command{Kind: CmdStop}); err != nil {
+			t.Fatal(err)
+		}
+		span, _ := runTraced(t, s)
+		if got := sessionAttrs(span)["close_reason"]; got != CloseStopped {
+			t.Fatalf("expected %q, got %v", CloseStopped, got)
+		}
+	})
+	t.Run("client gone", func(t *testing.T) {
+		s, _, cancel := controlSession(t, 12, time.Hour)
+		cancel()
+		span, _ := runTraced(t, s)
+		if got := sessionAttrs(span)["close_reason"]; got != CloseClientGone || span.Status().Code == codes.Error {
+			t.Fatalf("expected %q without error status, got %v %v", CloseClientGone, got, span.Status())
+		}
+	})
+	t.Run("write error", func(t *testing.T) {
+		s, _, cancel := controlSession(t, 12, time.Hour)
+		defer cancel()
+		s.out = slowWriter{fail: true}
+		span, _ := runTraced(t, s)
+		if got := sessionAttrs(span)["close_reason"]; got != CloseWriteError || span.Status().Code != codes.Error {
+			t.Fatalf("expected %q with error status, got %v %v", CloseWriteError, got, span.Status())
+		}
+	})
+}
@@ -2,24 +2,45 @@ package biz
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
 	v1 "stream-server/api/v1"
 	"stream-server/internal/converters"
 )
 
-// ListStreams gets streams
-func (u *StreamUsecase) ListStreams(ctx context.Context, _ *v1.ListStreamsRequest) (_ []*v1.Stream, err error) {
-	streamRows, err := u.repo.ListStreams(ctx)
+// ListStreams gets streams page
+func (u *StreamUsecase) ListStreams(ctx context.Context, in *v1.ListStreamsRequest) (_ []*v1.Stream, nextToken string, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	oldestFirst, err := parseOrderBy(in.OrderBy)
+	if err != nil {
+		return nil, "", err
+	}
+	params, size, err := listStreamsParams(in.PageSize, in.Search, in.PageToken, oldestFirst)
+	if err != nil {
+		return nil, "", err
+	}
+
+	streamRows, err := u.repo.ListStreams(ctx, params, oldestFirst)
 	if err != nil {
-		return nil, fmt.Errorf("error get streams: %w", err)
+		return nil, "", fmt.Errorf("error get streams: %w", err)
+	}
+	if len(streamRows) > size {
+		streamRows = streamRows[:size]
+		nextToken = nextPageToken(streamRows[size-1], in.Search, oldestFirst)
 	}
 
-	return converters.ToApiStreamResponseList(streamRows), nil
+	return converters.ToApiStreamResponseList(streamRows), nextToken, nil
 }
 
 // GetStream get stream by ID
 func (u *StreamUsecase) GetStream(ctx context.Context, in *v1.GetStreamRequest) (res *v1.Stream, err error) {
+	defer func() { err = ToApiError(err) }()
+
 	uuid, err := converters.StringToPgUUID(in.Id)
 	if err != nil {
 		return nil, fmt.Errorf("error converting uuid: %w", err)
@@ -33,17 +54,71 @@ func (u *StreamUsecase) GetStream(ctx context.Context, in *v1.GetStreamRequest)
 	return converters.ToApiStreamResponse(stream), nil
 }
 
-// UpdateStream update stream
+// UpdateStream update stream fields listed in update_mask
 func (u *StreamUsecase) UpdateStream(ctx context.Context, in *v1.UpdateStreamRequest) (res *v1.Stream, err error) {
-	params, err := converters.ToDbUpdateStreamParams(in)
+	defer func() { err = ToApiError(err) }()
+
+	paths, err := updatePaths(in)
+	if err != nil {
+		return nil, err
+	}
+	params, err := converters.ToDbUpdateStreamParams(in, paths)
 	if err != nil {
 		return nil, fmt.Errorf("error converting params: %w", err)
 	}
+	if in.Etag != "" {
+		version, ok := converters.ParseETag(in.Etag)
+		if !ok {
+			return nil, v1.ErrorStreamVersionMismatch("etag %q does not match stream %s", in.Etag, in.Id)
+		}
+		params.ExpectedVersion = pgtype.Int8{Int64: version, Valid: true}
+	}
 
 	stream, err := u.repo.UpdateStream(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) && params.ExpectedVersion.Valid {
+		// строку не обновили: либо стрима нет, либо версия уже другая
+		if current, getErr := u.repo.GetStream(ctx, params.ID); getErr == nil {
+			return nil, v1.ErrorStreamVersionMismatch("stream %s was modified: etag %q, current %q",
+				in.Id, in.Etag, converters.ETag(current.Version))
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error update stream: %w", err)
 	}
 
 	return converters.ToApiStreamUpdateResult(stream), nil
 }
+
+// CreateStream create stream
+func (u *StreamUsecase) CreateStream(ctx context.Context, in *v1.CreateStreamRequest) (res *v1.Stream, err error) {
+	defer func() { err = ToApiError(err) }()
+
+	stream, err := u.repo.CreateStream(ctx, converters.ToDbCreateStreamParams(in))
+	if err != nil {
+		return nil, fmt.Errorf("error create stream: %w", err)
+	}
+
+	return converters.ToApiStreamCreateResult(stream), nil
+}
+
+// DeleteStream delete stream by ID
+func (u *StreamUsecase) DeleteStream(ctx context.Context, in *v1.DeleteStreamRequest) (err error) {
+	defer func() { err = ToApiError(err) }()
+
+	uuid, err := converters.StringToPgUUID(in.Id)
+	if err != nil {
+		return fmt.Errorf("error converting uuid: %w", err)
+	}
+
+	if err = u.repo.DeleteStream(ctx, uuid); err != nil {
+		return fmt.Errorf("error delete stream: %w", err)
+	}
+
+	// Кадры удалены из БД — выкидываем чанки стрима из кэша, чтобы новые зрители их не получили
+	// Активные WS-сессии дочитают удерживаемый чанк и завершатся на следующем (пустом) чанке
+	if u.store != nil {
+		u.store.DropStream(uuid.Bytes)
+	}
+
+	return nil
+}
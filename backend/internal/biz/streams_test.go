@@ -3,26 +3,205 @@ package biz
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"testing"
 	"time"
 
 	v1 "stream-server/api/v1"
+	"stream-server/internal/biz/session/store_pool"
 	dbrepo "stream-server/internal/data/repo"
 	"stream-server/internal/interfaces"
 
 	conf "stream-server/config"
 
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type stubRepo struct {
-	rows []dbrepo.ListStreamsRow
-	err  error
+	rows    []dbrepo.ListStreamsRow
+	created dbrepo.Stream
+	deleted pgtype.UUID
+	batches [][]dbrepo.InsertFramesParams
+	nextSeq int32
+	err     error
+
+	updated     []dbrepo.UpdateStreamParams
+	version     int64
+	listed      []dbrepo.ListStreamsParams
+	oldestFirst bool
+
+	posters   map[pgtype.UUID][]byte
+	noPosters []dbrepo.ListStreamsWithoutThumbnailRow
+
+	frameStats   dbrepo.GetFrameStatsRow
+	gaps         []dbrepo.ListFrameGapsRow
+	gapLimit     int32
+	deletedRange []int64
+	renumbered   int
+	snapshots    int
+}
+
+func (s *stubRepo) ListStreams(_ context.Context, in dbrepo.ListStreamsParams, oldestFirst bool) ([]dbrepo.ListStreamsRow, error) {
+	s.listed = append(s.listed, in)
+	s.oldestFirst = oldestFirst
+	rows := s.rows
+	if int(in.PageLimit) < len(rows) {
+		rows = rows[:in.PageLimit]
+	}
+	return rows, s.err
+}
+
+func (s *stubRepo) GetStream(_ context.Context, _ pgtype.UUID) (dbrepo.GetStreamRow, error) {
+	return dbrepo.GetStreamRow{Version: s.version}, s.err
+}
+
+func (s *stubRepo) CreateStream(_ context.Context, in dbrepo.CreateStreamParams) (dbrepo.Stream, error) {
+	s.created.Title = in.Title
+	s.created.Description = in.Description
+	s.created.FrameIntervalMs = in.FrameIntervalMs
+	return s.created, s.err
+}
+
+func (s *stubRepo) UpdateStream(_ context.Context, in dbrepo.UpdateStreamParams) (dbrepo.UpdateStreamRow, error) {
+	s.updated = append(s.updated, in)
+	if in.ExpectedVersion.Valid && in.ExpectedVersion.Int64 != s.version {
+		return dbrepo.UpdateStreamRow{}, fmt.Errorf("update stream: %w", pgx.ErrNoRows)
+	}
+	s.version++
+	return dbrepo.UpdateStreamRow{Version: s.version}, s.err
+}
+
+func (s *stubRepo) DeleteStream(_ context.Context, id pgtype.UUID) error {
+	s.deleted = id
+	return s.err
+}
+
+func (s *stubRepo) AppendFrames(_ context.Context, _ pgtype.UUID, frames []dbrepo.InsertFramesParams) (int32, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.batches = append(s.batches, append([]dbrepo.InsertFramesParams(nil), frames...))
+	first := s.nextSeq
+	s.nextSeq += int32(len(frames))
+	return first, nil
+}
+
+// ImportStream — как транзакция: стрим и пачки остаются, только если next дочитал до io.EOF
+func (s *stubRepo) ImportStream(ctx context.Context, in dbrepo.CreateStreamParams, next func() ([]dbrepo.InsertFramesParams, error)) (dbrepo.Stream, error) {
+	if s.err != nil {
+		return dbrepo.Stream{}, s.err
+	}
+	var batches [][]dbrepo.InsertFramesParams
+	for {
+		frames, err := next()
+		if len(frames) > 0 {
+			batches = append(batches, append([]dbrepo.InsertFramesParams(nil), frames...))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return dbrepo.Stream{}, err
+		}
+	}
+	s.batches = append(s.batches, batches...)
+	return s.CreateStream(ctx, in)
+}
+
+func (s *stubRepo) GetStreamThumbnail(_ context.Context, id pgtype.UUID) (dbrepo.GetStreamThumbnailRow, error) {
+	return dbrepo.GetStreamThumbnailRow{Thumbnail: s.posters[id], FramesVersion: s.version}, s.err
+}
+
+func (s *stubRepo) SetStreamThumbnail(_ context.Context, id pgtype.UUID, thumbnail []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.posters == nil {
+		s.posters = map[pgtype.UUID][]byte{}
+	}
+	if _, ok := s.posters[id]; !ok {
+		s.posters[id] = thumbnail
+	}
+	for i, row := range s.noPosters {
+		if row.ID == id {
+			s.noPosters = append(s.noPosters[:i], s.noPosters[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *stubRepo) ListStreamsWithoutThumbnail(_ context.Context, limit int32) ([]dbrepo.ListStreamsWithoutThumbnailRow, error) {
+	if int(limit) < len(s.noPosters) {
+		return s.noPosters[:limit], s.err
+	}
+	return s.noPosters, s.err
+}
+
+// ScanFrames — кадры из ранее "загруженных" пачек в диапазоне
+func (s *stubRepo) ScanFrames(_ context.Context, _ pgtype.UUID, fromSeq, toSeq int64, fn interfaces.FrameFunc) error {
+	for _, batch := range s.batches {
+		for _, f := range batch {
+			if seq := int64(f.Sequence); seq >= fromSeq && seq <= toSeq {
+				if err := fn(seq, f.Payload, f.MimeType); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return s.err
+}
+
+// ReadSnapshot — снимок стаба: те же данные, считаем только вызовы
+func (s *stubRepo) ReadSnapshot(_ context.Context, fn func(snap interfaces.IFrameReader) error) error {
+	s.snapshots++
+	return fn(s)
+}
+
+func (s *stubRepo) GetFrameRangeStats(_ context.Context, in dbrepo.GetFrameRangeStatsParams) (dbrepo.GetFrameRangeStatsRow, error) {
+	res := dbrepo.GetFrameRangeStatsRow{LastSeq: -1}
+	_ = s.ScanFrames(context.Background(), in.StreamID, in.FromSeq, in.ToSeq, func(seq int64, payload []byte, mime string) error {
+		if mime == in.MimeType {
+			res.Frames++
+			res.Bytes += int64(len(payload))
+			res.OddFrames += int64(len(payload) % 2)
+			res.LastSeq = seq
+		}
+		return nil
+	})
+	return res, s.err
+}
+
+func (s *stubRepo) GetFrameStats(_ context.Context, _ pgtype.UUID) (dbrepo.GetFrameStatsRow, error) {
+	return s.frameStats, s.err
+}
+
+func (s *stubRepo) ListFrameGaps(_ context.Context, _ pgtype.UUID, limit int32) ([]dbrepo.ListFrameGapsRow, error) {
+	s.gapLimit = limit
+	if int(limit) < len(s.gaps) {
+		return s.gaps[:limit], s.err
+	}
+	return s.gaps, s.err
+}
+
+func (s *stubRepo) DeleteFrames(_ context.Context, _ pgtype.UUID, fromSeq, toSeq int64) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.deletedRange = []int64{fromSeq, toSeq}
+	return toSeq - fromSeq + 1, nil
 }
 
-func (s *stubRepo) ListStreams(_ context.Context) ([]dbrepo.ListStreamsRow, error) {
-	return s.rows, s.err
+func (s *stubRepo) RenumberFrames(_ context.Context, _ pgtype.UUID) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.renumbered++
+	return 3, nil
 }
 
 func TestStreamUsecase_ListStreams_Success(t *testing.T) {
@@ -40,11 +219,14 @@ func TestStreamUsecase_ListStreams_Success(t *testing.T) {
 		}},
 	}
 
-	uc := NewStreamUsecase(repo, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
-	got, err := uc.ListStreams(context.Background(), &v1.ListStreamsRequest{})
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	got, next, err := uc.ListStreams(context.Background(), &v1.ListStreamsRequest{})
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
+	if next != "" {
+		t.Fatalf("single page must not have next token, got %q", next)
+	}
 	if got == nil || len(got) != 1 {
 		t.Fatalf("unexpected result: %#v", got)
 	}
@@ -55,8 +237,8 @@ func TestStreamUsecase_ListStreams_Success(t *testing.T) {
 
 func TestStreamUsecase_ListStreams_RepoError(t *testing.T) {
 	want := errors.New("db err")
-	uc := NewStreamUsecase(&stubRepo{err: want}, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
-	got, err := uc.ListStreams(context.Background(), &v1.ListStreamsRequest{})
+	uc := NewStreamUsecase(&stubRepo{err: want}, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	got, _, err := uc.ListStreams(context.Background(), &v1.ListStreamsRequest{})
 	if err == nil {
 		t.Fatalf("expected error, got %#v", got)
 	}
@@ -65,4 +247,107 @@ func TestStreamUsecase_ListStreams_RepoError(t *testing.T) {
 	}
 }
 
+func TestStreamUsecase_CreateStream_Success(t *testing.T) {
+	uuid := pgtype.UUID{}
+	_ = uuid.Scan("0c7c8d3e-1f53-4c43-9b3a-2f0b5a6c1d11")
+	repo := &stubRepo{created: dbrepo.Stream{ID: uuid}}
+
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	got, err := uc.CreateStream(context.Background(), &v1.CreateStreamRequest{
+		Title:           "new",
+		Description:     "desc",
+		FrameIntervalMs: 40,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Id != "0c7c8d3e-1f53-4c43-9b3a-2f0b5a6c1d11" || got.Title != "new" || got.FrameIntervalMs != 40 || got.FrameCount != 0 {
+		t.Fatalf("unexpected mapping: %#v", got)
+	}
+}
+
+func TestStreamUsecase_UpdateStream_ETag(t *testing.T) {
+	repo := &stubRepo{version: 1}
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	in := &v1.UpdateStreamRequest{Id: "84a1c6a6-96ee-4d7b-94a9-0f3fbb29e7a1", Title: "a", Etag: "v1"}
+
+	got, err := uc.UpdateStream(context.Background(), in)
+	if err != nil || got.Etag != "v2" {
+		t.Fatalf("got %v err=%v, want etag v2", got, err)
+	}
+
+	// Второй оператор с устаревшим etag получает 412, а не перезаписывает
+	for _, etag := range []string{"v1", "garbage"} {
+		in.Etag = etag
+		_, err = uc.UpdateStream(context.Background(), in)
+		if !v1.IsStreamVersionMismatch(err) {
+			t.Fatalf("etag %q: expected STREAM_VERSION_MISMATCH, got %v", etag, err)
+		}
+	}
+
+	// Без etag — обновление без проверки
+	in.Etag = ""
+	if _, err = uc.UpdateStream(context.Background(), in); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// Стрима нет — это не конфликт версий
+	repo.err = pgx.ErrNoRows
+	in.Etag = "v1"
+	if _, err = uc.UpdateStream(context.Background(), in); !errors.Is(err, pgx.ErrNoRows) || v1.IsStreamVersionMismatch(err) {
+		t.Fatalf("missing stream: expected ErrNoRows, got %v", err)
+	}
+}
+
+func TestStreamUsecase_DeleteStream_BadUUID(t *testing.T) {
+	repo := &stubRepo{}
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	if err := uc.DeleteStream(context.Background(), &v1.DeleteStreamRequest{Id: "not-a-uuid"}); err == nil {
+		t.Fatal("expected error for bad uuid")
+	}
+	if repo.deleted.Valid {
+		t.Fatal("repo must not be called for bad uuid")
+	}
+}
+
+func TestStreamUsecase_DeleteStream_RepoError(t *testing.T) {
+	want := errors.New("no rows")
+	repo := &stubRepo{err: want}
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	err := uc.DeleteStream(context.Background(), &v1.DeleteStreamRequest{Id: "84a1c6a6-96ee-4d7b-94a9-0f3fbb29e7a1"})
+	if !errors.Is(err, want) {
+		t.Fatalf("expected Is(%v), got %v", want, err)
+	}
+	if !repo.deleted.Valid {
+		t.Fatal("expected repo to receive parsed uuid")
+	}
+}
+
+func TestStreamUsecase_DeleteStream_DropsCachedChunks(t *testing.T) {
+	store := store_pool.NewChunkStore(nil, []int{32 << 10}, 1<<20, 2)
+	stream := uuid.New()
+	ch := &store_pool.Chunk{StartSeq: 0, Frames: []store_pool.Frame{{Seq: 0, Data: make([]byte, 1)}}}
+	store_pool.InjectChunkForTest(store_pool.ChunkKey{Stream: stream, Index: 0}, ch, store)
+
+	// Активная сессия держит чанк
+	held, err := store.GetChunk(context.Background(), stream, 0, 0)
+	if err != nil || held != ch {
+		t.Fatalf("expected cached chunk, got %v err=%v", held, err)
+	}
+
+	uc := NewStreamUsecase(&stubRepo{}, store, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	if err = uc.DeleteStream(context.Background(), &v1.DeleteStreamRequest{Id: stream.String()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Удерживаемый чанк остаётся читаемым до release
+	if len(held.Frames) != 1 || held.Frames[0].Data == nil {
+		t.Fatal("held chunk must stay intact until release")
+	}
+	store.ReleaseChunk(held)
+	if held.Frames != nil {
+		t.Fatal("dropped chunk must be freed after release")
+	}
+}
+
 var _ interfaces.IRepo = (*stubRepo)(nil)
@@ -0,0 +1,82 @@
+package biz
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // декодеры для не-JPEG кадров
+	"image/jpeg"
+	_ "image/png"
+)
+
+const (
+	// MaxThumbnailWidth ограничение ширины превью (?w=)
+	MaxThumbnailWidth = 1024
+
+	thumbnailQuality = 80
+)
+
+var (
+	ErrBadThumbnailWidth = fmt.Errorf("thumbnail width must be within [1, %d]", MaxThumbnailWidth)
+	ErrFrameUndecodable  = errors.New("frame cannot be decoded")
+)
+
+// Thumbnail — уменьшенная до width копия кадра (пропорции сохраняются), всегда JPEG
+// resized=false — кадр и так не шире width, отдавать стоит исходные данные
+func Thumbnail(data []byte, width int) (out []byte, resized bool, err error) {
+	if width < 1 || width > MaxThumbnailWidth {
+		return nil, false, ErrBadThumbnailWidth
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrFrameUndecodable, err)
+	}
+	b := src.Bounds()
+	if b.Dx() <= width {
+		return nil, false, nil
+	}
+	height := max(b.Dy()*width/b.Dx(), 1)
+
+	var buf bytes.Buffer
+	if err = jpeg.Encode(&buf, downscale(src, width, height), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, false, fmt.Errorf("error encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// downscale — уменьшение усреднением по площади (box filter): без муара, который даёт nearest neighbour
+func downscale(src image.Image, dw, dh int) *image.RGBA {
+	// RGBA-копия: draw.Draw быстро конвертирует YCbCr, дальше работаем с Pix напрямую
+	b := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+	sw, sh := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		y0, y1 := dy*sh/dh, max((dy+1)*sh/dh, dy*sh/dh+1)
+		for dx := 0; dx < dw; dx++ {
+			x0, x1 := dx*sw/dw, max((dx+1)*sw/dw, dx*sw/dw+1)
+			var r, g, bl, a, n uint32
+			for y := y0; y < y1; y++ {
+				row := rgba.Pix[y*rgba.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+			o := dst.Pix[dy*dst.Stride+dx*4:]
+			o[0], o[1], o[2], o[3] = uint8(r/n), uint8(g/n), uint8(bl/n), uint8(a/n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,67 @@
+package biz
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestThumbnailDownscalesKeepingAspect(t *testing.T) {
+	out, resized, err := Thumbnail(testJPEG(t, 64, 32), 16)
+	if err != nil || !resized {
+		t.Fatalf("resized=%v err=%v", resized, err)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(out))
+	if err != nil || format != "jpeg" || cfg.Width != 16 || cfg.Height != 8 {
+		t.Fatalf("got %s %dx%d err=%v, want jpeg 16x8", format, cfg.Width, cfg.Height, err)
+	}
+}
+
+func TestThumbnailKeepsSmallFrames(t *testing.T) {
+	out, resized, err := Thumbnail(testJPEG(t, 32, 16), 64)
+	if err != nil || resized || out != nil {
+		t.Fatalf("frame narrower than width must be served as is: resized=%v err=%v", resized, err)
+	}
+}
+
+func TestThumbnailRejects(t *testing.T) {
+	if _, _, err := Thumbnail(testJPEG(t, 8, 8), 0); !errors.Is(err, ErrBadThumbnailWidth) {
+		t.Fatalf("width 0: %v", err)
+	}
+	if _, _, err := Thumbnail(testJPEG(t, 8, 8), MaxThumbnailWidth+1); !errors.Is(err, ErrBadThumbnailWidth) {
+		t.Fatalf("width too big: %v", err)
+	}
+	if _, _, err := Thumbnail([]byte("not an image"), 16); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
+
+func TestDownscaleAveragesArea(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.Set(0, 0, color.RGBA{R: 200, A: 255})
+	src.Set(1, 0, color.RGBA{R: 100, A: 255})
+	src.Set(0, 1, color.RGBA{G: 40, A: 255})
+	src.Set(1, 1, color.RGBA{G: 80, A: 255})
+	got := downscale(src, 1, 1).RGBAAt(0, 0)
+	if got != (color.RGBA{R: 75, G: 30, A: 255}) {
+		t.Fatalf("got %+v", got)
+	}
+}
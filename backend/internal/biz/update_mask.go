@@ -0,0 +1,74 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "stream-server/api/v1"
+)
+
+var (
+	ErrBadUpdateMask = errors.New("bad update_mask")
+	ErrEmptyUpdate   = errors.New("nothing to update")
+	ErrEmptyTitle    = errors.New("title must not be empty")
+	ErrBadInterval   = errors.New("frame_interval_ms must be positive")
+)
+
+// updatableFields поля стрима, которые меняет UpdateStream (пути update_mask)
+var updatableFields = []string{"title", "description", "frame_interval_ms"}
+
+// updatePaths — какие поля менять
+// Маска "*" — все поля; без маски — только непустые поля запроса (как раньше, но без затирания нулями)
+func updatePaths(in *v1.UpdateStreamRequest) ([]string, error) {
+	var paths []string
+	switch mask := in.GetUpdateMask().GetPaths(); {
+	case len(mask) == 1 && mask[0] == "*":
+		paths = updatableFields
+	case len(mask) > 0:
+		seen := make(map[string]bool, len(mask))
+		for _, p := range mask {
+			p = strings.TrimSpace(p)
+			if !isUpdatable(p) {
+				return nil, fmt.Errorf("%w: unknown path %q", ErrBadUpdateMask, p)
+			}
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	default:
+		if in.Title != "" {
+			paths = append(paths, "title")
+		}
+		if in.Description != "" {
+			paths = append(paths, "description")
+		}
+		if in.FrameIntervalMs != 0 {
+			paths = append(paths, "frame_interval_ms")
+		}
+	}
+	if len(paths) == 0 {
+		return nil, ErrEmptyUpdate
+	}
+
+	// значения из маски должны быть валидны (PGV пропускает пустые, т.к. поле может быть вне маски)
+	for _, p := range paths {
+		switch {
+		case p == "title" && strings.TrimSpace(in.Title) == "":
+			return nil, ErrEmptyTitle
+		case p == "frame_interval_ms" && in.FrameIntervalMs <= 0:
+			return nil, fmt.Errorf("%w, got %d", ErrBadInterval, in.FrameIntervalMs)
+		}
+	}
+	return paths, nil
+}
+
+func isUpdatable(path string) bool {
+	for _, f := range updatableFields {
+		if f == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	v1 "stream-server/api/v1"
+
+	conf "stream-server/config"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const updateID = "84a1c6a6-96ee-4d7b-94a9-0f3fbb29e7a1"
+
+func TestStreamUsecase_UpdateStream_Mask(t *testing.T) {
+	repo := &stubRepo{}
+	uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+
+	// Только title: интервал и описание не трогаем, даже если пришли нули
+	_, err := uc.UpdateStream(context.Background(), &v1.UpdateStreamRequest{
+		Id:         updateID,
+		Title:      "renamed",
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	p := repo.updated[0]
+	if p.Title != (pgtype.Text{String: "renamed", Valid: true}) || p.Description.Valid || p.FrameIntervalMs.Valid {
+		t.Fatalf("only title must be set: %#v", p)
+	}
+
+	// Маска позволяет очистить описание
+	_, err = uc.UpdateStream(context.Background(), &v1.UpdateStreamRequest{
+		Id:         updateID,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if p = repo.updated[1]; p.Description != (pgtype.Text{Valid: true}) || p.Title.Valid {
+		t.Fatalf("description must be cleared: %#v", p)
+	}
+
+	// Без маски меняются только непустые поля
+	_, err = uc.UpdateStream(context.Background(), &v1.UpdateStreamRequest{Id: updateID, FrameIntervalMs: 33})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if p = repo.updated[2]; p.FrameIntervalMs != (pgtype.Int4{Int32: 33, Valid: true}) || p.Title.Valid || p.Description.Valid {
+		t.Fatalf("implied mask must contain only frame_interval_ms: %#v", p)
+	}
+}
+
+func TestStreamUsecase_UpdateStream_BadMask(t *testing.T) {
+	cases := []struct {
+		name string
+		in   *v1.UpdateStreamRequest
+		want error
+	}{
+		{"unknown path", &v1.UpdateStreamRequest{Id: updateID, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"frame_count"}}}, ErrBadUpdateMask},
+		{"nothing set", &v1.UpdateStreamRequest{Id: updateID}, ErrEmptyUpdate},
+		{"empty masked title", &v1.UpdateStreamRequest{Id: updateID, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}}}, ErrEmptyTitle},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &stubRepo{}
+			uc := NewStreamUsecase(repo, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+			if _, err := uc.UpdateStream(context.Background(), tc.in); !errors.Is(err, tc.want) {
+				t.Fatalf("expected Is(%v), got %v", tc.want, err)
+			}
+			if len(repo.updated) != 0 {
+				t.Fatal("invalid update must not reach the repo")
+			}
+		})
+	}
+
+	// "*" с нулевым интервалом — тоже ошибка
+	uc := NewStreamUsecase(&stubRepo{}, nil, log.NewHelper(log.NewStdLogger(nil)), &conf.Config{})
+	_, err := uc.UpdateStream(context.Background(), &v1.UpdateStreamRequest{Id: updateID, Title: "t", UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}}})
+	if err == nil {
+		t.Fatal("expected error for masked zero frame_interval_ms")
+	}
+}
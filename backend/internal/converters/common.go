@@ -1,13 +1,19 @@
 package converters
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// ErrBadUUID id не UUID
+var ErrBadUUID = errors.New("bad uuid")
+
 func StringToPgUUID(idStr string) (pgtype.UUID, error) {
 	var pgUUID pgtype.UUID
 	if err := pgUUID.Scan(idStr); err != nil {
-		return pgtype.UUID{}, err
+		return pgtype.UUID{}, fmt.Errorf("%w %q", ErrBadUUID, idStr)
 	}
 
 	return pgUUID, nil
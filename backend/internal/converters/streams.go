@@ -2,9 +2,13 @@ package converters
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
 	v1 "stream-server/api/v1"
 	"stream-server/internal/data/repo"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -18,6 +22,8 @@ func ToApiStreamResponseList(in []repo.ListStreamsRow) (res []*v1.Stream) {
 			CreatedAt:       timestamppb.New(row.CreatedAt.Time),
 			UpdatedAt:       timestamppb.New(row.UpdatedAt.Time),
 			FrameCount:      row.FrameCount,
+			ThumbnailUrl:    ThumbnailURL(row.ID, row.HasThumbnail),
+			Etag:            ETag(row.Version),
 		}
 		res = append(res, item)
 	}
@@ -34,6 +40,8 @@ func ToApiStreamUpdateResult(in repo.UpdateStreamRow) *v1.Stream {
 		CreatedAt:       timestamppb.New(in.CreatedAt.Time),
 		UpdatedAt:       timestamppb.New(in.UpdatedAt.Time),
 		FrameCount:      in.FrameCount,
+		ThumbnailUrl:    ThumbnailURL(in.ID, in.HasThumbnail),
+		Etag:            ETag(in.Version),
 	}
 }
 
@@ -46,19 +54,76 @@ func ToApiStreamResponse(row repo.GetStreamRow) *v1.Stream {
 		CreatedAt:       timestamppb.New(row.CreatedAt.Time),
 		UpdatedAt:       timestamppb.New(row.UpdatedAt.Time),
 		FrameCount:      row.FrameCount,
+		ThumbnailUrl:    ThumbnailURL(row.ID, row.HasThumbnail),
+		Etag:            ETag(row.Version),
 	}
 }
 
-func ToDbUpdateStreamParams(in *v1.UpdateStreamRequest) (res repo.UpdateStreamParams, err error) {
+// ToDbUpdateStreamParams параметры обновления: заданы только поля из paths, остальные (NULL) SQL не меняет
+func ToDbUpdateStreamParams(in *v1.UpdateStreamRequest, paths []string) (res repo.UpdateStreamParams, err error) {
 	uuid, err := StringToPgUUID(in.Id)
 	if err != nil {
 		return res, fmt.Errorf("error converting uuid: %w", err)
 	}
 
-	return repo.UpdateStreamParams{
-		ID:              uuid,
+	res.ID = uuid
+	for _, path := range paths {
+		switch path {
+		case "title":
+			res.Title = pgtype.Text{String: in.Title, Valid: true}
+		case "description":
+			res.Description = pgtype.Text{String: in.Description, Valid: true}
+		case "frame_interval_ms":
+			res.FrameIntervalMs = pgtype.Int4{Int32: in.FrameIntervalMs, Valid: true}
+		default:
+			return res, fmt.Errorf("unknown update path %q", path)
+		}
+	}
+	return res, nil
+}
+
+func ToApiStreamCreateResult(in repo.Stream) *v1.Stream {
+	return &v1.Stream{
+		Id:              in.ID.String(),
+		Title:           in.Title,
+		Description:     in.Description,
+		FrameIntervalMs: in.FrameIntervalMs,
+		CreatedAt:       timestamppb.New(in.CreatedAt.Time),
+		UpdatedAt:       timestamppb.New(in.UpdatedAt.Time),
+		FrameCount:      in.FrameCount,
+		Etag:            ETag(in.Version),
+	}
+}
+
+func ToDbCreateStreamParams(in *v1.CreateStreamRequest) repo.CreateStreamParams {
+	return repo.CreateStreamParams{
 		Title:           in.Title,
 		Description:     in.Description,
 		FrameIntervalMs: in.FrameIntervalMs,
-	}, nil
+	}
+}
+
+// ThumbnailURL ссылка на постер стрима (пусто, если постера нет)
+func ThumbnailURL(id pgtype.UUID, has bool) string {
+	if !has {
+		return ""
+	}
+	return "/v1/streams/" + id.String() + "/thumbnail"
+}
+
+// ETag версия стрима в API ("v<version>"); клиент считает её непрозрачной
+func ETag(version int64) string {
+	return "v" + strconv.FormatInt(version, 10)
+}
+
+// ParseETag версия из etag; false — etag не наш (заведомо не совпадёт)
+func ParseETag(etag string) (int64, bool) {
+	if !strings.HasPrefix(etag, "v") {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(etag[1:], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
 }
@@ -56,3 +56,16 @@ func TestToApiStreamResponseList_ConvertsFields(t *testing.T) {
 	// sanity check type
 	var _ *v1.Stream = item
 }
+
+func TestToApiStreamResponse_ThumbnailURL(t *testing.T) {
+	uuid := pgtype.UUID{}
+	_ = uuid.Scan("2b6f9f5e-7a7c-4d9b-8f0f-4ef8b1c4ee11")
+
+	if got := ToApiStreamResponse(dbrepo.GetStreamRow{ID: uuid}); got.ThumbnailUrl != "" {
+		t.Errorf("stream without poster must have empty thumbnail_url, got %q", got.ThumbnailUrl)
+	}
+	got := ToApiStreamResponse(dbrepo.GetStreamRow{ID: uuid, HasThumbnail: true})
+	if want := "/v1/streams/2b6f9f5e-7a7c-4d9b-8f0f-4ef8b1c4ee11/thumbnail"; got.ThumbnailUrl != want {
+		t.Errorf("thumbnail_url mismatch: got %q want %q", got.ThumbnailUrl, want)
+	}
+}
@@ -0,0 +1,298 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Миграции в формате goose (db/migrations) без зависимости от goose: та же таблица версий goose_db_version,
+// поэтому streams admin migrate и контейнер goose из docker-compose видят одно и то же состояние
+const (
+	gooseTable = "goose_db_version"
+	// migrateLockID ключ pg_advisory_lock: два migrate одновременно не применят одну миграцию дважды
+	migrateLockID int64 = 0x73747265616d73 // "streams"
+)
+
+// ErrBadMigration файл миграции не разобран
+var ErrBadMigration = errors.New("bad migration")
+
+// Migration — Up-часть миграции; Version — числовой префикс имени файла (001_init.sql → 1)
+type Migration struct {
+	Version    int64
+	Name       string
+	Statements []string
+	NoTx       bool // -- +goose NO TRANSACTION
+}
+
+// MigrationStatus миграция и её состояние в БД
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// LoadMigrations читает *.sql из dir, по возрастанию версии
+func LoadMigrations(dir string) ([]Migration, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Migration, 0, len(files))
+	seen := make(map[int64]string, len(files))
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m, err := parseMigration(filepath.Base(path), src)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := seen[m.Version]; ok {
+			return nil, fmt.Errorf("%w: %s and %s have the same version %d", ErrBadMigration, prev, m.Name, m.Version)
+		}
+		seen[m.Version] = m.Name
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
+
+// parseMigration — разбор по правилам goose: Up до Down, инструкции режутся по ';' в конце строки,
+// блок StatementBegin/StatementEnd — одна инструкция (тела функций с ';' внутри)
+func parseMigration(name string, src []byte) (Migration, error) {
+	prefix, _, ok := strings.Cut(name, "_")
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if !ok || err != nil || version < 1 {
+		return Migration{}, fmt.Errorf("%w: %s: name must be <version>_<description>.sql", ErrBadMigration, name)
+	}
+	m := Migration{Version: version, Name: name}
+
+	var (
+		up, down, inBlock bool
+		stmt              strings.Builder
+	)
+	flush := func() {
+		if s := strings.TrimSpace(stmt.String()); s != "" && !onlyComments(s) {
+			m.Statements = append(m.Statements, s)
+		}
+		stmt.Reset()
+	}
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		if annotation, ok := strings.CutPrefix(trimmed, "-- +goose "); ok {
+			switch strings.ToUpper(strings.TrimSpace(annotation)) {
+			case "UP":
+				up = true
+			case "DOWN":
+				down = true
+			case "NO TRANSACTION":
+				m.NoTx = true
+			case "STATEMENTBEGIN":
+				inBlock = true
+			case "STATEMENTEND":
+				inBlock = false
+				if up && !down {
+					flush()
+				}
+			}
+			continue
+		}
+		if !up || down {
+			continue
+		}
+		stmt.WriteString(line)
+		stmt.WriteByte('\n')
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return Migration{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if !up {
+		return Migration{}, fmt.Errorf("%w: %s: no '-- +goose Up' annotation", ErrBadMigration, name)
+	}
+	if inBlock {
+		return Migration{}, fmt.Errorf("%w: %s: StatementBegin without StatementEnd", ErrBadMigration, name)
+	}
+	flush()
+	return m, nil
+}
+
+func onlyComments(s string) bool {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+// MigrationStatuses — какие из migrations уже применены
+func MigrationStatuses(ctx context.Context, pool *pgxpool.Pool, migrations []Migration) ([]MigrationStatus, error) {
+	applied, err := appliedVersions(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		at, ok := applied[m.Version]
+		res[i] = MigrationStatus{Migration: m, Applied: ok, AppliedAt: at}
+	}
+	return res, nil
+}
+
+// Migrate применяет неприменённые миграции по возрастанию версии; возвращает применённые сейчас
+// Миграция без NO TRANSACTION идёт одной транзакцией вместе с записью версии
+func Migrate(ctx context.Context, pool *pgxpool.Pool, migrations []Migration, l *log.Helper) (done []Migration, err error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	// advisory lock сессионный — держим одно соединение на всё время миграции
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrateLockID); err != nil {
+		return nil, fmt.Errorf("lock migrations: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrateLockID)
+	}()
+
+	if err = ensureVersionTable(ctx, conn.Conn()); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, conn.Conn())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		start := time.Now()
+		if err = applyMigration(ctx, conn.Conn(), m); err != nil {
+			return done, fmt.Errorf("migration %s: %w", m.Name, err)
+		}
+		l.Infof("applied migration %s in %s", m.Name, time.Since(start).Round(time.Millisecond))
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func applyMigration(ctx context.Context, conn *pgx.Conn, m Migration) error {
+	if m.NoTx {
+		for i, stmt := range m.Statements {
+			if _, err := conn.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("statement %d: %w", i+1, err)
+			}
+		}
+		return recordVersion(ctx, conn, m.Version)
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	for i, stmt := range m.Statements {
+		if _, err = tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d: %w", i+1, err)
+		}
+	}
+	if err = recordVersion(ctx, tx, m.Version); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func recordVersion(ctx context.Context, db execer, version int64) error {
+	_, err := db.Exec(ctx, "INSERT INTO "+gooseTable+" (version_id, is_applied) VALUES ($1, true)", version)
+	return err
+}
+
+// ensureVersionTable — таблица версий как у goose (с начальной записью версии 0)
+func ensureVersionTable(ctx context.Context, conn *pgx.Conn) error {
+	if exists, err := versionTableExists(ctx, conn); err != nil || exists {
+		return err
+	}
+	_, err := conn.Exec(ctx, `CREATE TABLE `+gooseTable+` (
+	id integer PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+	version_id bigint NOT NULL,
+	is_applied boolean NOT NULL,
+	tstamp timestamp NOT NULL DEFAULT now()
+)`)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", gooseTable, err)
+	}
+	return recordVersion(ctx, conn, 0)
+}
+
+// appliedVersions — версии, последняя запись которых is_applied (goose пишет и откаты); нет таблицы — ничего не применено
+func appliedVersions(ctx context.Context, db querier) (map[int64]time.Time, error) {
+	applied := make(map[int64]time.Time)
+	if exists, err := versionTableExists(ctx, db); err != nil || !exists {
+		return applied, err
+	}
+	rows, err := db.Query(ctx, "SELECT version_id, is_applied, tstamp FROM "+gooseTable+" ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var (
+			version int64
+			ok      bool
+			at      pgtype.Timestamp // у старых версий goose tstamp может быть NULL
+		)
+		if err = rows.Scan(&version, &ok, &at); err != nil {
+			return nil, err
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if ok {
+			applied[version] = at.Time
+		}
+	}
+	return applied, rows.Err()
+}
+
+func versionTableExists(ctx context.Context, db querier) (exists bool, err error) {
+	err = db.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", gooseTable).Scan(&exists)
+	return exists, err
+}
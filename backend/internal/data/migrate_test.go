@@ -0,0 +1,67 @@
+package data
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	ms, err := LoadMigrations("../../../db/migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) < 5 {
+		t.Fatalf("expected repo migrations, got %d", len(ms))
+	}
+	for i, m := range ms {
+		if m.Version != int64(i+1) || !m.NoTx || len(m.Statements) == 0 {
+			t.Fatalf("migration %d: %+v", i, m)
+		}
+	}
+	// 001: расширение, две таблицы и индекс; комментарии и Down не попадают
+	if got := len(ms[0].Statements); got != 4 || !strings.HasPrefix(ms[0].Statements[0], "CREATE EXTENSION") {
+		t.Fatalf("001 statements: %q", ms[0].Statements)
+	}
+}
+
+func TestParseMigration(t *testing.T) {
+	src := `-- пролог вне Up не выполняется
+SELECT 'ignored';
+-- +goose Up
+-- создаём таблицу
+CREATE TABLE t (
+    id int
+);
+-- +goose StatementBegin
+CREATE FUNCTION f() RETURNS int AS $$
+BEGIN
+    RETURN 1;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+INSERT INTO t VALUES (1)
+-- +goose Down
+DROP TABLE t;
+`
+	m, err := parseMigration("20250101120000_func.sql", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != 20250101120000 || m.NoTx || len(m.Statements) != 3 {
+		t.Fatalf("unexpected migration %+v", m)
+	}
+	if !strings.Contains(m.Statements[1], "RETURN 1;\nEND;") || m.Statements[2] != "INSERT INTO t VALUES (1)" {
+		t.Fatalf("statements: %q", m.Statements)
+	}
+
+	for name, src := range map[string]string{
+		"init.sql":      "-- +goose Up\nSELECT 1;",
+		"002_no_up.sql": "SELECT 1;",
+		"003_open.sql":  "-- +goose Up\n-- +goose StatementBegin\nSELECT 1;",
+	} {
+		if _, err = parseMigration(name, []byte(src)); !errors.Is(err, ErrBadMigration) {
+			t.Fatalf("%s: expected ErrBadMigration, got %v", name, err)
+		}
+	}
+}
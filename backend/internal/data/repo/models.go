@@ -24,4 +24,8 @@ type Stream struct {
 	FrameIntervalMs int32              `json:"FrameIntervalMs"`
 	CreatedAt       pgtype.Timestamptz `json:"CreatedAt"`
 	UpdatedAt       pgtype.Timestamptz `json:"UpdatedAt"`
+	Thumbnail       []byte             `json:"Thumbnail"`
+	FrameCount      int64              `json:"FrameCount"`
+	Version         int64              `json:"Version"`
+	FramesVersion   int64              `json:"FramesVersion"`
 }
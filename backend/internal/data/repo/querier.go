@@ -11,8 +11,25 @@ import (
 )
 
 type Querier interface {
+	AddStreamFrameCount(ctx context.Context, arg AddStreamFrameCountParams) error
+	CreateStream(ctx context.Context, arg CreateStreamParams) (Stream, error)
+	DeleteFrameRange(ctx context.Context, arg DeleteFrameRangeParams) (int64, error)
+	DeleteStream(ctx context.Context, id pgtype.UUID) (int64, error)
+	FinishFrameRenumber(ctx context.Context, streamID pgtype.UUID) (int64, error)
+	GetFrameRangeStats(ctx context.Context, arg GetFrameRangeStatsParams) (GetFrameRangeStatsRow, error)
+	GetFrameStats(ctx context.Context, streamID pgtype.UUID) (GetFrameStatsRow, error)
+	GetNextFrameSequence(ctx context.Context, streamID pgtype.UUID) (int32, error)
 	GetStream(ctx context.Context, id pgtype.UUID) (GetStreamRow, error)
-	ListStreams(ctx context.Context) ([]ListStreamsRow, error)
+	GetStreamThumbnail(ctx context.Context, id pgtype.UUID) (GetStreamThumbnailRow, error)
+	InsertFrames(ctx context.Context, arg []InsertFramesParams) (int64, error)
+	ListFrameGaps(ctx context.Context, arg ListFrameGapsParams) ([]ListFrameGapsRow, error)
+	ListStreams(ctx context.Context, arg ListStreamsParams) ([]ListStreamsRow, error)
+	ListStreamsOldestFirst(ctx context.Context, arg ListStreamsOldestFirstParams) ([]ListStreamsOldestFirstRow, error)
+	ListStreamsWithoutThumbnail(ctx context.Context, limit int32) ([]ListStreamsWithoutThumbnailRow, error)
+	LockStream(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
+	ResetStreamFrameStats(ctx context.Context, streamID pgtype.UUID) error
+	SetStreamThumbnail(ctx context.Context, arg SetStreamThumbnailParams) error
+	StageFrameRenumber(ctx context.Context, streamID pgtype.UUID) (int64, error)
 	UpdateStream(ctx context.Context, arg UpdateStreamParams) (UpdateStreamRow, error)
 }
 
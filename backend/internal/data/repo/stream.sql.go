@@ -11,11 +11,125 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addStreamFrameCount = `-- name: AddStreamFrameCount :exec
+UPDATE streams
+SET frame_count = frame_count + $2
+WHERE id = $1
+`
+
+type AddStreamFrameCountParams struct {
+	ID         pgtype.UUID `json:"ID"`
+	FrameCount int64       `json:"FrameCount"`
+}
+
+func (q *Queries) AddStreamFrameCount(ctx context.Context, arg AddStreamFrameCountParams) error {
+	_, err := q.db.Exec(ctx, addStreamFrameCount, arg.ID, arg.FrameCount)
+	return err
+}
+
+const createStream = `-- name: CreateStream :one
+INSERT INTO streams (id, title, description, frame_interval_ms)
+VALUES (uuid_generate_v4(), $1, $2, $3)
+RETURNING id, title, description, frame_interval_ms, created_at, updated_at, thumbnail, frame_count, version, frames_version
+`
+
+type CreateStreamParams struct {
+	Title           string `json:"Title"`
+	Description     string `json:"Description"`
+	FrameIntervalMs int32  `json:"FrameIntervalMs"`
+}
+
+func (q *Queries) CreateStream(ctx context.Context, arg CreateStreamParams) (Stream, error) {
+	row := q.db.QueryRow(ctx, createStream, arg.Title, arg.Description, arg.FrameIntervalMs)
+	var i Stream
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.FrameIntervalMs,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Thumbnail,
+		&i.FrameCount,
+		&i.Version,
+		&i.FramesVersion,
+	)
+	return i, err
+}
+
+const deleteStream = `-- name: DeleteStream :execrows
+DELETE FROM streams
+WHERE id = $1
+`
+
+func (q *Queries) DeleteStream(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStream, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getFrameRangeStats = `-- name: GetFrameRangeStats :one
+SELECT count(*)::bigint AS frames,
+       coalesce(sum(octet_length(payload)), 0)::bigint AS bytes,
+       count(*) FILTER (WHERE octet_length(payload) % 2 = 1)::bigint AS odd_frames,
+       coalesce(max(sequence), -1)::bigint AS last_seq
+FROM frames
+WHERE stream_id = $1
+  AND sequence >= $2::bigint AND sequence <= $3::bigint
+  AND mime_type = $4
+`
+
+type GetFrameRangeStatsParams struct {
+	StreamID pgtype.UUID `json:"StreamID"`
+	FromSeq  int64       `json:"FromSeq"`
+	ToSeq    int64       `json:"ToSeq"`
+	MimeType string      `json:"MimeType"`
+}
+
+type GetFrameRangeStatsRow struct {
+	Frames    int64 `json:"Frames"`
+	Bytes     int64 `json:"Bytes"`
+	OddFrames int64 `json:"OddFrames"`
+	LastSeq   int64 `json:"LastSeq"`
+}
+
+func (q *Queries) GetFrameRangeStats(ctx context.Context, arg GetFrameRangeStatsParams) (GetFrameRangeStatsRow, error) {
+	row := q.db.QueryRow(ctx, getFrameRangeStats,
+		arg.StreamID,
+		arg.FromSeq,
+		arg.ToSeq,
+		arg.MimeType,
+	)
+	var i GetFrameRangeStatsRow
+	err := row.Scan(
+		&i.Frames,
+		&i.Bytes,
+		&i.OddFrames,
+		&i.LastSeq,
+	)
+	return i, err
+}
+
+const getNextFrameSequence = `-- name: GetNextFrameSequence :one
+SELECT COALESCE(MAX(sequence) + 1, 0)::integer AS next_seq
+FROM frames
+WHERE stream_id = $1
+`
+
+func (q *Queries) GetNextFrameSequence(ctx context.Context, streamID pgtype.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, getNextFrameSequence, streamID)
+	var next_seq int32
+	err := row.Scan(&next_seq)
+	return next_seq, err
+}
+
 const getStream = `-- name: GetStream :one
-select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, count(f.id) as frame_count
-from streams s left join frames f on f.stream_id = s.id
-group by s.id
-having s.id = $1
+select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, s.frame_count,
+       coalesce(length(s.thumbnail), 0) > 0 as has_thumbnail, s.version
+from streams s
+where s.id = $1
 `
 
 type GetStreamRow struct {
@@ -26,6 +140,8 @@ type GetStreamRow struct {
 	CreatedAt       pgtype.Timestamptz `json:"CreatedAt"`
 	UpdatedAt       pgtype.Timestamptz `json:"UpdatedAt"`
 	FrameCount      int64              `json:"FrameCount"`
+	HasThumbnail    bool               `json:"HasThumbnail"`
+	Version         int64              `json:"Version"`
 }
 
 func (q *Queries) GetStream(ctx context.Context, id pgtype.UUID) (GetStreamRow, error) {
@@ -39,17 +155,56 @@ func (q *Queries) GetStream(ctx context.Context, id pgtype.UUID) (GetStreamRow,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.FrameCount,
+		&i.HasThumbnail,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getStreamThumbnail = `-- name: GetStreamThumbnail :one
+SELECT thumbnail, frames_version
+FROM streams
+WHERE id = $1
+`
+
+type GetStreamThumbnailRow struct {
+	Thumbnail     []byte `json:"Thumbnail"`
+	FramesVersion int64  `json:"FramesVersion"`
+}
+
+func (q *Queries) GetStreamThumbnail(ctx context.Context, id pgtype.UUID) (GetStreamThumbnailRow, error) {
+	row := q.db.QueryRow(ctx, getStreamThumbnail, id)
+	var i GetStreamThumbnailRow
+	err := row.Scan(&i.Thumbnail, &i.FramesVersion)
+	return i, err
+}
+
+type InsertFramesParams struct {
+	ID       pgtype.UUID `json:"ID"`
+	StreamID pgtype.UUID `json:"StreamID"`
+	Sequence int32       `json:"Sequence"`
+	Payload  []byte      `json:"Payload"`
+	MimeType string      `json:"MimeType"`
+}
+
 const listStreams = `-- name: ListStreams :many
-select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, count(f.id) as frame_count
-from streams s left join frames f on f.stream_id = s.id
-group by s.id
-order by s.created_at desc
+select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, s.frame_count,
+       coalesce(length(s.thumbnail), 0) > 0 as has_thumbnail, s.version
+from streams s
+where ($1::text = '' or s.title ilike $1 or s.description ilike $1)
+  and ($2::timestamptz is null
+       or (s.created_at, s.id) < ($2::timestamptz, $3::uuid))
+order by s.created_at desc, s.id desc
+limit $4
 `
 
+type ListStreamsParams struct {
+	Pattern        string             `json:"Pattern"`
+	AfterCreatedAt pgtype.Timestamptz `json:"AfterCreatedAt"`
+	AfterID        pgtype.UUID        `json:"AfterID"`
+	PageLimit      int32              `json:"PageLimit"`
+}
+
 type ListStreamsRow struct {
 	ID              pgtype.UUID        `json:"ID"`
 	Title           string             `json:"Title"`
@@ -58,10 +213,17 @@ type ListStreamsRow struct {
 	CreatedAt       pgtype.Timestamptz `json:"CreatedAt"`
 	UpdatedAt       pgtype.Timestamptz `json:"UpdatedAt"`
 	FrameCount      int64              `json:"FrameCount"`
+	HasThumbnail    bool               `json:"HasThumbnail"`
+	Version         int64              `json:"Version"`
 }
 
-func (q *Queries) ListStreams(ctx context.Context) ([]ListStreamsRow, error) {
-	rows, err := q.db.Query(ctx, listStreams)
+func (q *Queries) ListStreams(ctx context.Context, arg ListStreamsParams) ([]ListStreamsRow, error) {
+	rows, err := q.db.Query(ctx, listStreams,
+		arg.Pattern,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +239,8 @@ func (q *Queries) ListStreams(ctx context.Context) ([]ListStreamsRow, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.FrameCount,
+			&i.HasThumbnail,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -88,14 +252,149 @@ func (q *Queries) ListStreams(ctx context.Context) ([]ListStreamsRow, error) {
 	return items, nil
 }
 
+const listStreamsOldestFirst = `-- name: ListStreamsOldestFirst :many
+select s.id, s.title, s.description, s.frame_interval_ms, s.created_at, s.updated_at, s.frame_count,
+       coalesce(length(s.thumbnail), 0) > 0 as has_thumbnail, s.version
+from streams s
+where ($1::text = '' or s.title ilike $1 or s.description ilike $1)
+  and ($2::timestamptz is null
+       or (s.created_at, s.id) > ($2::timestamptz, $3::uuid))
+order by s.created_at, s.id
+limit $4
+`
+
+type ListStreamsOldestFirstParams struct {
+	Pattern        string             `json:"Pattern"`
+	AfterCreatedAt pgtype.Timestamptz `json:"AfterCreatedAt"`
+	AfterID        pgtype.UUID        `json:"AfterID"`
+	PageLimit      int32              `json:"PageLimit"`
+}
+
+type ListStreamsOldestFirstRow struct {
+	ID              pgtype.UUID        `json:"ID"`
+	Title           string             `json:"Title"`
+	Description     string             `json:"Description"`
+	FrameIntervalMs int32              `json:"FrameIntervalMs"`
+	CreatedAt       pgtype.Timestamptz `json:"CreatedAt"`
+	UpdatedAt       pgtype.Timestamptz `json:"UpdatedAt"`
+	FrameCount      int64              `json:"FrameCount"`
+	HasThumbnail    bool               `json:"HasThumbnail"`
+	Version         int64              `json:"Version"`
+}
+
+func (q *Queries) ListStreamsOldestFirst(ctx context.Context, arg ListStreamsOldestFirstParams) ([]ListStreamsOldestFirstRow, error) {
+	rows, err := q.db.Query(ctx, listStreamsOldestFirst,
+		arg.Pattern,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStreamsOldestFirstRow
+	for rows.Next() {
+		var i ListStreamsOldestFirstRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.FrameIntervalMs,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FrameCount,
+			&i.HasThumbnail,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStreamsWithoutThumbnail = `-- name: ListStreamsWithoutThumbnail :many
+SELECT s.id, f.payload
+FROM streams s
+JOIN LATERAL (
+    SELECT payload
+    FROM frames
+    WHERE stream_id = s.id
+    ORDER BY sequence
+    LIMIT 1
+) f ON true
+WHERE s.thumbnail IS NULL
+LIMIT $1
+`
+
+type ListStreamsWithoutThumbnailRow struct {
+	ID      pgtype.UUID `json:"ID"`
+	Payload []byte      `json:"Payload"`
+}
+
+func (q *Queries) ListStreamsWithoutThumbnail(ctx context.Context, limit int32) ([]ListStreamsWithoutThumbnailRow, error) {
+	rows, err := q.db.Query(ctx, listStreamsWithoutThumbnail, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStreamsWithoutThumbnailRow
+	for rows.Next() {
+		var i ListStreamsWithoutThumbnailRow
+		if err := rows.Scan(&i.ID, &i.Payload); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockStream = `-- name: LockStream :one
+SELECT id
+FROM streams
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockStream(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, lockStream, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const setStreamThumbnail = `-- name: SetStreamThumbnail :exec
+UPDATE streams
+SET thumbnail = $2
+WHERE id = $1 AND thumbnail IS NULL
+`
+
+type SetStreamThumbnailParams struct {
+	ID        pgtype.UUID `json:"ID"`
+	Thumbnail []byte      `json:"Thumbnail"`
+}
+
+func (q *Queries) SetStreamThumbnail(ctx context.Context, arg SetStreamThumbnailParams) error {
+	_, err := q.db.Exec(ctx, setStreamThumbnail, arg.ID, arg.Thumbnail)
+	return err
+}
+
 const updateStream = `-- name: UpdateStream :one
 UPDATE streams s
 SET
     updated_at = now(),
-    title = $2,
-    description = $3,
-    frame_interval_ms = $4
-WHERE s.id = $1
+    version = s.version + 1,
+    title = coalesce($1::text, s.title),
+    description = coalesce($2::text, s.description),
+    frame_interval_ms = coalesce($3::integer, s.frame_interval_ms)
+WHERE s.id = $4
+  AND ($5::bigint IS NULL OR s.version = $5::bigint)
     RETURNING
     s.id,
     s.title,
@@ -103,18 +402,17 @@ WHERE s.id = $1
     s.frame_interval_ms,
     s.created_at,
     s.updated_at,
-    (
-        SELECT count(f.id)
-        FROM frames f
-        WHERE f.stream_id = s.id
-    ) AS frame_count
+    s.frame_count,
+    coalesce(length(s.thumbnail), 0) > 0 AS has_thumbnail,
+    s.version
 `
 
 type UpdateStreamParams struct {
+	Title           pgtype.Text `json:"Title"`
+	Description     pgtype.Text `json:"Description"`
+	FrameIntervalMs pgtype.Int4 `json:"FrameIntervalMs"`
 	ID              pgtype.UUID `json:"ID"`
-	Title           string      `json:"Title"`
-	Description     string      `json:"Description"`
-	FrameIntervalMs int32       `json:"FrameIntervalMs"`
+	ExpectedVersion pgtype.Int8 `json:"ExpectedVersion"`
 }
 
 type UpdateStreamRow struct {
@@ -125,14 +423,17 @@ type UpdateStreamRow struct {
 	CreatedAt       pgtype.Timestamptz `json:"CreatedAt"`
 	UpdatedAt       pgtype.Timestamptz `json:"UpdatedAt"`
 	FrameCount      int64              `json:"FrameCount"`
+	HasThumbnail    bool               `json:"HasThumbnail"`
+	Version         int64              `json:"Version"`
 }
 
 func (q *Queries) UpdateStream(ctx context.Context, arg UpdateStreamParams) (UpdateStreamRow, error) {
 	row := q.db.QueryRow(ctx, updateStream,
-		arg.ID,
 		arg.Title,
 		arg.Description,
 		arg.FrameIntervalMs,
+		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i UpdateStreamRow
 	err := row.Scan(
@@ -143,6 +444,8 @@ func (q *Queries) UpdateStream(ctx context.Context, arg UpdateStreamParams) (Upd
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.FrameCount,
+		&i.HasThumbnail,
+		&i.Version,
 	)
 	return i, err
 }
@@ -1,20 +1,39 @@
 package dep
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"stream-server/config"
 
 	"github.com/go-kratos/kratos/v2/middleware/metrics"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// traceShutdownTimeout — сколько ждём досылки накопленных спанов при остановке
+const traceShutdownTimeout = 5 * time.Second
+
+// newResource — кто пишет метрики и трейсы
+func newResource(meta conf.Metadata) *resource.Resource {
+	return resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(meta.Name),
+		attribute.String("environment", meta.Env),
+	)
+}
+
 func NewMeter(cfg *conf.Metadata, provider metric.MeterProvider) (metric.Meter, error) {
 	name := fmt.Sprintf("%s-%s", cfg.Namespace, cfg.Name)
 
@@ -37,13 +56,7 @@ func NewMeterProvider(cfg *conf.Config) (metric.MeterProvider, error) {
 	}
 
 	provider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithResource(
-			resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(meta.Name),
-				attribute.String("environment", meta.Env),
-			),
-		),
+		sdkmetric.WithResource(newResource(meta)),
 		sdkmetric.WithReader(exporter),
 		sdkmetric.WithView(
 			metrics.DefaultSecondsHistogramView(metrics.DefaultServerSecondsHistogramName),
@@ -52,3 +65,39 @@ func NewMeterProvider(cfg *conf.Config) (metric.MeterProvider, error) {
 	otel.SetMeterProvider(provider)
 	return provider, nil
 }
+
+// NewTracerProvider — экспорт спанов в OTLP-коллектор (Tracing.Endpoint) пачками в фоне
+// Без endpoint провайдер noop: спаны в коде ничего не стоят. Пропагатор W3C (traceparent) ставится всегда,
+// чтобы трейс клиента продолжался и без своего экспорта. Функция очистки досылает накопленное
+func NewTracerProvider(cfg *conf.Config) (trace.TracerProvider, func(), error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	traceConf := cfg.Tracing
+	if traceConf.Endpoint == "" {
+		provider := noop.NewTracerProvider()
+		otel.SetTracerProvider(provider)
+		return provider, func() {}, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(traceConf.Endpoint)}
+	if traceConf.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	// New не ходит в сеть: недоступный коллектор не мешает старту, спаны просто теряются
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(newResource(cfg.Metadata)),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(traceConf.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), traceShutdownTimeout)
+		defer cancel()
+		_ = provider.Shutdown(ctx)
+	}, nil
+}
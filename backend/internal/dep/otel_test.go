@@ -0,0 +1,106 @@
+package dep
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"stream-server/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collectorStub — OTLP/HTTP-коллектор в памяти: принимает POST /v1/traces и запоминает имена спанов
+type collectorStub struct {
+	mu       sync.Mutex
+	spans    []string
+	services []string
+}
+
+func (c *collectorStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req collectortrace.ExportTraceServiceRequest
+	if r.URL.Path != "/v1/traces" || proto.Unmarshal(body, &req) != nil {
+		http.Error(w, "bad export", http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, kv := range rs.GetResource().GetAttributes() {
+			if kv.Key == "service.name" {
+				c.services = append(c.services, kv.GetValue().GetStringValue())
+			}
+		}
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				c.spans = append(c.spans, span.Name)
+			}
+		}
+	}
+	c.mu.Unlock()
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	out, _ := proto.Marshal(&collectortrace.ExportTraceServiceResponse{})
+	_, _ = w.Write(out)
+}
+
+func tracingConfig(endpoint string) *conf.Config {
+	cfg := &conf.Config{}
+	cfg.Metadata = conf.Metadata{Name: "stream.service", Env: "test"}
+	cfg.Tracing = conf.Tracing{Endpoint: endpoint, Insecure: true, SampleRatio: 1}
+	return cfg
+}
+
+func TestTracerProviderExportsToCollector(t *testing.T) {
+	collector := &collectorStub{}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	provider, cleanup, err := NewTracerProvider(tracingConfig(srv.Listener.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "StreamService.StreamWS")
+	_, child := provider.Tracer("test").Start(ctx, "ChunkStore.loadChunk")
+	child.End()
+	parent.End()
+	cleanup() // остановка досылает пачку
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.spans) != 2 || collector.spans[0] != "ChunkStore.loadChunk" || collector.spans[1] != "StreamService.StreamWS" {
+		t.Fatalf("unexpected exported spans %v", collector.spans)
+	}
+	if len(collector.services) == 0 || collector.services[0] != "stream.service" {
+		t.Fatalf("spans must carry the service resource, got %v", collector.services)
+	}
+}
+
+func TestTracerProviderWithoutEndpoint(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	provider, cleanup, err := NewTracerProvider(tracingConfig(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if _, span := provider.Tracer("test").Start(context.Background(), "noop"); span.IsRecording() {
+		t.Fatalf("without endpoint spans must not be recorded")
+	}
+
+	// traceparent клиента всё равно продолжается
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier{"Traceparent": {traceparent}})
+	if got := trace.SpanContextFromContext(ctx).TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("traceparent must be extracted, got %q", got)
+	}
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import "context"
+
+// IAdminUsecase обслуживание кадров стримов (streams admin)
+type IAdminUsecase interface {
+	FrameStats(ctx context.Context, streamID string, maxGaps int32) (*FrameStats, error)
+	DeleteFrames(ctx context.Context, streamID string, fromSeq, toSeq int64) (deleted int64, err error)
+	RenumberFrames(ctx context.Context, streamID string) (moved int64, err error)
+}
+
+// FrameStats сводка по кадрам стрима; MinSeq/MaxSeq = -1 — кадров нет
+type FrameStats struct {
+	Frames        int64
+	MinSeq        int64
+	MaxSeq        int64
+	Bytes         int64
+	Gaps          int64 // пропусков в sequence (после MinSeq)
+	MissingFrames int64 // отсутствующих номеров во всех пропусках
+	FirstGaps     []FrameGap
+}
+
+// FrameGap пропуск в sequence: номера [FromSeq, ToSeq] отсутствуют
+type FrameGap struct {
+	FromSeq int64
+	ToSeq   int64
+}
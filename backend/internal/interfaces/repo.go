@@ -8,7 +8,28 @@ import (
 )
 
 type IRepo interface {
-	ListStreams(ctx context.Context) ([]repo.ListStreamsRow, error)
+	ListStreams(ctx context.Context, in repo.ListStreamsParams, oldestFirst bool) ([]repo.ListStreamsRow, error)
 	GetStream(ctx context.Context, ID pgtype.UUID) (repo.GetStreamRow, error)
+	CreateStream(ctx context.Context, in repo.CreateStreamParams) (res repo.Stream, err error)
 	UpdateStream(ctx context.Context, in repo.UpdateStreamParams) (res repo.UpdateStreamRow, err error)
+	DeleteStream(ctx context.Context, ID pgtype.UUID) error
+	AppendFrames(ctx context.Context, streamID pgtype.UUID, frames []repo.InsertFramesParams) (firstSeq int32, err error)
+	ImportStream(ctx context.Context, in repo.CreateStreamParams, next func() ([]repo.InsertFramesParams, error)) (res repo.Stream, err error)
+	GetStreamThumbnail(ctx context.Context, ID pgtype.UUID) (repo.GetStreamThumbnailRow, error)
+	SetStreamThumbnail(ctx context.Context, ID pgtype.UUID, thumbnail []byte) error
+	ListStreamsWithoutThumbnail(ctx context.Context, limit int32) ([]repo.ListStreamsWithoutThumbnailRow, error)
+	GetFrameRangeStats(ctx context.Context, in repo.GetFrameRangeStatsParams) (repo.GetFrameRangeStatsRow, error)
+	ScanFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64, fn FrameFunc) error
+	GetFrameStats(ctx context.Context, streamID pgtype.UUID) (repo.GetFrameStatsRow, error)
+	ListFrameGaps(ctx context.Context, streamID pgtype.UUID, limit int32) ([]repo.ListFrameGapsRow, error)
+	DeleteFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64) (deleted int64, err error)
+	RenumberFrames(ctx context.Context, streamID pgtype.UUID) (moved int64, err error)
+	ReadSnapshot(ctx context.Context, fn func(snap IFrameReader) error) error
+}
+
+// IFrameReader чтения стрима и кадров внутри одного снимка БД (IRepo.ReadSnapshot)
+type IFrameReader interface {
+	GetStream(ctx context.Context, ID pgtype.UUID) (repo.GetStreamRow, error)
+	GetFrameRangeStats(ctx context.Context, in repo.GetFrameRangeStatsParams) (repo.GetFrameRangeStatsRow, error)
+	ScanFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64, fn FrameFunc) error
 }
@@ -16,8 +16,21 @@ type IHealthService interface {
 type IStreamService interface {
 	ListStreams(context.Context, *v1.ListStreamsRequest) (*v1.ListStreamsResponse, error)
 	GetStream(context.Context, *v1.GetStreamRequest) (*v1.GetStreamResponse, error)
+	CreateStream(context.Context, *v1.CreateStreamRequest) (*v1.CreateStreamResponse, error)
 	UpdateStream(context.Context, *v1.UpdateStreamRequest) (*v1.UpdateStreamResponse, error)
+	DeleteStream(context.Context, *v1.DeleteStreamRequest) (*v1.DeleteStreamResponse, error)
+	IngestFrames(v1.StreamService_IngestFramesServer) error
+	StreamFrames(*v1.StreamFramesRequest, v1.StreamService_StreamFramesServer) error
 
 	// Websocket handlers
 	StreamWSHandler() http.HandlerFunc
+	MJPEGHandler() http.HandlerFunc
+
+	// Raw HTTP handlers
+	IngestHTTPHandler() http.HandlerFunc
+	ImportHTTPHandler() http.HandlerFunc
+	FrameHTTPHandler() http.HandlerFunc
+	SnapshotHTTPHandler() http.HandlerFunc
+	ThumbnailHTTPHandler() http.HandlerFunc
+	ExportHTTPHandler() http.HandlerFunc
 }
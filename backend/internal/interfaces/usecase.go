@@ -6,7 +6,32 @@ import (
 )
 
 type IUsecase interface {
-	ListStreams(context.Context, *v1.ListStreamsRequest) ([]*v1.Stream, error)
+	ListStreams(ctx context.Context, in *v1.ListStreamsRequest) (res []*v1.Stream, nextPageToken string, err error)
 	GetStream(ctx context.Context, in *v1.GetStreamRequest) (res *v1.Stream, err error)
+	CreateStream(ctx context.Context, in *v1.CreateStreamRequest) (res *v1.Stream, err error)
 	UpdateStream(ctx context.Context, in *v1.UpdateStreamRequest) (res *v1.Stream, err error)
+	DeleteStream(ctx context.Context, in *v1.DeleteStreamRequest) error
+	IngestFrames(ctx context.Context, streamID string, src FrameSource) (res *v1.IngestFramesResponse, err error)
+	ImportStream(ctx context.Context, in *v1.CreateStreamRequest, src FrameSource) (res *v1.Stream, err error)
+	GetStreamThumbnail(ctx context.Context, streamID string) (poster []byte, version int64, err error)
+	ExportFrames(ctx context.Context, streamID string, fromSeq, toSeq int64, fn FrameFunc) error
+	ExportFrameRange(ctx context.Context, streamID string, fromSeq, toSeq int64, mime string, prepare func(fr *FrameRange) error, fn FrameFunc) error
+}
+
+// FrameRange сводка по кадрам диапазона с одним mime — чтобы заранее посчитать размеры контейнера (AVI)
+type FrameRange struct {
+	IntervalMS int32 // frame_interval_ms стрима
+	Frames     int64
+	Bytes      int64 // суммарный размер payload
+	OddFrames  int64 // кадров нечётной длины
+	LastSeq    int64 // последний кадр диапазона (-1 — кадров нет)
+}
+
+// FrameFunc получатель кадров при чтении диапазона; payload действителен только до возврата (буфер драйвера БД)
+// Ошибка прерывает чтение и возвращается вызывающему
+type FrameFunc func(seq int64, payload []byte, mime string) error
+
+// FrameSource источник кадров для загрузки в стрим; Next возвращает io.EOF, когда кадры закончились
+type FrameSource interface {
+	Next() (payload []byte, mime string, err error)
 }
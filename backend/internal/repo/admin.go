@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"stream-server/internal/data/repo"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetFrameStats сводка по всем кадрам стрима: границы sequence, объём и дыры
+func (r *StreamRepo) GetFrameStats(ctx context.Context, streamID pgtype.UUID) (repo.GetFrameStatsRow, error) {
+	return r.queries.GetFrameStats(ctx, streamID)
+}
+
+// ListFrameGaps первые limit пропусков в sequence (диапазоны отсутствующих номеров)
+func (r *StreamRepo) ListFrameGaps(ctx context.Context, streamID pgtype.UUID, limit int32) ([]repo.ListFrameGapsRow, error) {
+	return r.queries.ListFrameGaps(ctx, repo.ListFrameGapsParams{StreamID: streamID, Limit: limit})
+}
+
+// DeleteFrames удаляет кадры [fromSeq, toSeq] и пересчитывает счётчик кадров стрима
+func (r *StreamRepo) DeleteFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64) (deleted int64, err error) {
+	err = r.inStreamTx(ctx, streamID, func(qtx *repo.Queries) error {
+		deleted, err = qtx.DeleteFrameRange(ctx, repo.DeleteFrameRangeParams{StreamID: streamID, FromSeq: fromSeq, ToSeq: toSeq})
+		return err
+	})
+	return deleted, err
+}
+
+// RenumberFrames перенумеровывает кадры стрима подряд с 0, сохраняя порядок; возвращает число сдвинутых кадров
+func (r *StreamRepo) RenumberFrames(ctx context.Context, streamID pgtype.UUID) (moved int64, err error) {
+	err = r.inStreamTx(ctx, streamID, func(qtx *repo.Queries) error {
+		if moved, err = qtx.StageFrameRenumber(ctx, streamID); err != nil {
+			return fmt.Errorf("stage renumber: %w", err)
+		}
+		if _, err = qtx.FinishFrameRenumber(ctx, streamID); err != nil {
+			return fmt.Errorf("finish renumber: %w", err)
+		}
+		return nil
+	})
+	return moved, err
+}
+
+// inStreamTx — правка кадров под блокировкой стрима (как AppendFrames), после неё — пересчёт frame_count
+// Постер сбрасывается: первый кадр мог измениться
+func (r *StreamRepo) inStreamTx(ctx context.Context, streamID pgtype.UUID, fn func(qtx *repo.Queries) error) error {
+	tx, err := r.data.DBClientPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	qtx := r.queries.WithTx(tx)
+
+	if _, err = qtx.LockStream(ctx, streamID); err != nil {
+		return fmt.Errorf("lock stream: %w", err)
+	}
+	if err = fn(qtx); err != nil {
+		return err
+	}
+	if err = qtx.ResetStreamFrameStats(ctx, streamID); err != nil {
+		return fmt.Errorf("reset frame stats: %w", err)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"stream-server/internal/data/repo"
+	"stream-server/internal/interfaces"
+)
+
+// scanFramesQuery — не через sqlc: :many собрал бы весь диапазон в память, а нам нужен построчный проход
+const scanFramesQuery = `
+SELECT sequence, payload, mime_type
+FROM frames
+WHERE stream_id = $1 AND sequence >= $2 AND sequence <= $3
+ORDER BY sequence
+`
+
+// ScanFrames отдаёт кадры диапазона [fromSeq, toSeq] по одному, прямо из курсора pgx
+// Строки читаются из соединения по мере обработки, payload не копируется (DriverBytes — буфер pgx),
+// поэтому память не растёт с длиной диапазона. Кэш чанков не используется
+func (r *StreamRepo) ScanFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64, fn interfaces.FrameFunc) error {
+	return scanFrames(ctx, r.data.DBClientPool, streamID, fromSeq, toSeq, fn)
+}
+
+// ReadSnapshot выполняет fn в одной read-only транзакции REPEATABLE READ:
+// все чтения fn видят один снимок, даже если кадры параллельно дописывают или правят
+func (r *StreamRepo) ReadSnapshot(ctx context.Context, fn func(snap interfaces.IFrameReader) error) error {
+	tx, err := r.data.DBClientPool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = fn(&frameSnapshot{tx: tx, queries: r.queries.WithTx(tx)}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// frameSnapshot — чтения внутри транзакции ReadSnapshot
+type frameSnapshot struct {
+	tx      pgx.Tx
+	queries *repo.Queries
+}
+
+func (s *frameSnapshot) GetStream(ctx context.Context, ID pgtype.UUID) (repo.GetStreamRow, error) {
+	return s.queries.GetStream(ctx, ID)
+}
+
+func (s *frameSnapshot) GetFrameRangeStats(ctx context.Context, in repo.GetFrameRangeStatsParams) (repo.GetFrameRangeStatsRow, error) {
+	return s.queries.GetFrameRangeStats(ctx, in)
+}
+
+func (s *frameSnapshot) ScanFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64, fn interfaces.FrameFunc) error {
+	return scanFrames(ctx, s.tx, streamID, fromSeq, toSeq, fn)
+}
+
+// rowsQuerier — пул или транзакция
+type rowsQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func scanFrames(ctx context.Context, db rowsQuerier, streamID pgtype.UUID, fromSeq, toSeq int64, fn interfaces.FrameFunc) error {
+	rows, err := db.Query(ctx, scanFramesQuery, streamID, fromSeq, toSeq)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		seq     int64
+		payload pgtype.DriverBytes
+		mime    string
+	)
+	for rows.Next() {
+		if err = rows.Scan(&seq, &payload, &mime); err != nil {
+			return err
+		}
+		if err = fn(seq, payload, mime); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
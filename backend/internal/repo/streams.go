@@ -2,15 +2,33 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 
 	"stream-server/internal/data/repo"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-func (r *StreamRepo) ListStreams(ctx context.Context) ([]repo.ListStreamsRow, error) {
-	return r.queries.ListStreams(ctx)
+// ListStreams страница списка стримов (keyset по created_at, id); направление выбирает запрос, чтобы работал индекс
+func (r *StreamRepo) ListStreams(ctx context.Context, in repo.ListStreamsParams, oldestFirst bool) ([]repo.ListStreamsRow, error) {
+	if !oldestFirst {
+		return r.queries.ListStreams(ctx, in)
+	}
+
+	rows, err := r.queries.ListStreamsOldestFirst(ctx, repo.ListStreamsOldestFirstParams(in))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]repo.ListStreamsRow, len(rows))
+	for i := range rows {
+		res[i] = repo.ListStreamsRow(rows[i])
+	}
+	return res, nil
 }
 
 func (r *StreamRepo) GetStream(ctx context.Context, ID pgtype.UUID) (repo.GetStreamRow, error) {
@@ -34,3 +52,130 @@ func (r *StreamRepo) UpdateStream(ctx context.Context, in repo.UpdateStreamParam
 
 	return res, tx.Commit(ctx)
 }
+
+func (r *StreamRepo) CreateStream(ctx context.Context, in repo.CreateStreamParams) (res repo.Stream, err error) {
+	return r.queries.CreateStream(ctx, in)
+}
+
+// DeleteStream удаляет стрим вместе с кадрами (ON DELETE CASCADE)
+// Если стрима нет, то возвращаем pgx.ErrNoRows — так же, как GetStream
+func (r *StreamRepo) DeleteStream(ctx context.Context, ID pgtype.UUID) error {
+	n, err := r.queries.DeleteStream(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("delete stream: %w", err)
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// AppendFrames дописывает пачку кадров в конец стрима одной транзакцией через COPY
+// Строка стрима блокируется (FOR UPDATE), поэтому параллельные загрузки в один стрим получают
+// непересекающиеся и непрерывные диапазоны sequence. Возвращает sequence первого кадра пачки
+func (r *StreamRepo) AppendFrames(ctx context.Context, streamID pgtype.UUID, frames []repo.InsertFramesParams) (firstSeq int32, err error) {
+	tx, err := r.data.DBClientPool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	qtx := r.queries.WithTx(tx)
+
+	if _, err = qtx.LockStream(ctx, streamID); err != nil {
+		return 0, fmt.Errorf("lock stream: %w", err)
+	}
+	firstSeq, err = qtx.GetNextFrameSequence(ctx, streamID)
+	if err != nil {
+		return 0, fmt.Errorf("next sequence: %w", err)
+	}
+	if int64(firstSeq)+int64(len(frames)) > math.MaxInt32 {
+		return 0, fmt.Errorf("sequence overflow: next=%d frames=%d", firstSeq, len(frames))
+	}
+
+	for i := range frames {
+		frames[i].ID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+		frames[i].StreamID = streamID
+		frames[i].Sequence = firstSeq + int32(i)
+	}
+	if _, err = qtx.InsertFrames(ctx, frames); err != nil {
+		return 0, fmt.Errorf("copy frames: %w", err)
+	}
+	// счётчик в той же транзакции: список стримов читает его вместо count(frames)
+	if err = qtx.AddStreamFrameCount(ctx, repo.AddStreamFrameCountParams{ID: streamID, FrameCount: int64(len(frames))}); err != nil {
+		return 0, fmt.Errorf("frame count: %w", err)
+	}
+
+	return firstSeq, tx.Commit(ctx)
+}
+
+// ImportStream создаёт стрим и записывает в него все кадры одной транзакцией: пачки next идут через COPY с sequence от 0,
+// next возвращает io.EOF после последней пачки. До коммита стрим не виден ни в списке, ни зрителям,
+// а при ошибке (или падении процесса) откатывается целиком — от неудачного импорта ничего не остаётся
+func (r *StreamRepo) ImportStream(ctx context.Context, in repo.CreateStreamParams, next func() ([]repo.InsertFramesParams, error)) (res repo.Stream, err error) {
+	tx, err := r.data.DBClientPool.Begin(ctx)
+	if err != nil {
+		return res, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	qtx := r.queries.WithTx(tx)
+
+	res, err = qtx.CreateStream(ctx, in)
+	if err != nil {
+		return res, fmt.Errorf("create stream: %w", err)
+	}
+
+	var count int64
+	for {
+		frames, nextErr := next()
+		if int64(len(frames))+count > math.MaxInt32 {
+			return res, fmt.Errorf("sequence overflow: next=%d frames=%d", count, len(frames))
+		}
+		for i := range frames {
+			frames[i].ID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+			frames[i].StreamID = res.ID
+			frames[i].Sequence = int32(count) + int32(i)
+		}
+		if len(frames) > 0 {
+			if _, err = qtx.InsertFrames(ctx, frames); err != nil {
+				return res, fmt.Errorf("copy frames: %w", err)
+			}
+			count += int64(len(frames))
+		}
+		if errors.Is(nextErr, io.EOF) {
+			break
+		}
+		if nextErr != nil {
+			return res, nextErr
+		}
+	}
+	if err = qtx.AddStreamFrameCount(ctx, repo.AddStreamFrameCountParams{ID: res.ID, FrameCount: count}); err != nil {
+		return res, fmt.Errorf("frame count: %w", err)
+	}
+	res.FrameCount = count
+
+	return res, tx.Commit(ctx)
+}
+
+func (r *StreamRepo) GetStreamThumbnail(ctx context.Context, ID pgtype.UUID) (repo.GetStreamThumbnailRow, error) {
+	return r.queries.GetStreamThumbnail(ctx, ID)
+}
+
+// SetStreamThumbnail записывает постер, только если его ещё нет (повторная генерация ничего не перетирает)
+func (r *StreamRepo) SetStreamThumbnail(ctx context.Context, ID pgtype.UUID, thumbnail []byte) error {
+	return r.queries.SetStreamThumbnail(ctx, repo.SetStreamThumbnailParams{ID: ID, Thumbnail: thumbnail})
+}
+
+// GetFrameRangeStats число и объём кадров диапазона с заданным mime (по индексу stream_id, sequence)
+func (r *StreamRepo) GetFrameRangeStats(ctx context.Context, in repo.GetFrameRangeStatsParams) (repo.GetFrameRangeStatsRow, error) {
+	return r.queries.GetFrameRangeStats(ctx, in)
+}
+
+// ListStreamsWithoutThumbnail стримы с кадрами, но без постера — вместе с первым кадром
+func (r *StreamRepo) ListStreamsWithoutThumbnail(ctx context.Context, limit int32) ([]repo.ListStreamsWithoutThumbnailRow, error) {
+	return r.queries.ListStreamsWithoutThumbnail(ctx, limit)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	otel "go.opentelemetry.io/otel/metric"
+
+	v1 "stream-server/api/v1"
+	"stream-server/config"
+	utils "stream-server/internal/server/server_utils"
+)
+
+// NewGRPCStreamServer — те же StreamService и HealthService по gRPC
+// Сервисы передаются как v1.*Server: интерфейсы из interfaces не содержат mustEmbedUnimplemented*
+func NewGRPCStreamServer(cfg *conf.Config, service v1.StreamServiceServer, healthService v1.HealthServiceServer, meter otel.Meter, logger *log.Helper) *grpc.Server {
+	chain, err := serverMiddleware(meter, logger)
+	if err != nil {
+		return nil
+	}
+
+	var opts = []grpc.ServerOption{
+		grpc.Middleware(chain...),
+		// stream-middleware кратоса оборачивает только SendMsg, поэтому для IngestFrames — отдельный перехватчик
+		grpc.StreamInterceptor(utils.StreamRecovery(logger)),
+	}
+	if cfg.Grpc.Network != "" {
+		opts = append(opts, grpc.Network(cfg.Grpc.Network))
+	}
+	if cfg.Grpc.Addr != "" {
+		opts = append(opts, grpc.Address(cfg.Grpc.Addr))
+	}
+	if cfg.Grpc.Timeout != 0 {
+		opts = append(opts, grpc.Timeout(time.Duration(cfg.Grpc.Timeout)*time.Second))
+	}
+	srv := grpc.NewServer(opts...)
+
+	v1.RegisterStreamServiceServer(srv, service)
+	v1.RegisterHealthServiceServer(srv, healthService)
+
+	return srv
+}
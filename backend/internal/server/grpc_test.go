@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	kgrpc "github.com/go-kratos/kratos/v2/transport/grpc"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	v1 "stream-server/api/v1"
+	"stream-server/config"
+)
+
+type stubStreamServer struct {
+	v1.UnimplementedStreamServiceServer
+}
+
+func (stubStreamServer) GetStream(_ context.Context, in *v1.GetStreamRequest) (*v1.GetStreamResponse, error) {
+	return nil, v1.ErrorStreamNotFound("stream %s not found", in.Id)
+}
+
+type stubHealthServer struct {
+	v1.UnimplementedHealthServiceServer
+}
+
+func (stubHealthServer) Live(context.Context, *emptypb.Empty) (*v1.HealthReply, error) {
+	return &v1.HealthReply{}, nil
+}
+
+func TestGRPCStreamServer(t *testing.T) {
+	cfg := &conf.Config{Grpc: conf.Grpc{Addr: "127.0.0.1:0", Timeout: 5}}
+	logger := log.NewHelper(log.NewStdLogger(io.Discard))
+	srv := NewGRPCStreamServer(cfg, stubStreamServer{}, stubHealthServer{}, noop.NewMeterProvider().Meter("test"), logger)
+	if srv == nil {
+		t.Fatal("server not built")
+	}
+	endpoint, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Start(context.Background()) }()
+	defer func() { _ = srv.Stop(context.Background()) }()
+
+	ctx := context.Background()
+	conn, err := kgrpc.DialInsecure(ctx, kgrpc.WithEndpoint(endpoint.Host))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err = v1.NewHealthServiceClient(conn).Live(ctx, &emptypb.Empty{}); err != nil {
+		t.Fatalf("live: %v", err)
+	}
+
+	client := v1.NewStreamServiceClient(conn)
+	// ошибка сервиса — статус по ErrorReason
+	_, err = client.GetStream(ctx, &v1.GetStreamRequest{Id: "6f1c2e0a-3b7d-4c5e-9f10-2a3b4c5d6e7f"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	// валидация срабатывает до сервиса
+	_, err = client.GetStream(ctx, &v1.GetStreamRequest{Id: "nope"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
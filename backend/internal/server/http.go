@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/middleware/logging"
 	"github.com/go-kratos/kratos/v2/middleware/metrics"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
@@ -27,28 +28,35 @@ func NewHTTPStreamServer(cfg *conf.Config, service interfaces.IStreamService, he
 	// Websocket
 	srv.Handle("/v1/streams/{id}/ws", service.StreamWSHandler())
 
+	// MJPEG (multipart/x-mixed-replace)
+	srv.Handle("/v1/streams/{id}/mjpeg", service.MJPEGHandler())
+
+	// Multipart upload кадров
+	srv.Handle("/v1/streams/{id}/frames", service.IngestHTTPHandler())
+
+	// Импорт файла (Motion-JPEG AVI / сырой MJPEG) в новый стрим
+	srv.Handle("/v1/streams/import", service.ImportHTTPHandler())
+
+	// Отдельные кадры и превью
+	srv.Handle("/v1/streams/{id}/frames/{seq}", service.FrameHTTPHandler())
+	srv.Handle("/v1/streams/{id}/snapshot", service.SnapshotHTTPHandler())
+	srv.Handle("/v1/streams/{id}/thumbnail", service.ThumbnailHTTPHandler())
+
+	// Выгрузка диапазона кадров архивом
+	srv.Handle("/v1/streams/{id}/export", service.ExportHTTPHandler())
+
 	return srv
 }
 
 func newHTTPServer(cfg *conf.Config, meter otel.Meter, logger *log.Helper) *http.Server {
-	counter, err := metrics.DefaultRequestsCounter(meter, metrics.DefaultServerRequestsCounterName)
-	if err != nil {
-		return nil
-	}
-
-	seconds, err := metrics.DefaultSecondsHistogram(meter, metrics.DefaultServerSecondsHistogramName)
+	chain, err := serverMiddleware(meter, logger)
 	if err != nil {
 		return nil
 	}
 
 	var opts = []http.ServerOption{
 		http.Filter(utils.CORS()),
-		http.Middleware(
-			recovery.Recovery(),
-			tracing.Server(),
-			metrics.Server(metrics.WithRequests(counter), metrics.WithSeconds(seconds)),
-			logging.Server(logger.Logger()),
-		),
+		http.Middleware(chain...),
 	}
 	if cfg.Http.Network != "" {
 		opts = append(opts, http.Network(cfg.Http.Network))
@@ -63,3 +71,24 @@ func newHTTPServer(cfg *conf.Config, meter otel.Meter, logger *log.Helper) *http
 
 	return srv
 }
+
+// serverMiddleware — общая цепочка middleware для HTTP и gRPC серверов
+func serverMiddleware(meter otel.Meter, logger *log.Helper) ([]middleware.Middleware, error) {
+	counter, err := metrics.DefaultRequestsCounter(meter, metrics.DefaultServerRequestsCounterName)
+	if err != nil {
+		return nil, err
+	}
+
+	seconds, err := metrics.DefaultSecondsHistogram(meter, metrics.DefaultServerSecondsHistogramName)
+	if err != nil {
+		return nil, err
+	}
+
+	return []middleware.Middleware{
+		recovery.Recovery(),
+		tracing.Server(),
+		metrics.Server(metrics.WithRequests(counter), metrics.WithSeconds(seconds)),
+		logging.Server(logger.Logger()),
+		utils.Validator(),
+	}, nil
+}
@@ -0,0 +1,24 @@
+package server_utils
+
+import (
+	"runtime"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	"google.golang.org/grpc"
+)
+
+// StreamRecovery — recovery для streaming-вызовов gRPC: паника в обработчике не роняет процесс
+func StreamRecovery(logger *log.Helper) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if rerr := recover(); rerr != nil {
+				buf := make([]byte, 64<<10)
+				buf = buf[:runtime.Stack(buf, false)]
+				logger.WithContext(ss.Context()).Errorf("%v: %s\n%s", rerr, info.FullMethod, buf)
+				err = recovery.ErrUnknownRequest
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,27 @@
+package server_utils
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+
+	v1 "stream-server/api/v1"
+)
+
+type validator interface {
+	Validate() error
+}
+
+// Validator — PGV-валидация запроса; ошибка отдаётся как INVALID_ARGUMENT (400), а не VALIDATOR кратоса
+func Validator() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if v, ok := req.(validator); ok {
+				if err := v.Validate(); err != nil {
+					return nil, v1.ErrorInvalidArgument("%v", err).WithCause(err)
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
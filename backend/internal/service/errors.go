@@ -0,0 +1,15 @@
+package service
+
+import (
+	"net/http"
+
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+
+	"stream-server/internal/biz"
+)
+
+// writeError — ошибка в формате kratos ({code, reason, message}) с HTTP-кодом по ErrorReason
+// Так же отвечают сгенерированные REST-ручки, поэтому клиенты разбирают ошибки одинаково
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	khttp.DefaultErrorEncoder(w, r, biz.ToApiError(err))
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz"
+	session_pool "stream-server/internal/biz/session"
+	"stream-server/internal/interfaces"
+	"stream-server/pkg/avi"
+)
+
+// frameArchive — архив кадров, который пишется в ответ по мере чтения из БД
+type frameArchive interface {
+	add(name string, payload []byte) error
+	Close() error
+}
+
+// zipArchive — без сжатия (Store): JPEG уже сжат, deflate только тратил бы CPU
+type zipArchive struct {
+	zw      *zip.Writer
+	modTime time.Time
+}
+
+func (z *zipArchive) add(name string, payload []byte) error {
+	w, err := z.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: z.modTime})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(payload)
+	return err
+}
+
+func (z *zipArchive) Close() error {
+	return z.zw.Close()
+}
+
+type tarArchive struct {
+	tw      *tar.Writer
+	modTime time.Time
+}
+
+func (t *tarArchive) add(name string, payload []byte) error {
+	err := t.tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(payload)), ModTime: t.modTime, Format: tar.FormatPAX})
+	if err != nil {
+		return err
+	}
+	_, err = t.tw.Write(payload)
+	return err
+}
+
+func (t *tarArchive) Close() error {
+	return t.tw.Close()
+}
+
+// aviArchive — Motion-JPEG AVI: имена кадров не нужны, порядок задаёт поток
+type aviArchive struct {
+	*avi.Writer
+}
+
+func (a aviArchive) add(_ string, payload []byte) error {
+	return a.WriteFrame(payload)
+}
+
+// openArchive — архив поверх ответа; first — первый кадр (AVI берёт из него размеры картинки)
+// Ошибка до первой записи в w ещё позволяет ответить кодом
+type openArchive func(w io.Writer, first []byte) (frameArchive, error)
+
+func openZip(w io.Writer, _ []byte) (frameArchive, error) {
+	return &zipArchive{zw: zip.NewWriter(w), modTime: time.Now()}, nil
+}
+
+func openTar(w io.Writer, _ []byte) (frameArchive, error) {
+	return &tarArchive{tw: tar.NewWriter(w), modTime: time.Now()}, nil
+}
+
+// openAVI — размеры RIFF посчитаны заранее по сводке диапазона, ширина/высота — по первому кадру
+func openAVI(layout avi.Layout) openArchive {
+	return func(w io.Writer, first []byte) (frameArchive, error) {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(first))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", biz.ErrFrameUndecodable, err)
+		}
+		layout.Width, layout.Height = cfg.Width, cfg.Height
+		aw, err := avi.NewWriter(w, layout)
+		if errors.Is(err, avi.ErrTooLarge) {
+			return nil, v1.ErrorInvalidArgument("frames are too large for AVI: %dx%d", cfg.Width, cfg.Height)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return aviArchive{aw}, nil
+	}
+}
+
+// exportWriter — откладывает ответ до первого кадра: до него ошибку ещё можно отдать кодом
+type exportWriter struct {
+	w             http.ResponseWriter
+	rc            *http.ResponseController
+	format        string
+	name          string
+	mime          string // только кадры с этим mime ("" — все)
+	contentLength int64  // известен заранее только для AVI
+	open          openArchive
+	archive       frameArchive
+}
+
+func (e *exportWriter) started() bool {
+	return e.archive != nil
+}
+
+// start — заголовки и архив; вызывается на первом кадре или на пустом диапазоне
+// Код 200 уходит с первой записью архива, поэтому ошибка open ещё не испортила ответ
+func (e *exportWriter) start(first []byte) error {
+	h := e.w.Header()
+	h.Set("Content-Type", exportContentTypes[e.format])
+	h.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, e.name, e.format))
+	h.Set("Cache-Control", "no-store")
+	h.Set("X-Accel-Buffering", "no")
+	if e.contentLength > 0 {
+		h.Set("Content-Length", strconv.FormatInt(e.contentLength, 10))
+	}
+
+	archive, err := e.open(e.w, first)
+	if err != nil {
+		for _, k := range []string{"Content-Type", "Content-Disposition", "Content-Length"} {
+			h.Del(k)
+		}
+		return err
+	}
+	e.archive = archive
+	return nil
+}
+
+func (e *exportWriter) frame(seq int64, payload []byte, mime string) error {
+	if e.mime != "" && mime != e.mime {
+		return nil
+	}
+	// защита от клиента, который перестал читать
+	_ = e.rc.SetWriteDeadline(time.Now().Add(frameWriteWait))
+	if !e.started() {
+		if err := e.start(payload); err != nil {
+			return err
+		}
+	}
+	return e.archive.add(frameFileName(seq, mime), payload)
+}
+
+// aviFrameMime — в Motion-JPEG идут только JPEG-кадры
+const aviFrameMime = "image/jpeg"
+
+var exportContentTypes = map[string]string{
+	"zip": "application/zip",
+	"tar": "application/x-tar",
+	"avi": "video/x-msvideo",
+}
+
+// frameExtensions — расширение файла кадра по mime; неизвестные картинки — .bin
+var frameExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+	"image/bmp":  "bmp",
+}
+
+// frameFileName — имя кадра в архиве: sequence с ведущими нулями, чтобы сортировка по имени совпадала с порядком кадров
+func frameFileName(seq int64, mime string) string {
+	ext, ok := frameExtensions[mime]
+	if !ok {
+		ext = "bin"
+	}
+	return fmt.Sprintf("%010d.%s", seq, ext)
+}
+
+// parseExportRange — ?from_seq= (по умолчанию 0) и ?to_seq= (по умолчанию до конца стрима), включительно
+func parseExportRange(r *http.Request) (fromSeq, toSeq int64, err error) {
+	q := r.URL.Query()
+	fromSeq, toSeq = 0, math.MaxInt32 // sequence в БД — integer
+	if raw := q.Get("from_seq"); raw != "" {
+		if fromSeq, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("bad from_seq")
+		}
+	}
+	if raw := q.Get("to_seq"); raw != "" {
+		if toSeq, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("bad to_seq")
+		}
+	}
+	return fromSeq, toSeq, nil
+}
+
+// ExportHandler — GET /v1/streams/{id}/export?from_seq=&to_seq=&format=zip|tar|avi: диапазон кадров архивом или видео
+// Архив пишется потоком прямо из курсора БД, мимо кэша чанков: память не зависит от длины диапазона,
+// а выгрузка не вытесняет чанки зрителей. Ошибка после начала ответа обрывает соединение — архив будет неполным
+// avi — Motion-JPEG из JPEG-кадров диапазона (остальные пропускаются), частота кадров — из frame_interval_ms
+func ExportHandler(uc interfaces.IUsecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		streamID, err := parseStreamID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "zip"
+		}
+		if _, ok := exportContentTypes[format]; !ok {
+			writeError(w, r, v1.ErrorInvalidArgument("format must be zip, tar or avi"))
+			return
+		}
+		fromSeq, toSeq, err := parseExportRange(r)
+		if err != nil {
+			writeError(w, r, v1.ErrorInvalidArgument("%v", err))
+			return
+		}
+
+		// выгрузка длинная: таймаут запроса сервера на неё не действует, отменяет только уход клиента
+		ctx, cancel := sessionContext(r)
+		defer cancel()
+
+		// имя файла: <id>_<from>-<to>, без to — до конца стрима
+		name := fmt.Sprintf("%s_%d-", streamID, fromSeq)
+		if r.URL.Query().Get("to_seq") != "" {
+			name += strconv.FormatInt(toSeq, 10)
+		}
+		out := &exportWriter{w: w, rc: http.NewResponseController(w), format: format, name: name, open: openZip}
+
+		switch format {
+		case "tar":
+			out.open = openTar
+			err = uc.ExportFrames(ctx, streamID.String(), fromSeq, toSeq, out.frame)
+		case "avi":
+			// RIFF требует размеры заранее: сводка и кадры читаются из одного снимка, поэтому сходятся
+			err = uc.ExportFrameRange(ctx, streamID.String(), fromSeq, toSeq, aviFrameMime, out.prepareAVI, out.frame)
+		default:
+			err = uc.ExportFrames(ctx, streamID.String(), fromSeq, toSeq, out.frame)
+		}
+		if err == nil && !out.started() {
+			err = out.start(nil) // пустой диапазон — пустой, но корректный архив
+		}
+		if err == nil {
+			err = out.archive.Close()
+		}
+		switch {
+		case err != nil && !out.started():
+			writeError(w, r, err)
+		case err != nil:
+			// код ответа уже ушёл: рвём соединение, чтобы клиент не принял обрезанный архив за целый
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// prepareAVI — сводка JPEG-кадров диапазона → размеры AVI
+func (e *exportWriter) prepareAVI(fr *interfaces.FrameRange) error {
+	if fr.Frames == 0 {
+		return v1.ErrorFrameNotFound("no JPEG frames in range")
+	}
+	interval := int(fr.IntervalMS)
+	if interval <= 0 {
+		interval = int(session_pool.DefaultInterval.Milliseconds())
+	}
+	layout := avi.Layout{IntervalMS: interval, Frames: fr.Frames, Bytes: fr.Bytes, OddFrames: fr.OddFrames}
+	if size := layout.FileSize(); size > avi.MaxFileSize {
+		return v1.ErrorInvalidArgument("range is too large for AVI (%d bytes, max %d): narrow from_seq/to_seq", size, int64(avi.MaxFileSize))
+	}
+
+	e.mime = aviFrameMime
+	e.contentLength = layout.FileSize()
+	e.open = openAVI(layout)
+	return nil
+}
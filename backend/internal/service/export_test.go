@@ -0,0 +1,141 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"stream-server/internal/biz"
+)
+
+func exportUsecase() *stubUsecase {
+	return &stubUsecase{frames: []ingested{
+		{payload: []byte{0xFF, 0xD8, 0}, mime: "image/jpeg"},
+		{payload: []byte{0xFF, 0xD8, 1}, mime: "image/jpeg"},
+		{payload: []byte{0x89, 'P', 2}, mime: "image/png"},
+	}}
+}
+
+func TestExportHandler_Zip(t *testing.T) {
+	id := uuid.New()
+	rec := httptest.NewRecorder()
+	ExportHandler(exportUsecase())(rec, httptest.NewRequest(http.MethodGet, "/v1/streams/"+id.String()+"/export?from_seq=1", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="`+id.String()+`_1-.zip"` {
+		t.Fatalf("unexpected disposition %q", cd)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 2 || zr.File[0].Name != "0000000001.jpg" || zr.File[1].Name != "0000000002.png" {
+		t.Fatalf("unexpected entries %v", zr.File)
+	}
+	rc, _ := zr.File[1].Open()
+	data, _ := io.ReadAll(rc)
+	if !bytes.Equal(data, []byte{0x89, 'P', 2}) || zr.File[1].Method != zip.Store {
+		t.Fatalf("unexpected entry: %v method=%d", data, zr.File[1].Method)
+	}
+}
+
+func TestExportHandler_Tar(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ExportHandler(exportUsecase())(rec, httptest.NewRequest(http.MethodGet, "/v1/streams/"+uuid.NewString()+"/export?format=tar&to_seq=0", nil))
+
+	tr := tar.NewReader(rec.Body)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != "0000000000.jpg" || hdr.Size != 3 {
+		t.Fatalf("unexpected entry %+v err=%v", hdr, err)
+	}
+	if _, err = tr.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected single entry, got %v", err)
+	}
+}
+
+func TestExportHandler_Errors(t *testing.T) {
+	id := uuid.NewString()
+	for _, c := range []struct {
+		url  string
+		uc   *stubUsecase
+		code int
+	}{
+		{"/v1/streams/nope/export", exportUsecase(), http.StatusBadRequest},
+		{"/v1/streams/" + id + "/export?format=rar", exportUsecase(), http.StatusBadRequest},
+		{"/v1/streams/" + id + "/export?from_seq=x", exportUsecase(), http.StatusBadRequest},
+		{"/v1/streams/" + id + "/export?from_seq=5&to_seq=1", &stubUsecase{err: biz.ToApiError(biz.ErrBadRange)}, http.StatusBadRequest},
+		{"/v1/streams/" + id + "/export", &stubUsecase{err: biz.ToApiError(pgx.ErrNoRows)}, http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		ExportHandler(c.uc)(rec, httptest.NewRequest(http.MethodGet, c.url, nil))
+		if rec.Code != c.code || rec.Header().Get("Content-Disposition") != "" {
+			t.Fatalf("%s: expected %d, got %d %v", c.url, c.code, rec.Code, rec.Header())
+		}
+	}
+
+	// ошибка посреди выгрузки: ответ уже начат — соединение обрывается, а не завершается целым архивом
+	uc := exportUsecase()
+	uc.exportErr = errors.New("connection reset")
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Fatalf("expected abort, got %v", r)
+		}
+	}()
+	ExportHandler(uc)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/streams/"+id+"/export", nil))
+}
+
+func TestExportHandler_AVI(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	var frame bytes.Buffer
+	if err := jpeg.Encode(&frame, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	uc := &stubUsecase{frames: []ingested{
+		{payload: frame.Bytes(), mime: "image/jpeg"},
+		{payload: []byte{0x89, 'P', 1}, mime: "image/png"}, // не JPEG — в видео не попадает
+		{payload: append(frame.Bytes(), 0), mime: "image/jpeg"},
+	}}
+	id := uuid.NewString()
+	rec := httptest.NewRecorder()
+	ExportHandler(uc)(rec, httptest.NewRequest(http.MethodGet, "/v1/streams/"+id+"/export?format=avi", nil))
+
+	body := rec.Body.Bytes()
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "video/x-msvideo" {
+		t.Fatalf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != strconv.Itoa(len(body)) {
+		t.Fatalf("Content-Length %s, body %d", cl, len(body))
+	}
+	if string(body[:4]) != "RIFF" || string(body[8:12]) != "AVI " || int(binary.LittleEndian.Uint32(body[4:])) != len(body)-8 {
+		t.Fatalf("bad RIFF header % x", body[:12])
+	}
+	// avih: dwTotalFrames и размеры из первого кадра
+	avih := body[bytes.Index(body, []byte("avih"))+8:]
+	if n, w, h := binary.LittleEndian.Uint32(avih[16:]), binary.LittleEndian.Uint32(avih[32:]), binary.LittleEndian.Uint32(avih[36:]); n != 2 || w != 32 || h != 16 {
+		t.Fatalf("avih frames=%d size=%dx%d", n, w, h)
+	}
+	if bytes.Count(body, []byte("00dc")) != 4 { // 2 чанка кадров + 2 записи idx1
+		t.Fatalf("expected 2 frames with index")
+	}
+
+	// без JPEG-кадров видео не собрать
+	rec = httptest.NewRecorder()
+	ExportHandler(uc)(rec, httptest.NewRequest(http.MethodGet, "/v1/streams/"+id+"/export?format=avi&from_seq=1&to_seq=1", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
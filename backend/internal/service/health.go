@@ -10,6 +10,8 @@ import (
 )
 
 type HealthService struct {
+	v1.UnimplementedHealthServiceServer
+
 	db *pgxpool.Pool
 }
 
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	kjson "github.com/go-kratos/kratos/v2/encoding/json"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz"
+	"stream-server/internal/interfaces"
+)
+
+// ImportHandler — POST /v1/streams/import?title=&description=&frame_interval_ms=&format=auto|avi|mjpeg
+// Тело — файл целиком: Motion-JPEG AVI или JPEG-кадры подряд. Создаёт новый стрим, в ответ — CreateStreamResponse в JSON
+// frame_interval_ms по умолчанию берётся из заголовка AVI, для сырого MJPEG — 40 (25fps)
+func ImportHandler(uc interfaces.IUsecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		var interval int64
+		if raw := q.Get("frame_interval_ms"); raw != "" {
+			var err error
+			if interval, err = strconv.ParseInt(raw, 10, 32); err != nil {
+				writeError(w, r, v1.ErrorInvalidArgument("bad frame_interval_ms"))
+				return
+			}
+		}
+
+		src, detected, err := biz.OpenImport(r.Body, q.Get("format"))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		in := &v1.CreateStreamRequest{
+			Title:           q.Get("title"),
+			Description:     q.Get("description"),
+			FrameIntervalMs: biz.ImportInterval(int32(interval), detected),
+		}
+
+		// загрузка файла длинная: таймаут запроса сервера на неё не действует, отменяет только уход клиента
+		ctx, cancel := sessionContext(r)
+		defer cancel()
+
+		stream, err := uc.ImportStream(ctx, in, src)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		body, err := encoding.GetCodec(kjson.Name).Marshal(&v1.CreateStreamResponse{Stream: stream})
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}
+}
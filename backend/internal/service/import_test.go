@@ -0,0 +1,88 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestImportHandler_AVIRoundTrip — AVI из выгрузки импортируется обратно теми же кадрами и с тем же интервалом
+func TestImportHandler_AVIRoundTrip(t *testing.T) {
+	var frame bytes.Buffer
+	if err := jpeg.Encode(&frame, image.NewGray(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatal(err)
+	}
+	src := &stubUsecase{frames: []ingested{
+		{payload: frame.Bytes(), mime: "image/jpeg"},
+		{payload: append(frame.Bytes()[:len(frame.Bytes()):len(frame.Bytes())], 0), mime: "image/jpeg"}, // нечётная длина
+	}}
+	exported := httptest.NewRecorder()
+	ExportHandler(src)(exported, httptest.NewRequest(http.MethodGet, "/v1/streams/"+uuid.NewString()+"/export?format=avi", nil))
+	if exported.Code != http.StatusOK {
+		t.Fatalf("export: %d %s", exported.Code, exported.Body)
+	}
+
+	uc := &stubUsecase{}
+	rec := httptest.NewRecorder()
+	ImportHandler(uc)(rec, httptest.NewRequest(http.MethodPost, "/v1/streams/import?title=cam&description=d", exported.Body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("import: %d %s", rec.Code, rec.Body)
+	}
+	var res struct {
+		Stream struct {
+			Title           string `json:"title"`
+			FrameIntervalMs int32  `json:"frameIntervalMs"`
+			FrameCount      string `json:"frameCount"`
+		} `json:"stream"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Stream.Title != "cam" || res.Stream.FrameIntervalMs != 40 || res.Stream.FrameCount != "2" {
+		t.Fatalf("unexpected response %s", rec.Body)
+	}
+	for i := range src.frames {
+		if !bytes.Equal(uc.frames[i].payload, src.frames[i].payload) || uc.frames[i].mime != "image/jpeg" {
+			t.Fatalf("frame %d differs", i)
+		}
+	}
+}
+
+func TestImportHandler_MJPEG(t *testing.T) {
+	body := []byte{0xFF, 0xD8, 0xFF, 0xD9, '\r', '\n', 0xFF, 0xD8, 0xFF, 0xD9}
+	uc := &stubUsecase{}
+	rec := httptest.NewRecorder()
+	ImportHandler(uc)(rec, httptest.NewRequest(http.MethodPost, "/v1/streams/import?title=raw&frame_interval_ms=100", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK || len(uc.frames) != 2 || !bytes.Contains(rec.Body.Bytes(), []byte(`"frameIntervalMs":100`)) {
+		t.Fatalf("unexpected response %d %s, %d frames", rec.Code, rec.Body, len(uc.frames))
+	}
+}
+
+func TestImportHandler_Errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		query  string
+		want   int
+	}{
+		{"method", http.MethodGet, "?title=x", http.StatusMethodNotAllowed},
+		{"bad interval", http.MethodPost, "?title=x&frame_interval_ms=fast", http.StatusBadRequest},
+		{"bad format", http.MethodPost, "?title=x&format=gif", http.StatusBadRequest},
+		{"not avi", http.MethodPost, "?title=x&format=avi", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ImportHandler(&stubUsecase{})(rec, httptest.NewRequest(tc.method, "/v1/streams/import"+tc.query, bytes.NewReader([]byte("junk"))))
+			if rec.Code != tc.want {
+				t.Fatalf("code %d, want %d: %s", rec.Code, tc.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	kjson "github.com/go-kratos/kratos/v2/encoding/json"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz/session/store_pool"
+	"stream-server/internal/interfaces"
+)
+
+// grpcFrameSource — адаптер client-stream gRPC к FrameSource
+// Первое сообщение уже прочитано (из него берём stream_id), поэтому отдаём его первым
+type grpcFrameSource struct {
+	stream   v1.StreamService_IngestFramesServer
+	streamID string
+	pending  *v1.IngestFramesRequest
+}
+
+func (g *grpcFrameSource) Next() ([]byte, string, error) {
+	msg := g.pending
+	g.pending = nil
+	if msg == nil {
+		var err error
+		if msg, err = g.stream.Recv(); err != nil {
+			return nil, "", err // io.EOF — клиент закончил отправку
+		}
+	}
+	if msg.StreamId != "" && msg.StreamId != g.streamID {
+		return nil, "", v1.ErrorInvalidArgument("stream_id changed mid-stream: %q != %q", msg.StreamId, g.streamID)
+	}
+	return msg.Payload, msg.MimeType, nil
+}
+
+func (s *StreamService) IngestFrames(stream v1.StreamService_IngestFramesServer) error {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return stream.SendAndClose(&v1.IngestFramesResponse{FirstSeq: -1, LastSeq: -1})
+	}
+	if err != nil {
+		return err
+	}
+	if first.StreamId == "" {
+		return v1.ErrorInvalidArgument("stream_id is required in the first message")
+	}
+
+	src := &grpcFrameSource{stream: stream, streamID: first.StreamId, pending: first}
+	res, err := s.uc.IngestFrames(stream.Context(), first.StreamId, src)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(res)
+}
+
+// multipartFrameSource — кадры из multipart/form-data: каждая file-часть формы — один кадр
+// Части читаются потоково (без ParseMultipartForm), в памяти держим только текущую пачку
+type multipartFrameSource struct {
+	mr *multipart.Reader
+}
+
+func (m *multipartFrameSource) Next() ([]byte, string, error) {
+	for {
+		part, err := m.mr.NextPart()
+		if err != nil {
+			return nil, "", err // io.EOF — части закончились
+		}
+		if part.FileName() == "" {
+			_ = part.Close() // обычные поля формы пропускаем
+			continue
+		}
+
+		// +1 байт, чтобы отличить "ровно лимит" от "больше лимита"
+		data, err := io.ReadAll(io.LimitReader(part, store_pool.MaxFrameBytes+1))
+		_ = part.Close()
+		if err != nil {
+			return nil, "", err
+		}
+
+		mime := part.Header.Get("Content-Type")
+		if mime == "" || mime == "application/octet-stream" {
+			mime = http.DetectContentType(data)
+		}
+		return data, mime, nil
+	}
+}
+
+// IngestFramesHandler — multipart-загрузка кадров: POST /v1/streams/{id}/frames
+// Порядок file-частей задаёт порядок sequence; в ответ — IngestFramesResponse в JSON
+func IngestFramesHandler(uc interfaces.IUsecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		streamID, err := parseStreamID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			writeError(w, r, v1.ErrorInvalidArgument("expected multipart/form-data body"))
+			return
+		}
+
+		res, err := uc.IngestFrames(r.Context(), streamID.String(), &multipartFrameSource{mr: mr})
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		body, err := encoding.GetCodec(kjson.Name).Marshal(res)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}
+}
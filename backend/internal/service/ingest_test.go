@@ -0,0 +1,78 @@
+package service
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"stream-server/internal/biz"
+)
+
+func multipartBody(t *testing.T, parts map[string]string, payloads ...[]byte) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	for k, v := range parts {
+		_ = mw.WriteField(k, v)
+	}
+	for i, p := range payloads {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="frame"; filename="f`+string(rune('0'+i))+`.jpg"`)
+		h.Set("Content-Type", "image/jpeg")
+		w, err := mw.CreatePart(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write(p)
+	}
+	_ = mw.Close()
+	return body, mw.FormDataContentType()
+}
+
+func TestIngestFramesHandler_Multipart(t *testing.T) {
+	uc := &stubUsecase{}
+	body, ct := multipartBody(t, map[string]string{"note": "skip me"}, []byte{0xFF, 0xD8, 1}, []byte{0xFF, 0xD8, 2})
+	req := httptest.NewRequest(http.MethodPost, "/v1/streams/"+uuid.NewString()+"/frames", body)
+	req.Header.Set("Content-Type", ct)
+	rec := httptest.NewRecorder()
+
+	IngestFramesHandler(uc)(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(uc.frames) != 2 || uc.frames[1].payload[2] != 2 || uc.frames[0].mime != "image/jpeg" {
+		t.Fatalf("unexpected frames: %#v", uc.frames)
+	}
+	if !strings.Contains(rec.Body.String(), `"count":"2"`) {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestIngestFramesHandler_ErrorStatus(t *testing.T) {
+	uc := &stubUsecase{err: biz.ErrFrameNotImage}
+	body, ct := multipartBody(t, nil, []byte{1})
+	req := httptest.NewRequest(http.MethodPost, "/v1/streams/"+uuid.NewString()+"/frames", body)
+	req.Header.Set("Content-Type", ct)
+	rec := httptest.NewRecorder()
+
+	IngestFramesHandler(uc)(rec, req)
+
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415, got %d", rec.Code)
+	}
+}
+
+func TestIngestFramesHandler_BadRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	IngestFramesHandler(&stubUsecase{})(rec, httptest.NewRequest(http.MethodPost, "/v1/streams/not-a-uuid/frames", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"stream-server/config"
+	session_pool "stream-server/internal/biz/session"
+	"stream-server/internal/biz/session/store_pool"
+)
+
+// mjpegBoundary — разделитель частей multipart/x-mixed-replace
+const mjpegBoundary = "mjpegframe"
+
+// mjpegFrameWriter — кадры частями multipart/x-mixed-replace (понимают <img src>, VLC, ffmpeg)
+type mjpegFrameWriter struct {
+	w   io.Writer
+	rc  *http.ResponseController
+	hdr []byte // переиспользуемый буфер заголовка части
+}
+
+func newMJPEGFrameWriter(w http.ResponseWriter) *mjpegFrameWriter {
+	return &mjpegFrameWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+func (m *mjpegFrameWriter) WriteFrame(f store_pool.Frame, info session_pool.FrameInfo) error {
+	// защита от медленных клиентов; не все ResponseWriter умеют дедлайны — тогда просто без него
+	_ = m.rc.SetWriteDeadline(time.Now().Add(frameWriteWait))
+
+	mime := f.Mime
+	if mime == "" {
+		mime = "image/jpeg"
+	}
+	m.hdr = fmt.Appendf(m.hdr[:0],
+		"--%s\r\nContent-Type: %s\r\nContent-Length: %d\r\nX-Frame-Seq: %d\r\nX-Frame-Timestamp-Ms: %d\r\n\r\n",
+		mjpegBoundary, mime, len(f.Data), f.Seq, info.TimestampMS)
+	if _, err := m.w.Write(m.hdr); err != nil {
+		return err
+	}
+	if _, err := m.w.Write(f.Data); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(m.w, "\r\n"); err != nil {
+		return err
+	}
+	// кадр должен уйти сразу, а не копиться в буфере
+	return m.rc.Flush()
+}
+
+// MJPEGStreamHandler — GET /v1/streams/{id}/mjpeg: классический MJPEG поверх HTTP
+// Те же параметры и темп, что у ws (?rate=, ?from_seq=/?from_ms=, ?live=1), без команд управления
+func MJPEGStreamHandler(store *store_pool.ChunkStore, cfg *conf.Config) http.HandlerFunc {
+	maxFPS := 0
+	if cfg != nil {
+		maxFPS = cfg.MaxFPS
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		pr := preparePlayback(w, r, store, maxFPS)
+		if pr == nil {
+			return
+		}
+		defer pr.unsubscribe()
+
+		h := w.Header()
+		h.Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBoundary)
+		h.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		h.Set("Pragma", "no-cache")
+		h.Set("X-Accel-Buffering", "no") // nginx не должен буферизовать поток
+		pr.playbackHeaders(h)
+		w.WriteHeader(http.StatusOK)
+
+		// Уход клиента отменит контекст запроса; ошибки записи тоже завершают сессию
+		ctx, cancel := sessionContext(r)
+		defer cancel()
+
+		_ = pr.newSession(ctx, newMJPEGFrameWriter(w), store).Run()
+	}
+}
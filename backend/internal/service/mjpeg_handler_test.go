@@ -0,0 +1,66 @@
+package service
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	session_pool "stream-server/internal/biz/session"
+	"stream-server/internal/biz/session/store_pool"
+)
+
+func TestMJPEGFrameWriterProducesMultipart(t *testing.T) {
+	rec := httptest.NewRecorder()
+	fw := newMJPEGFrameWriter(rec)
+	frames := []store_pool.Frame{
+		{Seq: 7, Data: []byte{0xff, 0xd8, 0x01, 0xff, 0xd9}, Mime: "image/jpeg"},
+		{Seq: 8, Data: []byte{0xff, 0xd8, 0x02, 0xff, 0xd9}}, // без mime — считаем JPEG
+	}
+	for i, f := range frames {
+		if err := fw.WriteFrame(f, session_pool.FrameInfo{TimestampMS: int64(i) * 40}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !rec.Flushed {
+		t.Fatal("frames must be flushed immediately")
+	}
+
+	mr := multipart.NewReader(rec.Body, mjpegBoundary)
+	for i, f := range frames {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		data, _ := io.ReadAll(part)
+		if string(data) != string(f.Data) {
+			t.Fatalf("part %d: got %x want %x", i, data, f.Data)
+		}
+		if part.Header.Get("Content-Type") != "image/jpeg" ||
+			part.Header.Get("Content-Length") != strconv.Itoa(len(f.Data)) ||
+			part.Header.Get("X-Frame-Seq") != strconv.FormatInt(f.Seq, 10) {
+			t.Fatalf("part %d: bad headers %v", i, part.Header)
+		}
+	}
+}
+
+func TestMJPEGStreamHandlerRejects(t *testing.T) {
+	h := MJPEGStreamHandler(nil, nil)
+	for _, tc := range []struct {
+		method, target string
+		want           int
+	}{
+		{http.MethodPost, "/v1/streams/00000000-0000-0000-0000-000000000000/mjpeg", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v1/streams/not-a-uuid/mjpeg", http.StatusBadRequest},
+		{http.MethodGet, "/v1/streams/00000000-0000-0000-0000-000000000000/mjpeg?rate=x", http.StatusBadRequest},
+		{http.MethodGet, "/v1/streams/00000000-0000-0000-0000-000000000000/mjpeg?from_seq=-3", http.StatusBadRequest},
+	} {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(tc.method, tc.target, nil))
+		if rec.Code != tc.want {
+			t.Fatalf("%s %s: got %d want %d", tc.method, tc.target, rec.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz"
+	"stream-server/internal/biz/session/store_pool"
+	"stream-server/internal/interfaces"
+)
+
+const (
+	// frameCacheControl — кадр по sequence меняют admin-правки (delete-frames, renumber): кэш только с проверкой ETag
+	frameCacheControl = "no-cache"
+	// snapshotCacheControl — "последний" кадр меняется с дозаписью: кэшировать можно, но с проверкой ETag
+	snapshotCacheControl = "no-cache"
+	// posterCacheControl — постер пересобирается при правке кадров: тоже с проверкой ETag
+	posterCacheControl = "no-cache"
+)
+
+// FrameHandler — GET /v1/streams/{id}/frames/{seq}: один кадр (?w= — превью)
+func FrameHandler(store *store_pool.ChunkStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		streamID, width, ok := parseFrameRequest(w, r)
+		if !ok {
+			return
+		}
+		seq, err := extractSeq(r)
+		if err != nil {
+			writeError(w, r, v1.ErrorInvalidArgument("bad frame sequence"))
+			return
+		}
+
+		// 304 отдаёт serveFrame, когда кадр найден: ETag с версией стрима, удалённый кадр — 404
+		meta, err := store.LoadStreamMeta(r.Context(), streamID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if seq > meta.MaxSeq {
+			writeError(w, r, store_pool.ErrFrameNotFound)
+			return
+		}
+		serveFrame(w, r, store, meta, seq, width, frameCacheControl)
+	}
+}
+
+// SnapshotHandler — GET /v1/streams/{id}/snapshot?at=latest|first: последний (по умолчанию) или первый кадр
+func SnapshotHandler(store *store_pool.ChunkStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		streamID, width, ok := parseFrameRequest(w, r)
+		if !ok {
+			return
+		}
+		meta, err := store.LoadStreamMeta(r.Context(), streamID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if meta.Count == 0 || meta.MaxSeq < meta.MinSeq {
+			writeError(w, r, v1.ErrorFrameNotFound("stream has no frames"))
+			return
+		}
+
+		var seq int64
+		switch r.URL.Query().Get("at") {
+		case "", "latest":
+			seq = meta.MaxSeq
+		case "first":
+			seq = meta.MinSeq
+		default:
+			writeError(w, r, v1.ErrorInvalidArgument("at must be latest or first"))
+			return
+		}
+		serveFrame(w, r, store, meta, seq, width, snapshotCacheControl)
+	}
+}
+
+// ThumbnailHandler — GET /v1/streams/{id}/thumbnail: сохранённый постер стрима (см. thumbnail_url в списке)
+func ThumbnailHandler(uc interfaces.IUsecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		streamID, width, ok := parseFrameRequest(w, r)
+		if !ok {
+			return
+		}
+		if width > 0 {
+			writeError(w, r, v1.ErrorInvalidArgument("poster has fixed size"))
+			return
+		}
+
+		poster, version, err := uc.GetStreamThumbnail(r.Context(), streamID.String())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		// NULL — ещё не сделан, пустой — первый кадр не декодировался
+		if len(poster) == 0 {
+			writeError(w, r, v1.ErrorFrameNotFound("stream has no thumbnail"))
+			return
+		}
+
+		h := w.Header()
+		h.Set("Content-Type", http.DetectContentType(poster))
+		h.Set("ETag", fmt.Sprintf(`"%s-v%d-poster"`, streamID, version))
+		h.Set("Cache-Control", posterCacheControl)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(poster))
+	}
+}
+
+// parseFrameRequest — метод, id стрима и ?w=; при ошибке ответ уже записан
+func parseFrameRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, int, bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return uuid.Nil, 0, false
+	}
+	streamID, err := parseStreamID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return uuid.Nil, 0, false
+	}
+
+	width := 0
+	if raw := r.URL.Query().Get("w"); raw != "" {
+		width, err = strconv.Atoi(raw)
+		if err != nil || width < 1 || width > biz.MaxThumbnailWidth {
+			writeError(w, r, biz.ErrBadThumbnailWidth)
+			return uuid.Nil, 0, false
+		}
+	}
+	return streamID, width, true
+}
+
+// serveFrame — кадр через общий кэш чанков; HEAD/Range обрабатывает ServeContent
+// Ревалидация отвечается 304 до чанка и декодирования превью: ETag несёт версию кадров, а дыры в sequence
+// появляются только правкой кадров, поднимающей её, — кадр из диапазона meta с совпавшим ETag существует
+func serveFrame(w http.ResponseWriter, r *http.Request, store *store_pool.ChunkStore, meta store_pool.StreamMeta, seq int64, width int, cacheControl string) {
+	if seq < meta.MinSeq || seq > meta.MaxSeq {
+		writeError(w, r, store_pool.ErrFrameNotFound)
+		return
+	}
+	etag := frameETag(meta, seq, width)
+	h := w.Header()
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		h.Set("ETag", etag)
+		h.Set("Cache-Control", cacheControl)
+		h.Set("X-Frame-Seq", strconv.FormatInt(seq, 10))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	f, chunk, err := store.GetFrame(r.Context(), meta.ID, meta.MinSeq, seq)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	// Data — буфер чанка: держим чанк, пока ответ не записан
+	defer store.ReleaseChunk(chunk)
+
+	data, mime := f.Data, f.Mime
+	if width > 0 {
+		thumb, resized, err := biz.Thumbnail(f.Data, width)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if resized {
+			data, mime = thumb, "image/jpeg"
+		}
+	}
+
+	h.Set("Content-Type", mime)
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", cacheControl)
+	h.Set("X-Frame-Seq", strconv.FormatInt(seq, 10))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+// etagMatches — If-None-Match (список через запятую, "*" или слабые W/) совпадает с etag
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// frameETag — стрим, версия его кадров и sequence (+ ширина превью): правка кадров поднимает версию и сбрасывает ETag
+func frameETag(meta store_pool.StreamMeta, seq int64, width int) string {
+	if width > 0 {
+		return fmt.Sprintf(`"%s-v%d-%d-w%d"`, meta.ID, meta.Version, seq, width)
+	}
+	return fmt.Sprintf(`"%s-v%d-%d"`, meta.ID, meta.Version, seq)
+}
+
+// extractSeq — {seq} из /v1/streams/{id}/frames/{seq}
+func extractSeq(r *http.Request) (int64, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 6 {
+		return 0, fmt.Errorf("bad path: %s", r.URL.Path)
+	}
+	seq, err := strconv.ParseInt(parts[5], 10, 64)
+	if err != nil || seq < 0 {
+		return 0, fmt.Errorf("bad sequence: %q", parts[5])
+	}
+	return seq, nil
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/go-kratos/kratos/v2/log"
 
+	"stream-server/config"
+
 	v1 "stream-server/api/v1"
 	"stream-server/internal/interfaces"
 )
@@ -17,24 +19,27 @@ type StreamService struct {
 	uc    interfaces.IUsecase
 	log   *log.Helper
 	store *store_pool.ChunkStore
+	cfg   *conf.Config
 }
 
-func NewStreamService(uc interfaces.IUsecase, l *log.Helper, store *store_pool.ChunkStore) *StreamService {
+func NewStreamService(uc interfaces.IUsecase, l *log.Helper, store *store_pool.ChunkStore, cfg *conf.Config) *StreamService {
 	return &StreamService{
 		uc:    uc,
 		log:   l,
 		store: store,
+		cfg:   cfg,
 	}
 }
 
 func (s *StreamService) ListStreams(ctx context.Context, in *v1.ListStreamsRequest) (res *v1.ListStreamsResponse, err error) {
-	streams, err := s.uc.ListStreams(ctx, in)
+	streams, nextPageToken, err := s.uc.ListStreams(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 
 	return &v1.ListStreamsResponse{
-		Streams: streams,
+		Streams:       streams,
+		NextPageToken: nextPageToken,
 	}, err
 }
 
@@ -49,6 +54,17 @@ func (s *StreamService) GetStream(ctx context.Context, in *v1.GetStreamRequest)
 	}, err
 }
 
+func (s *StreamService) CreateStream(ctx context.Context, in *v1.CreateStreamRequest) (res *v1.CreateStreamResponse, err error) {
+	stream, err := s.uc.CreateStream(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CreateStreamResponse{
+		Stream: stream,
+	}, err
+}
+
 func (s *StreamService) UpdateStream(ctx context.Context, in *v1.UpdateStreamRequest) (res *v1.UpdateStreamResponse, err error) {
 	stream, err := s.uc.UpdateStream(ctx, in)
 	if err != nil {
@@ -60,6 +76,42 @@ func (s *StreamService) UpdateStream(ctx context.Context, in *v1.UpdateStreamReq
 	}, err
 }
 
+func (s *StreamService) DeleteStream(ctx context.Context, in *v1.DeleteStreamRequest) (res *v1.DeleteStreamResponse, err error) {
+	if err = s.uc.DeleteStream(ctx, in); err != nil {
+		return nil, err
+	}
+
+	return &v1.DeleteStreamResponse{}, nil
+}
+
 func (s *StreamService) StreamWSHandler() http.HandlerFunc {
-	return WSStreamHandler(s.store)
+	return WSStreamHandler(s.store, s.cfg)
+}
+
+func (s *StreamService) MJPEGHandler() http.HandlerFunc {
+	return MJPEGStreamHandler(s.store, s.cfg)
+}
+
+func (s *StreamService) FrameHTTPHandler() http.HandlerFunc {
+	return FrameHandler(s.store)
+}
+
+func (s *StreamService) SnapshotHTTPHandler() http.HandlerFunc {
+	return SnapshotHandler(s.store)
+}
+
+func (s *StreamService) ThumbnailHTTPHandler() http.HandlerFunc {
+	return ThumbnailHandler(s.uc)
+}
+
+func (s *StreamService) ExportHTTPHandler() http.HandlerFunc {
+	return ExportHandler(s.uc)
+}
+
+func (s *StreamService) ImportHTTPHandler() http.HandlerFunc {
+	return ImportHandler(s.uc)
+}
+
+func (s *StreamService) IngestHTTPHandler() http.HandlerFunc {
+	return IngestFramesHandler(s.uc)
 }
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	v1 "stream-server/api/v1"
+	"stream-server/internal/biz"
+	session_pool "stream-server/internal/biz/session"
+	"stream-server/internal/biz/session/store_pool"
+)
+
+// grpcFrameWriter — кадры сессии сообщениями server-stream gRPC
+// Send сериализует сообщение до возврата, поэтому payload можно брать прямо из буфера удерживаемого чанка
+type grpcFrameWriter struct {
+	stream v1.StreamService_StreamFramesServer
+}
+
+func (g *grpcFrameWriter) WriteFrame(f store_pool.Frame, info session_pool.FrameInfo) error {
+	mime := f.Mime
+	if mime == "" {
+		mime = "image/jpeg"
+	}
+	return g.stream.Send(&v1.Frame{
+		Seq:         f.Seq,
+		Mime:        mime,
+		Payload:     f.Data,
+		TimestampMs: info.TimestampMS,
+		Skipped:     info.Skipped,
+	})
+}
+
+// StreamFrames — кадры стрима по gRPC: та же StreamSession, что у ws/mjpeg, поверх общего ChunkStore
+// Отмена вызова клиентом отменяет контекст стрима и завершает сессию
+func (s *StreamService) StreamFrames(in *v1.StreamFramesRequest, stream v1.StreamService_StreamFramesServer) error {
+	// validate-middleware кратоса на server-stream не срабатывает — проверяем сами
+	if err := in.Validate(); err != nil {
+		return v1.ErrorInvalidArgument("%v", err).WithCause(err)
+	}
+	pr, err := s.prepareStreamFrames(stream.Context(), in)
+	if err != nil {
+		return biz.ToApiError(err)
+	}
+	if pr == nil {
+		return nil // кадров нет — пустой поток
+	}
+	defer pr.unsubscribe()
+
+	return runStreamFrames(stream, s.store, pr, in.AsFastAsPossible)
+}
+
+// prepareStreamFrames — разбор запроса и снимок meta (как preparePlayback для http); nil — кадров нет
+func (s *StreamService) prepareStreamFrames(ctx context.Context, in *v1.StreamFramesRequest) (*playbackRequest, error) {
+	streamID, err := uuid.Parse(in.StreamId)
+	if err != nil {
+		return nil, v1.ErrorInvalidArgument("bad stream id %q", in.StreamId)
+	}
+	pr := &playbackRequest{streamID: streamID, unsubscribe: func() {}}
+	if in.FromSeq != nil || in.FromMs != nil {
+		start, err := seekTarget(in.FromSeq, in.FromMs)
+		if err != nil {
+			return nil, v1.ErrorInvalidArgument("%v", err)
+		}
+		pr.start = &start
+	}
+	rate := in.Rate
+	if rate == 0 {
+		rate = 1
+	}
+
+	// live: подписываемся ДО снимка meta, чтобы не потерять кадры, дописанные между ними
+	if in.Live {
+		pr.updates, pr.unsubscribe = s.store.SubscribeAppends(streamID)
+	}
+	pr.meta, err = s.store.LoadStreamMeta(ctx, streamID)
+	if err != nil {
+		pr.unsubscribe()
+		return nil, err
+	}
+	if !in.Live && (pr.meta.Count == 0 || pr.meta.MaxSeq < pr.meta.MinSeq) {
+		pr.unsubscribe()
+		return nil, nil
+	}
+
+	maxFPS := 0
+	if s.cfg != nil {
+		maxFPS = s.cfg.MaxFPS
+	}
+	pr.playback, err = session_pool.NewPlayback(pr.meta.IntervalMS, rate, maxFPS)
+	if err != nil {
+		pr.unsubscribe()
+		return nil, v1.ErrorInvalidArgument("%v", err)
+	}
+	return pr, nil
+}
+
+// runStreamFrames — сессия до конца данных, отмены вызова или ошибки Send
+func runStreamFrames(stream v1.StreamService_StreamFramesServer, store *store_pool.ChunkStore, pr *playbackRequest, unpaced bool) error {
+	session := pr.newSession(stream.Context(), &grpcFrameWriter{stream: stream}, store)
+	if unpaced {
+		session.SetUnpaced()
+	}
+	return session.Run()
+}
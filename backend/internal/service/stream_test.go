@@ -3,21 +3,127 @@ package service
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	v1 "stream-server/api/v1"
 	"stream-server/internal/interfaces"
 
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/uuid"
 )
 
+type ingested struct {
+	payload []byte
+	mime    string
+}
+
 type stubUsecase struct {
-	resp []*v1.Stream
-	err  error
+	resp   []*v1.Stream
+	frames []ingested
+	poster []byte
+	err    error
+
+	posterVersion int64
+
+	exportErr error
+}
+
+func (s *stubUsecase) ListStreams(_ context.Context, _ *v1.ListStreamsRequest) ([]*v1.Stream, string, error) {
+	return s.resp, "", s.err
+}
+
+func (s *stubUsecase) GetStream(_ context.Context, _ *v1.GetStreamRequest) (*v1.Stream, error) {
+	return nil, s.err
+}
+
+func (s *stubUsecase) CreateStream(_ context.Context, in *v1.CreateStreamRequest) (*v1.Stream, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &v1.Stream{Id: "id-new", Title: in.Title}, nil
+}
+
+func (s *stubUsecase) UpdateStream(_ context.Context, _ *v1.UpdateStreamRequest) (*v1.Stream, error) {
+	return nil, s.err
+}
+
+func (s *stubUsecase) DeleteStream(_ context.Context, _ *v1.DeleteStreamRequest) error {
+	return s.err
+}
+
+func (s *stubUsecase) GetStreamThumbnail(_ context.Context, _ string) ([]byte, int64, error) {
+	return s.poster, s.posterVersion, s.err
+}
+
+// ExportFrames — загруженные кадры (sequence = индекс) из диапазона; exportErr — обрыв после кадров
+func (s *stubUsecase) ExportFrames(_ context.Context, _ string, fromSeq, toSeq int64, fn interfaces.FrameFunc) error {
+	if s.err != nil {
+		return s.err
+	}
+	for i, f := range s.frames {
+		if seq := int64(i); seq >= fromSeq && seq <= toSeq {
+			if err := fn(seq, f.payload, f.mime); err != nil {
+				return err
+			}
+		}
+	}
+	return s.exportErr
+}
+
+// ExportFrameRange — сводка по кадрам с mime, затем те же кадры, как ExportFrames
+func (s *stubUsecase) ExportFrameRange(ctx context.Context, id string, fromSeq, toSeq int64, mime string, prepare func(fr *interfaces.FrameRange) error, fn interfaces.FrameFunc) error {
+	if s.err != nil {
+		return s.err
+	}
+	res := &interfaces.FrameRange{IntervalMS: 40, LastSeq: -1}
+	for i, f := range s.frames {
+		if seq := int64(i); seq >= fromSeq && seq <= toSeq && f.mime == mime {
+			res.Frames++
+			res.Bytes += int64(len(f.payload))
+			res.OddFrames += int64(len(f.payload) % 2)
+			res.LastSeq = seq
+		}
+	}
+	if err := prepare(res); err != nil {
+		return err
+	}
+	return s.ExportFrames(ctx, id, fromSeq, res.LastSeq, func(seq int64, payload []byte, frameMime string) error {
+		if frameMime != mime {
+			return nil
+		}
+		return fn(seq, payload, frameMime)
+	})
 }
 
-func (s *stubUsecase) ListStreams(_ context.Context, _ *v1.ListStreamsRequest) ([]*v1.Stream, error) {
-	return s.resp, s.err
+func (s *stubUsecase) IngestFrames(_ context.Context, streamID string, src interfaces.FrameSource) (*v1.IngestFramesResponse, error) {
+	res := &v1.IngestFramesResponse{StreamId: streamID}
+	for {
+		payload, mime, err := src.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.frames = append(s.frames, ingested{payload: payload, mime: mime})
+		res.Count++
+	}
+	return res, s.err
+}
+
+// ImportStream — кадры src в stubUsecase.frames, в ответ стрим с их числом
+func (s *stubUsecase) ImportStream(ctx context.Context, in *v1.CreateStreamRequest, src interfaces.FrameSource) (*v1.Stream, error) {
+	stream, err := s.CreateStream(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.IngestFrames(ctx, stream.Id, src)
+	if err != nil {
+		return nil, err
+	}
+	stream.Description, stream.FrameIntervalMs, stream.FrameCount = in.Description, in.FrameIntervalMs, res.Count
+	return stream, nil
 }
 
 func TestStreamService_ListStreams_Success(t *testing.T) {
@@ -47,6 +153,14 @@ func TestStreamService_ListStreams_Error(t *testing.T) {
 	}
 }
 
+func TestStreamService_DeleteStream_Error(t *testing.T) {
+	wantErr := errors.New("boom")
+	svc := &StreamService{uc: &stubUsecase{err: wantErr}, log: log.NewHelper(log.NewStdLogger(nil))}
+	if _, err := svc.DeleteStream(context.Background(), &v1.DeleteStreamRequest{Id: uuid.NewString()}); !errors.Is(err, wantErr) {
+		t.Fatalf("expected Is(wantErr), got: %v", err)
+	}
+}
+
 // Ensure handler is returned (not testing WS logic here)
 func TestStreamService_StreamWSHandler_NotNil(t *testing.T) {
 	uc := &stubUsecase{}
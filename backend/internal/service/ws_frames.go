@@ -0,0 +1,73 @@
+package service
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	session_pool "stream-server/internal/biz/session"
+	"stream-server/internal/biz/session/store_pool"
+	"stream-server/pkg/wsproto"
+)
+
+// frameWriteWait — дедлайн на запись кадра (защита от медленных клиентов)
+const frameWriteWait = 2 * time.Second
+
+// wsFrameWriter — отправка кадров в ws в режиме, согласованном подпротоколом (см. pkg/wsproto)
+type wsFrameWriter struct {
+	conn        *websocket.Conn
+	subprotocol string
+	hdr         []byte // переиспользуемый буфер заголовка
+}
+
+func newWSFrameWriter(conn *websocket.Conn) *wsFrameWriter {
+	return &wsFrameWriter{conn: conn, subprotocol: conn.Subprotocol()}
+}
+
+func (w *wsFrameWriter) WriteFrame(f store_pool.Frame, info session_pool.FrameInfo) error {
+	_ = w.conn.SetWriteDeadline(time.Now().Add(frameWriteWait))
+
+	switch w.subprotocol {
+	case wsproto.SubprotocolFramed:
+		// заголовок и кадр — одно бинарное сообщение, без склейки в отдельный буфер
+		w.hdr = wsproto.AppendFrameHeader(w.hdr[:0], frameHeader(f, info))
+		mw, err := w.conn.NextWriter(websocket.BinaryMessage)
+		if err != nil {
+			return err
+		}
+		if _, err = mw.Write(w.hdr); err != nil {
+			return err
+		}
+		if _, err = mw.Write(f.Data); err != nil {
+			return err
+		}
+		return mw.Close()
+
+	case wsproto.SubprotocolMeta:
+		h := frameHeader(f, info)
+		meta := wsproto.FrameMeta{
+			V:           wsproto.Version,
+			Type:        wsproto.TypeFrame,
+			Sequence:    h.Seq,
+			TimestampMS: h.TimestampMS,
+			Skipped:     h.Skipped,
+			MimeType:    h.Mime,
+		}
+		if err := w.conn.WriteJSON(meta); err != nil {
+			return err
+		}
+		return w.conn.WriteMessage(websocket.BinaryMessage, f.Data)
+
+	default:
+		return w.conn.WriteMessage(websocket.BinaryMessage, f.Data)
+	}
+}
+
+func frameHeader(f store_pool.Frame, info session_pool.FrameInfo) wsproto.FrameHeader {
+	return wsproto.FrameHeader{
+		Seq:         f.Seq,
+		TimestampMS: info.TimestampMS,
+		Skipped:     wsproto.ClampSkipped(info.Skipped),
+		Mime:        f.Mime,
+	}
+}
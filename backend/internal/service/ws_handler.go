@@ -1,88 +1,356 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	v1 "stream-server/api/v1"
+	"stream-server/config"
 	session_pool "stream-server/internal/biz/session"
 	"stream-server/internal/biz/session/store_pool"
+	"stream-server/pkg/wsproto"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
+const (
+	pongWait   = 60 * time.Second  // read-deadline, продлевается каждым Pong
+	pingPeriod = pongWait * 9 / 10 // пингуем чаще, чем истекает read-deadline
+	writeWait  = 1 * time.Second   // дедлайн на control-фреймы
+)
+
 // В продакшене можно ограничить CheckOrigin по доменам фронта/хедеру
+// Подпротокол (метаданные кадров) — по желанию клиента; без него кадры идут "сырыми"
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
+	Subprotocols: []string{wsproto.SubprotocolFramed, wsproto.SubprotocolMeta},
 }
 
 // readerPump — читает входящие кадры, чтобы обрабатывать ping/pong/close и поддерживать read-deadline
-// Мы ничего не ждём от клиента, но без этого gorilla НЕ вызовет PongHandler
-func readerPump(conn *websocket.Conn, done chan struct{}) {
+// Текстовые сообщения — команды управления (pkg/wsproto), отдаём в onText; без чтения gorilla НЕ вызовет PongHandler
+func readerPump(conn *websocket.Conn, done chan struct{}, onText func([]byte)) {
 	defer close(done)
-	// Защита от злоупотребления: максимум 64К на входящее сообщение (нам ничего не шлют)
+	// Защита от злоупотребления: максимум 64К на входящее сообщение (шлют только короткие JSON-команды)
 	conn.SetReadLimit(64 << 10)
-	_ = conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
 	conn.SetPongHandler(func(string) error {
 		// продлеваем read-deadline при каждом Pong
-		return conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
 	})
 	for {
-		if _, _, err := conn.ReadMessage(); err != nil {
+		typ, data, err := conn.ReadMessage()
+		if err != nil {
 			return // клиент отвалился/закрылся
 		}
+		if typ == websocket.TextMessage && onText != nil {
+			onText(data)
+		}
 	}
 }
 
-func WSStreamHandler(store *store_pool.ChunkStore) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Валидация id
-		idStr, err := extractID(r)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+// pingPump — периодический Ping, чтобы клиент отвечал Pong и read-deadline продлевался
+// Нужен live-сессиям: пока ждём дозаписи, кадры не идут, а соединение должно жить
+// WriteControl в gorilla можно вызывать параллельно с WriteMessage
+func pingPump(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
 			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
 		}
-		streamID, err := uuid.Parse(idStr)
+	}
+}
+
+// announcesPlayback — шлём ли текстовое "playback" после апгрейда: только при согласованном подпротоколе
+func announcesPlayback(subprotocol string) bool {
+	return subprotocol == wsproto.SubprotocolFramed || subprotocol == wsproto.SubprotocolMeta
+}
+
+func newPlaybackMessage(p session_pool.Playback) wsproto.Playback {
+	return wsproto.Playback{
+		V:                wsproto.Version,
+		Type:             wsproto.TypePlayback,
+		StreamIntervalMS: durationMS(p.StreamInterval),
+		IntervalMS:       durationMS(p.Interval),
+		Rate:             p.Rate,
+		FPS:              p.FPS(),
+	}
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// seekTarget — точка перехода из seq/ms (ровно одно из двух, неотрицательное)
+func seekTarget(seq, ms *int64) (session_pool.SeekTarget, error) {
+	switch {
+	case seq != nil && ms != nil:
+		return session_pool.SeekTarget{}, errors.New("seq and ms are mutually exclusive")
+	case seq != nil && *seq >= 0:
+		return session_pool.SeekTarget{Seq: *seq}, nil
+	case ms != nil && *ms >= 0:
+		return session_pool.SeekTarget{Ms: *ms, ByMs: true}, nil
+	default:
+		return session_pool.SeekTarget{}, errors.New("seek needs a non-negative seq or ms")
+	}
+}
+
+// parseCommand — JSON-команда клиента (см. pkg/wsproto) в команду сессии
+func parseCommand(cmd wsproto.Command) (session_pool.Command, error) {
+	if cmd.V != 0 && cmd.V != wsproto.Version {
+		return session_pool.Command{}, fmt.Errorf("unsupported protocol version %d", cmd.V)
+	}
+	switch cmd.Type {
+	case wsproto.TypePause:
+		return session_pool.Command{Kind: session_pool.CmdPause}, nil
+	case wsproto.TypeResume:
+		return session_pool.Command{Kind: session_pool.CmdResume}, nil
+	case wsproto.TypeSeek:
+		t, err := seekTarget(cmd.Seq, cmd.Ms)
 		if err != nil {
-			http.Error(w, "bad stream id", http.StatusBadRequest)
-			return
+			return session_pool.Command{}, err
+		}
+		return session_pool.Command{Kind: session_pool.CmdSeek, Seek: t}, nil
+	case wsproto.TypeSetRate:
+		if cmd.Rate == nil {
+			return session_pool.Command{}, errors.New("set_rate needs rate")
 		}
+		return session_pool.Command{Kind: session_pool.CmdSetRate, Rate: *cmd.Rate}, nil
+	case wsproto.TypeStop:
+		return session_pool.Command{Kind: session_pool.CmdStop}, nil
+	default:
+		return session_pool.Command{}, fmt.Errorf("unknown command %q", cmd.Type)
+	}
+}
+
+// newAck — ответ на команду по состоянию сессии после её применения
+func newAck(cmd wsproto.Command, st session_pool.State, err error) wsproto.Ack {
+	ack := wsproto.Ack{
+		V:    wsproto.Version,
+		Type: wsproto.TypeAck,
+		ID:   cmd.ID,
+		Cmd:  cmd.Type,
+		OK:   err == nil,
+		State: &wsproto.State{
+			Paused:     st.Paused,
+			Seq:        st.Seq,
+			Rate:       st.Playback.Rate,
+			IntervalMS: durationMS(st.Playback.Interval),
+			FPS:        st.Playback.FPS(),
+		},
+	}
+	if err != nil {
+		ack.Error = err.Error()
+	}
+	return ack
+}
+
+// submitCommand — разобрать текстовое сообщение и поставить команду в очередь сессии
+// Ответ (в том числе отказ разбора) пишет горутина Run: так ack не пересекается с записью кадров
+// и приходит строго после применения команды
+func submitCommand(session *session_pool.StreamSession, conn *websocket.Conn, data []byte) {
+	var cmd wsproto.Command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		cmd = wsproto.Command{}
+	}
+	sc, err := parseCommand(cmd)
+	if err != nil {
+		sc = session_pool.Command{Err: err}
+	}
+	sc.Done = func(st session_pool.State, err error) {
+		_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+		_ = conn.WriteJSON(newAck(cmd, st, err))
+	}
+	_ = session.Submit(sc)
+}
 
-		// Метаданные (min/max/count/interval) — фиксируем "снимок" стрима на момент запроса
-		ctx := r.Context()
-		meta, err := store.LoadStreamMeta(ctx, streamID)
+// parseStart — ?from_seq=120 или ?from_ms=5000: откуда начать воспроизведение (nil — с начала)
+func parseStart(r *http.Request) (*session_pool.SeekTarget, error) {
+	q := r.URL.Query()
+	var seq, ms *int64
+	for key, dst := range map[string]**int64{"from_seq": &seq, "from_ms": &ms} {
+		raw := q.Get(key)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
 		if err != nil {
-			http.Error(w, "stream not found", http.StatusNotFound)
-			return
+			return nil, fmt.Errorf("bad %s", key)
 		}
-		if meta.Count == 0 || meta.MaxSeq < meta.MinSeq {
-			// 204 если кадров нет — до апгрейда.
-			w.WriteHeader(http.StatusNoContent)
+		*dst = &v
+	}
+	if seq == nil && ms == nil {
+		return nil, nil
+	}
+	t, err := seekTarget(seq, ms)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseRate — ?rate=2 (множитель скорости); по умолчанию 1x
+func parseRate(r *http.Request) (float64, error) {
+	raw := r.URL.Query().Get("rate")
+	if raw == "" {
+		return 1, nil
+	}
+	return strconv.ParseFloat(raw, 64)
+}
+
+// isLive — ?live=1: сессия не завершается по концу снимка, а ждёт новые кадры
+func isLive(r *http.Request) bool {
+	live, _ := strconv.ParseBool(r.URL.Query().Get("live"))
+	return live
+}
+
+// playbackRequest — разобранный запрос воспроизведения, общий для ws и mjpeg
+type playbackRequest struct {
+	streamID    uuid.UUID
+	meta        store_pool.StreamMeta
+	playback    session_pool.Playback
+	start       *session_pool.SeekTarget
+	updates     <-chan int64 // live: дозапись кадров; nil — VOD по снимку
+	unsubscribe func()
+}
+
+// preparePlayback — валидация id/rate/from_*/live и снимок meta; при ошибке ответ уже записан (nil)
+// Вызывающий обязан вызвать unsubscribe
+func preparePlayback(w http.ResponseWriter, r *http.Request, store *store_pool.ChunkStore, maxFPS int) *playbackRequest {
+	// Валидация id
+	streamID, err := parseStreamID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return nil
+	}
+	rate, err := parseRate(r)
+	if err != nil {
+		writeError(w, r, v1.ErrorInvalidArgument("bad rate"))
+		return nil
+	}
+	start, err := parseStart(r)
+	if err != nil {
+		writeError(w, r, v1.ErrorInvalidArgument("%v", err))
+		return nil
+	}
+
+	// live: подписываемся ДО снимка meta, чтобы не потерять кадры, дописанные между ними
+	pr := &playbackRequest{streamID: streamID, start: start, unsubscribe: func() {}}
+	live := isLive(r)
+	if live {
+		pr.updates, pr.unsubscribe = store.SubscribeAppends(streamID)
+	}
+
+	// Метаданные (min/max/count/interval) — фиксируем "снимок" стрима на момент запроса
+	pr.meta, err = store.LoadStreamMeta(r.Context(), streamID)
+	if err != nil {
+		pr.unsubscribe()
+		writeError(w, r, err)
+		return nil
+	}
+	if !live && (pr.meta.Count == 0 || pr.meta.MaxSeq < pr.meta.MinSeq) {
+		// 204 если кадров нет — до начала ответа. В live-режиме ждём первые кадры
+		pr.unsubscribe()
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	// Скорость: интервал стрима / rate, но не чаще MaxFPS
+	pr.playback, err = session_pool.NewPlayback(pr.meta.IntervalMS, rate, maxFPS)
+	if err != nil {
+		pr.unsubscribe()
+		writeError(w, r, v1.ErrorInvalidArgument("%v", err))
+		return nil
+	}
+	return pr
+}
+
+// playbackHeaders — фактическая скорость в заголовках ответа
+func (pr *playbackRequest) playbackHeaders(h http.Header) {
+	h.Set("X-Playback-Rate", strconv.FormatFloat(pr.playback.Rate, 'f', -1, 64))
+	h.Set("X-Playback-Interval-Ms", strconv.FormatFloat(durationMS(pr.playback.Interval), 'f', -1, 64))
+}
+
+// newSession — сессия с параметрами запроса: скорость, live-режим, стартовая позиция
+func (pr *playbackRequest) newSession(ctx context.Context, out session_pool.FrameWriter, store *store_pool.ChunkStore) *session_pool.StreamSession {
+	session := session_pool.NewStreamSession(ctx, out, store, pr.meta, pr.streamID)
+	session.SetPlayback(pr.playback)
+	if pr.updates != nil {
+		session.FollowLive(pr.updates)
+	}
+	if pr.start != nil {
+		_ = session.Submit(session_pool.Command{Kind: session_pool.CmdSeek, Seek: *pr.start})
+	}
+	return session
+}
+
+func WSStreamHandler(store *store_pool.ChunkStore, cfg *conf.Config) http.HandlerFunc {
+	maxFPS := 0
+	if cfg != nil {
+		maxFPS = cfg.MaxFPS
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		pr := preparePlayback(w, r, store, maxFPS)
+		if pr == nil {
 			return
 		}
+		defer pr.unsubscribe()
 
-		// Апгрейд до WS
-		conn, err := upgrader.Upgrade(w, r, nil)
+		// Апгрейд до WS (фактическую скорость дублируем в заголовках ответа)
+		respHeader := http.Header{}
+		pr.playbackHeaders(respHeader)
+		conn, err := upgrader.Upgrade(w, r, respHeader)
 		if err != nil {
 			return
 		}
 		defer conn.Close()
 		conn.EnableWriteCompression(false) // JPEG уже сжат, компрессия лишь нагружает CPU
 
-		// Запускаем reader и ждём его завершения через канал
+		// Сообщаем клиенту фактическую скорость (из браузера заголовки апгрейда не прочитать — встроенный UI
+		// поэтому подключается с mjpeg.meta.v1). Только клиентам с подпротоколом: "сырые" считают кадром каждое сообщение
+		if announcesPlayback(conn.Subprotocol()) {
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err = conn.WriteJSON(newPlaybackMessage(pr.playback)); err != nil {
+				return
+			}
+		}
+
+		// После апгрейда r.Context() не отменяется при уходе клиента — отменяем сессию сами по выходу reader'а
+		ctx, cancel := sessionContext(r)
+		defer cancel()
+
+		session := pr.newSession(ctx, newWSFrameWriter(conn), store)
+
+		// Запускаем reader (он же принимает команды управления) и ждём его завершения через канал
 		readerDone := make(chan struct{})
-		go readerPump(conn, readerDone)
+		go readerPump(conn, readerDone, func(data []byte) { submitCommand(session, conn, data) })
+		go pingPump(conn, readerDone)
+		go func() {
+			select {
+			case <-readerDone:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
 
-		// Запуск сессии
-		session := session_pool.NewStreamSession(ctx, conn, store, meta, streamID)
 		runErr := session.Run()
 
 		if runErr == nil {
-			// Нормально закрываем поток (конец данных)
+			// Нормально закрываем поток (конец данных или stop от клиента)
 			_ = conn.WriteControl(websocket.CloseMessage,
 				websocket.FormatCloseMessage(websocket.CloseNormalClosure, "end of stream"),
 				time.Now().Add(1*time.Second))
@@ -102,6 +370,22 @@ func WSStreamHandler(store *store_pool.ChunkStore) http.HandlerFunc {
 	}
 }
 
+// sessionContext — контекст долгой сессии воспроизведения
+// kratos ограничивает каждый запрос HTTP_TIMEOUT, а сессия длится сколько угодно: дедлайн отбрасываем,
+// а отмену запроса (клиент закрыл соединение) пробрасываем
+func sessionContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	stop := context.AfterFunc(r.Context(), func() {
+		if !errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+			cancel()
+		}
+	})
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
 func extractID(r *http.Request) (string, error) {
 	parts := strings.Split(r.URL.Path, "/")
 	if len(parts) < 5 {
@@ -109,3 +393,16 @@ func extractID(r *http.Request) (string, error) {
 	}
 	return parts[3], nil
 }
+
+// parseStreamID — {id} из пути как UUID; ошибка уже размечена INVALID_ARGUMENT
+func parseStreamID(r *http.Request) (uuid.UUID, error) {
+	idStr, err := extractID(r)
+	if err != nil {
+		return uuid.Nil, v1.ErrorInvalidArgument("%v", err)
+	}
+	streamID, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, v1.ErrorInvalidArgument("bad stream id %q", idStr)
+	}
+	return streamID, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	session_pool "stream-server/internal/biz/session"
+	"stream-server/pkg/wsproto"
+)
+
+func TestParseStart(t *testing.T) {
+	for _, tc := range []struct {
+		query   string
+		want    *session_pool.SeekTarget
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"from_seq=12", &session_pool.SeekTarget{Seq: 12}, false},
+		{"from_ms=1500", &session_pool.SeekTarget{Ms: 1500, ByMs: true}, false},
+		{"from_seq=1&from_ms=2", nil, true},
+		{"from_seq=-1", nil, true},
+		{"from_ms=abc", nil, true},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/v1/streams/x/ws?"+tc.query, nil)
+		got, err := parseStart(r)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%q: err=%v, wantErr=%v", tc.query, err, tc.wantErr)
+		}
+		if (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+			t.Fatalf("%q: got %+v, want %+v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	seq, ms, rate := int64(5), int64(200), 1.5
+	for _, tc := range []struct {
+		cmd     wsproto.Command
+		want    session_pool.Command
+		wantErr bool
+	}{
+		{wsproto.Command{Type: wsproto.TypePause}, session_pool.Command{Kind: session_pool.CmdPause}, false},
+		{wsproto.Command{V: 1, Type: wsproto.TypeResume}, session_pool.Command{Kind: session_pool.CmdResume}, false},
+		{wsproto.Command{Type: wsproto.TypeSeek, Seq: &seq}, session_pool.Command{Kind: session_pool.CmdSeek, Seek: session_pool.SeekTarget{Seq: 5}}, false},
+		{wsproto.Command{Type: wsproto.TypeSeek, Ms: &ms}, session_pool.Command{Kind: session_pool.CmdSeek, Seek: session_pool.SeekTarget{Ms: 200, ByMs: true}}, false},
+		{wsproto.Command{Type: wsproto.TypeSetRate, Rate: &rate}, session_pool.Command{Kind: session_pool.CmdSetRate, Rate: 1.5}, false},
+		{wsproto.Command{Type: wsproto.TypeStop}, session_pool.Command{Kind: session_pool.CmdStop}, false},
+		{wsproto.Command{Type: wsproto.TypeSeek}, session_pool.Command{}, true},
+		{wsproto.Command{Type: wsproto.TypeSetRate}, session_pool.Command{}, true},
+		{wsproto.Command{V: 2, Type: wsproto.TypePause}, session_pool.Command{}, true},
+		{wsproto.Command{Type: "rewind"}, session_pool.Command{}, true},
+	} {
+		got, err := parseCommand(tc.cmd)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%+v: err=%v, wantErr=%v", tc.cmd, err, tc.wantErr)
+		}
+		if got.Kind != tc.want.Kind || got.Seek != tc.want.Seek || got.Rate != tc.want.Rate {
+			t.Fatalf("%+v: got %+v, want %+v", tc.cmd, got, tc.want)
+		}
+	}
+}
+
+func TestSessionContextIgnoresRequestDeadline(t *testing.T) {
+	// дедлайн запроса (HTTP_TIMEOUT) сессию не прерывает
+	reqCtx, cancelReq := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelReq()
+	ctx, cancel := sessionContext(httptest.NewRequest(http.MethodGet, "/", nil).WithContext(reqCtx))
+	defer cancel()
+	<-reqCtx.Done()
+	select {
+	case <-ctx.Done():
+		t.Fatal("session context must outlive the request deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// отмена запроса (клиент ушёл) — прерывает
+	reqCtx, cancelReq = context.WithCancel(context.Background())
+	ctx, cancel = sessionContext(httptest.NewRequest(http.MethodGet, "/", nil).WithContext(reqCtx))
+	defer cancel()
+	cancelReq()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("session context must follow request cancellation")
+	}
+}
+
+func TestAnnouncesPlaybackOnlyWithSubprotocol(t *testing.T) {
+	for sp, want := range map[string]bool{
+		"":                        false, // сырой клиент: каждое сообщение — кадр
+		wsproto.SubprotocolFramed: true,
+		wsproto.SubprotocolMeta:   true,
+	} {
+		if got := announcesPlayback(sp); got != want {
+			t.Fatalf("announcesPlayback(%q) = %v, want %v", sp, got, want)
+		}
+	}
+}
@@ -20,9 +20,14 @@ func NewStreamRepoWrapper(repo interfaces.IRepo) *StreamRepoWrapper {
 	return &StreamRepoWrapper{repo: repo}
 }
 
-func (s *StreamRepoWrapper) ListStreams(ctx context.Context) (_ []repo.ListStreamsRow, err error) {
+func (s *StreamRepoWrapper) ListStreams(ctx context.Context, in repo.ListStreamsParams, oldestFirst bool) (_ []repo.ListStreamsRow, err error) {
 	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "ListStreams")
 	defer func() {
+		span.SetAttributes(
+			attribute.Int("page_limit", int(in.PageLimit)),
+			attribute.Bool("oldest_first", oldestFirst),
+		)
+
 		if err != nil {
 			span.RecordError(err)
 			span.SetAttributes(
@@ -32,7 +37,7 @@ func (s *StreamRepoWrapper) ListStreams(ctx context.Context) (_ []repo.ListStrea
 		}
 		span.End()
 	}()
-	return s.repo.ListStreams(ctx)
+	return s.repo.ListStreams(ctx, in, oldestFirst)
 }
 
 func (s *StreamRepoWrapper) GetStream(ctx context.Context, ID pgtype.UUID) (res repo.GetStreamRow, err error) {
@@ -64,3 +69,218 @@ func (s *StreamRepoWrapper) UpdateStream(ctx context.Context, in repo.UpdateStre
 	}()
 	return s.repo.UpdateStream(ctx, in)
 }
+
+func (s *StreamRepoWrapper) CreateStream(ctx context.Context, in repo.CreateStreamParams) (res repo.Stream, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "CreateStream")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.CreateStream(ctx, in)
+}
+
+func (s *StreamRepoWrapper) DeleteStream(ctx context.Context, ID pgtype.UUID) (err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "DeleteStream")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.DeleteStream(ctx, ID)
+}
+
+func (s *StreamRepoWrapper) AppendFrames(ctx context.Context, streamID pgtype.UUID, frames []repo.InsertFramesParams) (firstSeq int32, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "AppendFrames")
+	span.SetAttributes(attribute.Int("frames", len(frames)))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.AppendFrames(ctx, streamID, frames)
+}
+
+func (s *StreamRepoWrapper) ImportStream(ctx context.Context, in repo.CreateStreamParams, next func() ([]repo.InsertFramesParams, error)) (res repo.Stream, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "ImportStream")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.ImportStream(ctx, in, next)
+}
+
+func (s *StreamRepoWrapper) GetStreamThumbnail(ctx context.Context, ID pgtype.UUID) (_ repo.GetStreamThumbnailRow, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "GetStreamThumbnail")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.GetStreamThumbnail(ctx, ID)
+}
+
+func (s *StreamRepoWrapper) SetStreamThumbnail(ctx context.Context, ID pgtype.UUID, thumbnail []byte) (err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "SetStreamThumbnail")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.SetStreamThumbnail(ctx, ID, thumbnail)
+}
+
+func (s *StreamRepoWrapper) ListStreamsWithoutThumbnail(ctx context.Context, limit int32) (_ []repo.ListStreamsWithoutThumbnailRow, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "ListStreamsWithoutThumbnail")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.ListStreamsWithoutThumbnail(ctx, limit)
+}
+
+func (s *StreamRepoWrapper) ScanFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64, fn interfaces.FrameFunc) (err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "ScanFrames")
+	defer func() {
+		span.SetAttributes(
+			attribute.Int64("from_seq", fromSeq),
+			attribute.Int64("to_seq", toSeq),
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.ScanFrames(ctx, streamID, fromSeq, toSeq, fn)
+}
+
+func (s *StreamRepoWrapper) GetFrameRangeStats(ctx context.Context, in repo.GetFrameRangeStatsParams) (_ repo.GetFrameRangeStatsRow, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "GetFrameRangeStats")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.GetFrameRangeStats(ctx, in)
+}
+
+func (s *StreamRepoWrapper) GetFrameStats(ctx context.Context, streamID pgtype.UUID) (_ repo.GetFrameStatsRow, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "GetFrameStats")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.GetFrameStats(ctx, streamID)
+}
+
+func (s *StreamRepoWrapper) ListFrameGaps(ctx context.Context, streamID pgtype.UUID, limit int32) (_ []repo.ListFrameGapsRow, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "ListFrameGaps")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.ListFrameGaps(ctx, streamID, limit)
+}
+
+func (s *StreamRepoWrapper) DeleteFrames(ctx context.Context, streamID pgtype.UUID, fromSeq, toSeq int64) (_ int64, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "DeleteFrames")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.DeleteFrames(ctx, streamID, fromSeq, toSeq)
+}
+
+func (s *StreamRepoWrapper) RenumberFrames(ctx context.Context, streamID pgtype.UUID) (_ int64, err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "RenumberFrames")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.RenumberFrames(ctx, streamID)
+}
+
+func (s *StreamRepoWrapper) ReadSnapshot(ctx context.Context, fn func(snap interfaces.IFrameReader) error) (err error) {
+	ctx, span := otel.Tracer(RepoInstance).Start(ctx, "ReadSnapshot")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.repo.ReadSnapshot(ctx, fn)
+}
@@ -8,6 +8,8 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type StreamServiceWrapper struct {
@@ -21,9 +23,101 @@ func NewStreamServiceWrapper(base interfaces.IStreamService) *StreamServiceWrapp
 	return &StreamServiceWrapper{service: base}
 }
 
+// StreamWSHandler — спан на всю WS-сессию (ручка вне middleware kratos, поэтому трейс начинаем сами)
+// Продолжает трейс клиента из traceparent; stream_id, число кадров и причину закрытия дописывает сама сессия
 func (s *StreamServiceWrapper) StreamWSHandler() http.HandlerFunc {
-	// TODO: подумоть
-	return s.service.StreamWSHandler()
+	next := s.service.StreamWSHandler()
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(StreamServiceInstance).Start(ctx, "StreamService.StreamWS",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.target", r.URL.Path)),
+		)
+		defer span.End()
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func (s *StreamServiceWrapper) MJPEGHandler() http.HandlerFunc {
+	return s.service.MJPEGHandler()
+}
+
+func (s *StreamServiceWrapper) FrameHTTPHandler() http.HandlerFunc {
+	return s.service.FrameHTTPHandler()
+}
+
+func (s *StreamServiceWrapper) SnapshotHTTPHandler() http.HandlerFunc {
+	return s.service.SnapshotHTTPHandler()
+}
+
+func (s *StreamServiceWrapper) ThumbnailHTTPHandler() http.HandlerFunc {
+	return s.service.ThumbnailHTTPHandler()
+}
+
+func (s *StreamServiceWrapper) ExportHTTPHandler() http.HandlerFunc {
+	return s.service.ExportHTTPHandler()
+}
+
+func (s *StreamServiceWrapper) ImportHTTPHandler() http.HandlerFunc {
+	return s.service.ImportHTTPHandler()
+}
+
+func (s *StreamServiceWrapper) IngestHTTPHandler() http.HandlerFunc {
+	return s.service.IngestHTTPHandler()
+}
+
+// ingestServerStream подменяет контекст client-stream, чтобы спаны ниже были дочерними
+type ingestServerStream struct {
+	v1.StreamService_IngestFramesServer
+	ctx context.Context
+}
+
+func (s *ingestServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *StreamServiceWrapper) IngestFrames(stream v1.StreamService_IngestFramesServer) (err error) {
+	ctx, span := otel.Tracer(StreamServiceInstance).Start(stream.Context(), "StreamService.IngestFrames")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.service.IngestFrames(&ingestServerStream{StreamService_IngestFramesServer: stream, ctx: ctx})
+}
+
+// framesServerStream подменяет контекст server-stream, чтобы спаны ниже были дочерними
+type framesServerStream struct {
+	v1.StreamService_StreamFramesServer
+	ctx context.Context
+}
+
+func (s *framesServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *StreamServiceWrapper) StreamFrames(in *v1.StreamFramesRequest, stream v1.StreamService_StreamFramesServer) (err error) {
+	ctx, span := otel.Tracer(StreamServiceInstance).Start(stream.Context(), "StreamService.StreamFrames")
+	defer func() {
+		span.SetAttributes(
+			attribute.Stringer("in", in),
+		)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.service.StreamFrames(in, &framesServerStream{StreamService_StreamFramesServer: stream, ctx: ctx})
 }
 
 func (s *StreamServiceWrapper) ListStreams(ctx context.Context, in *v1.ListStreamsRequest) (res *v1.ListStreamsResponse, err error) {
@@ -85,3 +179,43 @@ func (s *StreamServiceWrapper) UpdateStream(ctx context.Context, in *v1.UpdateSt
 	}()
 	return s.service.UpdateStream(ctx, in)
 }
+
+func (s *StreamServiceWrapper) CreateStream(ctx context.Context, in *v1.CreateStreamRequest) (res *v1.CreateStreamResponse, err error) {
+	ctx, span := otel.Tracer(StreamServiceInstance).Start(ctx, "StreamService.CreateStream")
+	defer func() {
+		span.SetAttributes(
+			attribute.Stringer("in", in),
+			attribute.Stringer("res", res),
+		)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.service.CreateStream(ctx, in)
+}
+
+func (s *StreamServiceWrapper) DeleteStream(ctx context.Context, in *v1.DeleteStreamRequest) (res *v1.DeleteStreamResponse, err error) {
+	ctx, span := otel.Tracer(StreamServiceInstance).Start(ctx, "StreamService.DeleteStream")
+	defer func() {
+		span.SetAttributes(
+			attribute.Stringer("in", in),
+			attribute.Stringer("res", res),
+		)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.service.DeleteStream(ctx, in)
+}
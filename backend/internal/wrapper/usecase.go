@@ -19,7 +19,7 @@ func NewStreamUsecaseWrapper(base interfaces.IUsecase) *StreamUsecaseWrapper {
 	return &StreamUsecaseWrapper{uc: base}
 }
 
-func (s *StreamUsecaseWrapper) ListStreams(ctx context.Context, in *v1.ListStreamsRequest) (_ []*v1.Stream, err error) {
+func (s *StreamUsecaseWrapper) ListStreams(ctx context.Context, in *v1.ListStreamsRequest) (_ []*v1.Stream, _ string, err error) {
 	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "ListStreams")
 	defer func() {
 		if err != nil {
@@ -63,3 +63,117 @@ func (s *StreamUsecaseWrapper) UpdateStream(ctx context.Context, in *v1.UpdateSt
 	}()
 	return s.uc.UpdateStream(ctx, in)
 }
+
+func (s *StreamUsecaseWrapper) CreateStream(ctx context.Context, in *v1.CreateStreamRequest) (res *v1.Stream, err error) {
+	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "CreateStream")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.uc.CreateStream(ctx, in)
+}
+
+func (s *StreamUsecaseWrapper) DeleteStream(ctx context.Context, in *v1.DeleteStreamRequest) (err error) {
+	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "DeleteStream")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.uc.DeleteStream(ctx, in)
+}
+
+func (s *StreamUsecaseWrapper) IngestFrames(ctx context.Context, streamID string, src interfaces.FrameSource) (res *v1.IngestFramesResponse, err error) {
+	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "IngestFrames")
+	defer func() {
+		if res != nil {
+			span.SetAttributes(attribute.Int64("count", res.Count))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.uc.IngestFrames(ctx, streamID, src)
+}
+
+func (s *StreamUsecaseWrapper) ImportStream(ctx context.Context, in *v1.CreateStreamRequest, src interfaces.FrameSource) (res *v1.Stream, err error) {
+	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "ImportStream")
+	defer func() {
+		span.SetAttributes(
+			attribute.Stringer("in", in),
+		)
+		if res != nil {
+			span.SetAttributes(attribute.String("stream_id", res.Id), attribute.Int64("count", res.FrameCount))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.uc.ImportStream(ctx, in, src)
+}
+
+func (s *StreamUsecaseWrapper) GetStreamThumbnail(ctx context.Context, streamID string) (_ []byte, _ int64, err error) {
+	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "GetStreamThumbnail")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.uc.GetStreamThumbnail(ctx, streamID)
+}
+
+func (s *StreamUsecaseWrapper) ExportFrames(ctx context.Context, streamID string, fromSeq, toSeq int64, fn interfaces.FrameFunc) (err error) {
+	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "ExportFrames")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.uc.ExportFrames(ctx, streamID, fromSeq, toSeq, fn)
+}
+
+func (s *StreamUsecaseWrapper) ExportFrameRange(ctx context.Context, streamID string, fromSeq, toSeq int64, mime string, prepare func(fr *interfaces.FrameRange) error, fn interfaces.FrameFunc) (err error) {
+	ctx, span := otel.Tracer(UsecaseInstance).Start(ctx, "ExportFrameRange")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+		span.End()
+	}()
+	return s.uc.ExportFrameRange(ctx, streamID, fromSeq, toSeq, mime, prepare, fn)
+}
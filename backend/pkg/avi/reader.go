@@ -0,0 +1,202 @@
+package avi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	// ErrNotAVI поток не начинается с RIFF 'AVI '
+	ErrNotAVI = errors.New("avi: not an AVI file")
+	// ErrNoVideo в заголовке нет видеопотока
+	ErrNoVideo = errors.New("avi: no video stream")
+	// ErrFrameTooLarge кадр больше лимита читателя
+	ErrFrameTooLarge = errors.New("avi: frame too large")
+	// ErrMalformed нарушена структура чанков
+	ErrMalformed = errors.New("avi: malformed file")
+)
+
+// Reader читает кадры первого видеопотока по порядку, не загружая файл целиком
+// Чанки идут плоско: RIFF/LIST-контейнеры не отслеживаются по вложенности, в movi берём '##dc'/'##db'
+// нужного потока, остальное (звук, JUNK, idx1, индексы OpenDML) пропускаем. Продолжения RIFF 'AVIX' (>1 ГБ) тоже читаются
+type Reader struct {
+	r        *bufio.Reader
+	maxFrame int
+	interval time.Duration
+	video    string // префикс чанков кадров видеопотока: "00", "01", ...
+	head     [8]byte
+}
+
+// NewReader читает заголовки (до LIST movi); maxFrame — предел размера одного кадра
+func NewReader(r io.Reader, maxFrame int) (*Reader, error) {
+	ar := &Reader{r: bufio.NewReaderSize(r, 64<<10), maxFrame: maxFrame}
+
+	var riff [12]byte
+	if _, err := io.ReadFull(ar.r, riff[:]); err != nil || string(riff[:4]) != "RIFF" || string(riff[8:]) != "AVI " {
+		return nil, ErrNotAVI
+	}
+
+	var usPerFrame uint32
+	streams := 0
+	for {
+		id, size, err := ar.chunkHead()
+		if err != nil {
+			return nil, fmt.Errorf("avi header: %w", unexpected(err))
+		}
+		switch id {
+		case "LIST":
+			typ, err := ar.listType(size)
+			if err != nil {
+				return nil, fmt.Errorf("avi header: %w", unexpected(err))
+			}
+			switch typ {
+			case "movi":
+				if ar.video == "" {
+					return nil, ErrNoVideo
+				}
+				ar.interval = time.Duration(usPerFrame) * time.Microsecond
+				return ar, nil
+			case "hdrl", "strl":
+				// заходим внутрь
+			default:
+				if err = ar.skip(size - 4); err != nil {
+					return nil, fmt.Errorf("avi header: %w", unexpected(err))
+				}
+			}
+		case "avih":
+			b, err := ar.read(size)
+			if err != nil {
+				return nil, fmt.Errorf("avi header: %w", unexpected(err))
+			}
+			if len(b) >= 4 && usPerFrame == 0 {
+				usPerFrame = binary.LittleEndian.Uint32(b)
+			}
+		case "strh":
+			b, err := ar.read(size)
+			if err != nil {
+				return nil, fmt.Errorf("avi header: %w", unexpected(err))
+			}
+			// частота видеопотока точнее avih: Rate/Scale кадров в секунду
+			if ar.video == "" && len(b) >= 28 && string(b[:4]) == "vids" {
+				ar.video = fmt.Sprintf("%02d", streams)
+				if scale, rate := binary.LittleEndian.Uint32(b[20:]), binary.LittleEndian.Uint32(b[24:]); scale > 0 && rate > 0 {
+					usPerFrame = uint32(uint64(scale) * 1_000_000 / uint64(rate))
+				}
+			}
+			streams++
+		default:
+			if err = ar.skip(size); err != nil {
+				return nil, fmt.Errorf("avi header: %w", unexpected(err))
+			}
+		}
+	}
+}
+
+// FrameInterval интервал между кадрами по заголовку (0 — не указан)
+func (ar *Reader) FrameInterval() time.Duration {
+	return ar.interval
+}
+
+// Next — следующий кадр видеопотока (новый буфер); io.EOF — кадры закончились
+func (ar *Reader) Next() ([]byte, error) {
+	for {
+		id, size, err := ar.chunkHead()
+		if err != nil {
+			return nil, err // io.EOF ровно на границе чанка — штатный конец файла
+		}
+		switch {
+		case id == "RIFF":
+			// продолжение OpenDML: RIFF 'AVIX' со своим LIST movi
+			if _, err = ar.listType(size); err != nil {
+				return nil, unexpected(err)
+			}
+		case id == "LIST":
+			typ, err := ar.listType(size)
+			if err != nil {
+				return nil, unexpected(err)
+			}
+			if typ != "movi" && typ != "rec " {
+				if err = ar.skip(size - 4); err != nil {
+					return nil, unexpected(err)
+				}
+			}
+		case id[:2] == ar.video && (id[2:] == "dc" || id[2:] == "db"):
+			if int64(size) > int64(ar.maxFrame) {
+				return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, size)
+			}
+			b, err := ar.read(size)
+			if err != nil {
+				return nil, unexpected(err)
+			}
+			if len(b) == 0 {
+				continue // пустой чанк — "повтор предыдущего кадра", в стрим не пишем
+			}
+			return b, nil
+		default:
+			if err = ar.skip(size); err != nil {
+				return nil, unexpected(err)
+			}
+		}
+	}
+}
+
+func (ar *Reader) chunkHead() (string, uint32, error) {
+	n, err := io.ReadFull(ar.r, ar.head[:])
+	if err != nil {
+		if n > 0 {
+			return "", 0, io.ErrUnexpectedEOF
+		}
+		return "", 0, io.EOF
+	}
+	return string(ar.head[:4]), binary.LittleEndian.Uint32(ar.head[4:]), nil
+}
+
+// listType — тип LIST/RIFF (первые 4 байта содержимого)
+func (ar *Reader) listType(size uint32) (string, error) {
+	if size < 4 {
+		return "", fmt.Errorf("%w: list of %d bytes", ErrMalformed, size)
+	}
+	var typ [4]byte
+	if _, err := io.ReadFull(ar.r, typ[:]); err != nil {
+		return "", err
+	}
+	return string(typ[:]), nil
+}
+
+// read — содержимое чанка (+ выравнивающий байт)
+func (ar *Reader) read(size uint32) ([]byte, error) {
+	b := make([]byte, size)
+	if _, err := io.ReadFull(ar.r, b); err != nil {
+		return nil, err
+	}
+	if size%2 == 1 {
+		if _, err := ar.r.Discard(1); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (ar *Reader) skip(size uint32) error {
+	n := int(size) + int(size%2)
+	if d, err := ar.r.Discard(n); err != nil {
+		// выравнивающего байта в самом конце файла может и не быть
+		if errors.Is(err, io.EOF) && d >= int(size) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// unexpected — конец файла посреди чанка — это обрезанный файл, а не штатный конец
+func unexpected(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package avi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func readAll(t *testing.T, r *Reader) [][]byte {
+	t.Helper()
+	var res [][]byte
+	for {
+		f, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return res
+		}
+		if err != nil {
+			t.Fatalf("next after %d frames: %v", len(res), err)
+		}
+		res = append(res, f)
+	}
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	frames := [][]byte{
+		{0xFF, 0xD8, 1, 2, 0xFF, 0xD9},
+		{0xFF, 0xD8, 3, 0xFF, 0xD9},
+		{0xFF, 0xD8, 4, 5, 0xFF, 0xD9},
+	}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Layout{Width: 64, Height: 48, IntervalMS: 40, Frames: 3, Bytes: 17, OddFrames: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range frames {
+		if err = w.WriteFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(&buf, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.FrameInterval() != 40*time.Millisecond {
+		t.Fatalf("interval %v, want 40ms", r.FrameInterval())
+	}
+	got := readAll(t, r)
+	if len(got) != len(frames) {
+		t.Fatalf("got %d frames, want %d", len(got), len(frames))
+	}
+	for i := range frames {
+		if !bytes.Equal(got[i], frames[i]) {
+			t.Fatalf("frame %d: %v, want %v", i, got[i], frames[i])
+		}
+	}
+}
+
+// riffChunk — чанк с выравниванием; для LIST/RIFF body начинается с типа списка
+func riffChunk(id string, body ...[]byte) []byte {
+	data := bytes.Join(body, nil)
+	b := append([]byte(id), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+	b = append(b, data...)
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func strh(typ string, scale, rate uint32) []byte {
+	b := make([]byte, 56)
+	copy(b, typ)
+	binary.LittleEndian.PutUint32(b[20:], scale)
+	binary.LittleEndian.PutUint32(b[24:], rate)
+	return riffChunk("strh", b)
+}
+
+func TestReaderLayouts(t *testing.T) {
+	// звук первым потоком (видео — '01'), rec-списки, JUNK, idx1 и продолжение OpenDML AVIX
+	avih := make([]byte, 56)
+	binary.LittleEndian.PutUint32(avih, 100_000) // перекрывается частотой strh
+	file := riffChunk("RIFF", []byte("AVI "),
+		riffChunk("LIST", []byte("hdrl"),
+			riffChunk("avih", avih),
+			riffChunk("LIST", []byte("strl"), strh("auds", 1, 8000), riffChunk("strf", make([]byte, 18))),
+			riffChunk("LIST", []byte("strl"), strh("vids", 1, 25), riffChunk("strf", make([]byte, 40))),
+		),
+		riffChunk("LIST", []byte("INFO"), riffChunk("ISFT", []byte("test\x00"))),
+		riffChunk("JUNK", make([]byte, 7)),
+		riffChunk("LIST", []byte("movi"),
+			riffChunk("00wb", []byte{9, 9, 9}),
+			riffChunk("01dc", []byte{1}),
+			riffChunk("LIST", []byte("rec "), riffChunk("01dc", []byte{2, 2}), riffChunk("00wb", []byte{9})),
+			riffChunk("01dc", nil), // пустой кадр-повтор
+			riffChunk("ix01", make([]byte, 24)),
+		),
+		riffChunk("idx1", make([]byte, 64)),
+	)
+	file = append(file, riffChunk("RIFF", []byte("AVIX"),
+		riffChunk("LIST", []byte("movi"), riffChunk("01db", []byte{3, 3, 3})),
+	)...)
+
+	r, err := NewReader(bytes.NewReader(file), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.FrameInterval() != 40*time.Millisecond {
+		t.Fatalf("interval %v, want 40ms from strh 25fps", r.FrameInterval())
+	}
+	got := readAll(t, r)
+	want := [][]byte{{1}, {2, 2}, {3, 3, 3}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WAVE")), 1<<20); !errors.Is(err, ErrNotAVI) {
+		t.Fatalf("wave: %v", err)
+	}
+
+	noVideo := riffChunk("RIFF", []byte("AVI "),
+		riffChunk("LIST", []byte("hdrl"), riffChunk("LIST", []byte("strl"), strh("auds", 1, 8000))),
+		riffChunk("LIST", []byte("movi")),
+	)
+	if _, err := NewReader(bytes.NewReader(noVideo), 1<<20); !errors.Is(err, ErrNoVideo) {
+		t.Fatalf("no video: %v", err)
+	}
+
+	file := riffChunk("RIFF", []byte("AVI "),
+		riffChunk("LIST", []byte("hdrl"), riffChunk("LIST", []byte("strl"), strh("vids", 1, 25))),
+		riffChunk("LIST", []byte("movi"), riffChunk("00dc", make([]byte, 100))),
+	)
+	r, err := NewReader(bytes.NewReader(file), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Next(); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("too large: %v", err)
+	}
+
+	r, err = NewReader(bytes.NewReader(file[:len(file)-50]), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Next(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("truncated: %v", err)
+	}
+}
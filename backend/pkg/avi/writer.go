@@ -0,0 +1,186 @@
+// Package avi — потоковая запись Motion-JPEG в контейнер AVI (RIFF) без ffmpeg
+//
+// RIFF хранит размеры в заголовках, а поток назад не перемотать, поэтому число кадров
+// и их суммарный размер нужно знать заранее (Layout). В памяти держится только размер
+// каждого кадра (4 байта) — для индекса idx1, который пишется в конце
+package avi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var (
+	// ErrTooLarge файл не помещается в AVI 1.0 (RIFF-размеры 32-битные)
+	ErrTooLarge = errors.New("avi: too large for a single RIFF")
+	// ErrFrameCount записано не столько кадров, сколько объявлено в Layout
+	ErrFrameCount = errors.New("avi: frame count does not match layout")
+)
+
+// MaxFileSize — предел размера файла; часть плееров читает RIFF-размер как знаковый, поэтому 2 ГБ, а не 4
+const MaxFileSize = math.MaxInt32
+
+const (
+	hdrlSize  = 4 + (8 + avihSize) + (8 + strlSize) // содержимое LIST hdrl
+	strlSize  = 4 + (8 + strhSize) + (8 + strfSize) // содержимое LIST strl
+	avihSize  = 56
+	strhSize  = 56
+	strfSize  = 40
+	idxEntry  = 16
+	chunkHead = 8
+
+	avifHasIndex    = 0x10
+	aviifKeyframe   = 0x10
+	headerBytes     = 12 + 8 + hdrlSize + 12 // RIFF AVI + LIST hdrl + LIST movi (до первого кадра)
+	frameChunkID    = "00dc"
+	suggestedBuffer = 1 << 20
+)
+
+// Layout — что будет записано: геометрия, темп и объём кадров
+type Layout struct {
+	Width, Height int
+	IntervalMS    int   // frame_interval_ms стрима: частота кадров = 1000 / IntervalMS
+	Frames        int64 // число кадров
+	Bytes         int64 // суммарный размер JPEG без выравнивания
+	OddFrames     int64 // кадров нечётной длины: RIFF выравнивает их нулевым байтом
+}
+
+// moviSize — содержимое LIST movi: 'movi' + чанки кадров с выравниванием
+func (l Layout) moviSize() int64 {
+	return 4 + l.Frames*chunkHead + l.Bytes + l.OddFrames
+}
+
+// FileSize — точный размер файла (для Content-Length)
+func (l Layout) FileSize() int64 {
+	return headerBytes - 4 + l.moviSize() + chunkHead + l.Frames*idxEntry
+}
+
+// Writer пишет кадры по одному; Close дописывает idx1
+type Writer struct {
+	w      io.Writer
+	layout Layout
+	sizes  []uint32 // размеры кадров для idx1
+	buf    []byte
+}
+
+// NewWriter проверяет Layout и сразу пишет заголовки
+func NewWriter(w io.Writer, l Layout) (*Writer, error) {
+	if l.Width <= 0 || l.Height <= 0 || l.IntervalMS <= 0 || l.Frames <= 0 {
+		return nil, fmt.Errorf("avi: bad layout %+v", l)
+	}
+	if l.FileSize() > MaxFileSize || l.Width > math.MaxInt16 || l.Height > math.MaxInt16 {
+		return nil, ErrTooLarge
+	}
+	aw := &Writer{w: w, layout: l, sizes: make([]uint32, 0, l.Frames)}
+	if _, err := w.Write(aw.header()); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// header — RIFF 'AVI ', LIST hdrl (avih + strl: strh + strf) и начало LIST movi
+func (aw *Writer) header() []byte {
+	l := aw.layout
+	riffSize := l.FileSize() - 8
+	usPerFrame := uint32(l.IntervalMS) * 1000
+	maxBytesPerSec := uint32(0)
+	if l.Frames > 0 {
+		maxBytesPerSec = uint32(min(int64(math.MaxUint32), l.Bytes/l.Frames*1000/int64(l.IntervalMS)))
+	}
+
+	b := make([]byte, 0, headerBytes)
+	b = appendChunkHead(b, "RIFF", uint32(riffSize))
+	b = append(b, "AVI "...)
+
+	b = appendChunkHead(b, "LIST", hdrlSize)
+	b = append(b, "hdrl"...)
+	b = appendChunkHead(b, "avih", avihSize)
+	b = appendU32(b, usPerFrame, maxBytesPerSec, 0, avifHasIndex, uint32(l.Frames), 0, 1, suggestedBuffer,
+		uint32(l.Width), uint32(l.Height), 0, 0, 0, 0)
+
+	b = appendChunkHead(b, "LIST", strlSize)
+	b = append(b, "strl"...)
+	b = appendChunkHead(b, "strh", strhSize)
+	b = append(b, "vidsMJPG"...)
+	b = appendU32(b, 0)    // dwFlags
+	b = appendU16(b, 0, 0) // wPriority, wLanguage
+	// dwInitialFrames, dwScale/dwRate (частота = Rate/Scale = 1000/IntervalMS), dwStart, dwLength,
+	// dwSuggestedBufferSize, dwQuality (-1 — по умолчанию), dwSampleSize
+	b = appendU32(b, 0, uint32(l.IntervalMS), 1000, 0, uint32(l.Frames), suggestedBuffer, math.MaxUint32, 0)
+	b = appendU16(b, 0, 0, uint16(l.Width), uint16(l.Height)) // rcFrame
+
+	// BITMAPINFOHEADER
+	b = appendChunkHead(b, "strf", strfSize)
+	b = appendU32(b, strfSize, uint32(l.Width), uint32(l.Height))
+	b = appendU16(b, 1, 24) // biPlanes, biBitCount
+	b = append(b, "MJPG"...)
+	b = appendU32(b, uint32(l.Width*l.Height*3), 0, 0, 0, 0)
+
+	b = appendChunkHead(b, "LIST", uint32(l.moviSize()))
+	b = append(b, "movi"...)
+	return b
+}
+
+// WriteFrame — чанк '00dc' с JPEG; нечётная длина выравнивается нулевым байтом
+func (aw *Writer) WriteFrame(jpeg []byte) error {
+	if int64(len(aw.sizes)) >= aw.layout.Frames {
+		return ErrFrameCount
+	}
+	aw.buf = appendChunkHead(aw.buf[:0], frameChunkID, uint32(len(jpeg)))
+	if _, err := aw.w.Write(aw.buf); err != nil {
+		return err
+	}
+	if _, err := aw.w.Write(jpeg); err != nil {
+		return err
+	}
+	if len(jpeg)%2 == 1 {
+		if _, err := aw.w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	aw.sizes = append(aw.sizes, uint32(len(jpeg)))
+	return nil
+}
+
+// Close — индекс idx1 (смещения от начала 'movi'); кадров должно быть ровно Layout.Frames
+func (aw *Writer) Close() error {
+	if int64(len(aw.sizes)) != aw.layout.Frames {
+		return fmt.Errorf("%w: wrote %d of %d", ErrFrameCount, len(aw.sizes), aw.layout.Frames)
+	}
+	aw.buf = appendChunkHead(aw.buf[:0], "idx1", uint32(len(aw.sizes)*idxEntry))
+	if _, err := aw.w.Write(aw.buf); err != nil {
+		return err
+	}
+	offset := uint32(4) // сразу за 'movi'
+	for _, size := range aw.sizes {
+		aw.buf = append(aw.buf[:0], frameChunkID...)
+		aw.buf = appendU32(aw.buf, aviifKeyframe, offset, size)
+		if _, err := aw.w.Write(aw.buf); err != nil {
+			return err
+		}
+		offset += chunkHead + size + size%2
+	}
+	return nil
+}
+
+func appendChunkHead(b []byte, id string, size uint32) []byte {
+	b = append(b, id...)
+	return binary.LittleEndian.AppendUint32(b, size)
+}
+
+func appendU32(b []byte, vs ...uint32) []byte {
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+func appendU16(b []byte, vs ...uint16) []byte {
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint16(b, v)
+	}
+	return b
+}
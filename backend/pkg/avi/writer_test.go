@@ -0,0 +1,139 @@
+package avi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// chunk — разобранный RIFF-чанк (для LIST/RIFF list — тип и вложенные чанки)
+type chunk struct {
+	id       string
+	list     string
+	data     []byte
+	children []chunk
+	offset   int // смещение заголовка чанка от начала родительских данных
+}
+
+func parseChunks(t *testing.T, b []byte) []chunk {
+	t.Helper()
+	var res []chunk
+	for off := 0; off < len(b); {
+		if off+8 > len(b) {
+			t.Fatalf("truncated chunk header at %d", off)
+		}
+		id := string(b[off : off+4])
+		size := int(binary.LittleEndian.Uint32(b[off+4:]))
+		if off+8+size > len(b) {
+			t.Fatalf("chunk %s at %d: size %d overflows parent (%d)", id, off, size, len(b))
+		}
+		c := chunk{id: id, data: b[off+8 : off+8+size], offset: off}
+		if id == "RIFF" || id == "LIST" {
+			c.list = string(c.data[:4])
+			c.children = parseChunks(t, c.data[4:])
+		}
+		res = append(res, c)
+		off += 8 + size + size%2
+	}
+	return res
+}
+
+func find(t *testing.T, cs []chunk, id string) chunk {
+	t.Helper()
+	for _, c := range cs {
+		if c.id == id || c.list == id {
+			return c
+		}
+	}
+	t.Fatalf("no %s chunk", id)
+	return chunk{}
+}
+
+func TestWriterProducesValidAVI(t *testing.T) {
+	frames := [][]byte{
+		{0xFF, 0xD8, 1, 2, 0xFF, 0xD9},
+		{0xFF, 0xD8, 3, 0xFF, 0xD9}, // нечётная длина — нужен паддинг
+		{0xFF, 0xD8, 4, 5, 0xFF, 0xD9},
+	}
+	l := Layout{Width: 64, Height: 48, IntervalMS: 40, Frames: 3, Bytes: 17, OddFrames: 1}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range frames {
+		if err = w.WriteFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if int64(buf.Len()) != l.FileSize() {
+		t.Fatalf("file size %d, layout says %d", buf.Len(), l.FileSize())
+	}
+
+	top := parseChunks(t, buf.Bytes())
+	if len(top) != 1 || top[0].id != "RIFF" || top[0].list != "AVI " {
+		t.Fatalf("expected single RIFF AVI, got %+v", top)
+	}
+	riff := top[0].children
+	hdrl := find(t, riff, "hdrl")
+	avih := find(t, hdrl.children, "avih")
+	if us := binary.LittleEndian.Uint32(avih.data); us != 40000 {
+		t.Fatalf("dwMicroSecPerFrame = %d", us)
+	}
+	if n := binary.LittleEndian.Uint32(avih.data[16:]); n != 3 {
+		t.Fatalf("dwTotalFrames = %d", n)
+	}
+	strh := find(t, find(t, hdrl.children, "strl").children, "strh")
+	if string(strh.data[:8]) != "vidsMJPG" {
+		t.Fatalf("unexpected stream header %q", strh.data[:8])
+	}
+	if scale, rate := binary.LittleEndian.Uint32(strh.data[20:]), binary.LittleEndian.Uint32(strh.data[24:]); rate/scale != 25 {
+		t.Fatalf("fps = %d/%d", rate, scale)
+	}
+
+	movi := find(t, riff, "movi")
+	if len(movi.children) != 3 {
+		t.Fatalf("expected 3 frame chunks, got %d", len(movi.children))
+	}
+	idx := find(t, riff, "idx1")
+	if len(idx.data) != 3*16 {
+		t.Fatalf("idx1 size %d", len(idx.data))
+	}
+	for i, f := range frames {
+		c := movi.children[i]
+		if c.id != "00dc" || !bytes.Equal(c.data, f) {
+			t.Fatalf("frame %d: %s %v", i, c.id, c.data)
+		}
+		e := idx.data[i*16:]
+		// смещение в idx1 — от 'movi', т.е. на 4 больше смещения в данных после типа списка
+		if string(e[:4]) != "00dc" || int(binary.LittleEndian.Uint32(e[8:])) != c.offset+4 || int(binary.LittleEndian.Uint32(e[12:])) != len(f) {
+			t.Fatalf("idx1 entry %d does not point at its frame: %v (chunk at %d)", i, e[:16], c.offset)
+		}
+	}
+}
+
+func TestWriterRejects(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, Layout{Width: 1, Height: 1, IntervalMS: 40, Frames: 1, Bytes: MaxFileSize}); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, Layout{Width: 1, Height: 1, Frames: 1}); err == nil {
+		t.Fatal("expected error for zero interval")
+	}
+
+	w, err := NewWriter(&bytes.Buffer{}, Layout{Width: 1, Height: 1, IntervalMS: 40, Frames: 1, Bytes: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); !errors.Is(err, ErrFrameCount) {
+		t.Fatalf("expected ErrFrameCount on missing frames, got %v", err)
+	}
+	_ = w.WriteFrame([]byte{1, 2})
+	if err = w.WriteFrame([]byte{1, 2}); !errors.Is(err, ErrFrameCount) {
+		t.Fatalf("expected ErrFrameCount on extra frame, got %v", err)
+	}
+}
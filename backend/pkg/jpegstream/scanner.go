@@ -0,0 +1,184 @@
+// Package jpegstream режет "сырой" MJPEG — JPEG-кадры подряд, как пишут IP-камеры и NVR — на отдельные кадры
+package jpegstream
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrFrameTooLarge кадр больше лимита сканера
+	ErrFrameTooLarge = errors.New("jpegstream: frame too large")
+	// ErrTruncated поток оборвался посреди кадра
+	ErrTruncated = errors.New("jpegstream: truncated frame")
+	// ErrMalformed нарушена структура маркеров JPEG
+	ErrMalformed = errors.New("jpegstream: malformed frame")
+)
+
+const (
+	markerSOI = 0xD8
+	markerEOI = 0xD9
+	markerSOS = 0xDA
+)
+
+// Scanner выделяет кадры по структуре JPEG, а не поиском FFD9: EOI встречается внутри
+// встроенных превью (APP1/EXIF), поэтому сегменты с длиной пропускаются целиком, а в
+// энтропийных данных FF00 и RSTn считаются данными. Мусор между кадрами пропускается
+type Scanner struct {
+	r        *bufio.Reader
+	maxFrame int
+	buf      []byte
+}
+
+// NewScanner — maxFrame предел размера одного кадра
+func NewScanner(r io.Reader, maxFrame int) *Scanner {
+	return &Scanner{r: bufio.NewReaderSize(r, 64<<10), maxFrame: maxFrame}
+}
+
+// Next — следующий кадр от SOI до EOI включительно (новый буфер); io.EOF — кадров больше нет
+func (s *Scanner) Next() ([]byte, error) {
+	if err := s.seekSOI(); err != nil {
+		return nil, err
+	}
+	s.buf = append(make([]byte, 0, 64<<10), 0xFF, markerSOI)
+
+	var pending byte // маркер, на котором остановились энтропийные данные
+	for {
+		m, err := pending, error(nil)
+		if pending == 0 {
+			if m, err = s.marker(); err != nil {
+				return nil, err
+			}
+		}
+		pending = 0
+		switch {
+		case m == markerEOI:
+			if err = s.put(0xFF, m); err != nil {
+				return nil, err
+			}
+			return s.buf, nil
+		case m == 0x01 || (m >= 0xD0 && m <= 0xD7):
+			// маркеры без длины
+			if err = s.put(0xFF, m); err != nil {
+				return nil, err
+			}
+		case m == markerSOS:
+			if err = s.segment(m); err != nil {
+				return nil, err
+			}
+			if pending, err = s.entropy(); err != nil {
+				return nil, err
+			}
+		default:
+			if err = s.segment(m); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// seekSOI пропускает байты до FFD8; io.EOF, если кадров больше нет
+func (s *Scanner) seekSOI() error {
+	prevFF := false
+	for {
+		b, err := s.r.ReadByte()
+		if err != nil {
+			return err // io.EOF — кадров больше нет
+		}
+		if prevFF && b == markerSOI {
+			return nil
+		}
+		prevFF = b == 0xFF
+	}
+}
+
+// marker читает следующий маркер (с пропуском заполняющих FF)
+func (s *Scanner) marker() (byte, error) {
+	b, err := s.r.ReadByte()
+	if err != nil {
+		return 0, truncated(err)
+	}
+	if b != 0xFF {
+		return 0, fmt.Errorf("%w: expected marker, got 0x%02x", ErrMalformed, b)
+	}
+	for {
+		if b, err = s.r.ReadByte(); err != nil {
+			return 0, truncated(err)
+		}
+		if b != 0xFF {
+			return b, nil
+		}
+	}
+}
+
+// segment — маркер с 2-байтовой длиной и содержимым
+func (s *Scanner) segment(m byte) error {
+	var l [2]byte
+	if _, err := io.ReadFull(s.r, l[:]); err != nil {
+		return truncated(err)
+	}
+	n := int(l[0])<<8 | int(l[1])
+	if n < 2 {
+		return fmt.Errorf("%w: bad length %d of marker 0x%02x", ErrMalformed, n, m)
+	}
+	if err := s.put(0xFF, m, l[0], l[1]); err != nil {
+		return err
+	}
+	if len(s.buf)+n-2 > s.maxFrame {
+		return fmt.Errorf("%w: more than %d bytes", ErrFrameTooLarge, s.maxFrame)
+	}
+	start := len(s.buf)
+	s.buf = append(s.buf, make([]byte, n-2)...)
+	if _, err := io.ReadFull(s.r, s.buf[start:]); err != nil {
+		return truncated(err)
+	}
+	return nil
+}
+
+// entropy копирует сжатые данные после SOS и возвращает настоящий маркер, которым они закончились
+func (s *Scanner) entropy() (byte, error) {
+	for {
+		b, err := s.r.ReadByte()
+		if err != nil {
+			return 0, truncated(err)
+		}
+		if b != 0xFF {
+			if err = s.put(b); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		m, err := s.r.ReadByte()
+		for err == nil && m == 0xFF { // заполняющие FF перед маркером
+			m, err = s.r.ReadByte()
+		}
+		if err != nil {
+			return 0, truncated(err)
+		}
+		if m == 0x00 || (m >= 0xD0 && m <= 0xD7) {
+			if err = s.put(0xFF, m); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		return m, nil
+	}
+}
+
+func (s *Scanner) put(b ...byte) error {
+	if len(s.buf)+len(b) > s.maxFrame {
+		return fmt.Errorf("%w: more than %d bytes", ErrFrameTooLarge, s.maxFrame)
+	}
+	s.buf = append(s.buf, b...)
+	return nil
+}
+
+// truncated — конец потока посреди кадра — обрезанный файл; прочие ошибки чтения возвращаются как есть
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncated
+	}
+	return err
+}
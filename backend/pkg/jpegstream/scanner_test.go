@@ -0,0 +1,88 @@
+package jpegstream
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"testing"
+)
+
+func encode(t *testing.T, shade uint8, restart bool) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 32, 24))
+	for i := range img.Pix {
+		img.Pix[i] = shade + uint8(i*7) // шум: в энтропийных данных будут FF00
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	if restart {
+		// DRI перед SOS не нужен для разбора — проверяем только, что RSTn внутри данных не режет кадр
+		sos := bytes.Index(b, []byte{0xFF, 0xDA})
+		n := int(b[sos+2])<<8 | int(b[sos+3])
+		data := sos + 2 + n
+		b = append(append(append([]byte{}, b[:data+4]...), 0xFF, 0xD3), b[data+4:]...)
+	}
+	return b
+}
+
+// withThumbnail — кадр с APP1, внутри которого целый JPEG (со своим FFD9), как у EXIF-превью
+func withThumbnail(main, thumb []byte) []byte {
+	n := len(thumb) + 2
+	app1 := append([]byte{0xFF, 0xE1, byte(n >> 8), byte(n)}, thumb...)
+	return append(append(append([]byte{}, main[:2]...), app1...), main[2:]...)
+}
+
+func TestScannerSplitsFrames(t *testing.T) {
+	first := encode(t, 10, false)
+	second := withThumbnail(encode(t, 200, false), encode(t, 50, false))
+	third := encode(t, 90, true)
+
+	var in bytes.Buffer
+	in.WriteString("--frame\r\nContent-Type: image/jpeg\r\n\r\n") // мусор перед кадром (дамп multipart)
+	in.Write(first)
+	in.Write(second)
+	in.WriteString("\r\n--frame\r\n\xff\xff")
+	in.Write(third)
+	in.WriteString("\r\n")
+
+	s := NewScanner(&in, 1<<20)
+	for i, want := range [][]byte{first, second, third} {
+		got, err := s.Next()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("frame %d: %d bytes, want %d", i, len(got), len(want))
+		}
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(second)); err != nil {
+		t.Fatalf("frame with thumbnail must stay decodable: %v", err)
+	}
+	if _, err := s.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("after last frame: %v", err)
+	}
+}
+
+func TestScannerErrors(t *testing.T) {
+	frame := encode(t, 10, false)
+
+	s := NewScanner(bytes.NewReader(frame[:len(frame)-10]), 1<<20)
+	if _, err := s.Next(); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("truncated: %v", err)
+	}
+
+	s = NewScanner(bytes.NewReader(frame), len(frame)-1)
+	if _, err := s.Next(); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("too large: %v", err)
+	}
+
+	s = NewScanner(bytes.NewReader([]byte("no jpeg here")), 1<<20)
+	if _, err := s.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("garbage only: %v", err)
+	}
+}
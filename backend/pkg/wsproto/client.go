@@ -0,0 +1,259 @@
+package wsproto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrClosed соединение закрыто до получения ответа
+var ErrClosed = errors.New("wsproto: connection closed")
+
+// Conn — то, что клиенту нужно от websocket-соединения (*websocket.Conn; в тестах — фейк)
+type Conn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// Frame кадр от сервера; Meta заполнен, если согласован подпротокол с метаданными
+type Frame struct {
+	Data []byte
+	Meta *FrameHeader
+}
+
+// Client читает кадры и служебные сообщения, отправляет команды и ждёт на них ack
+// Кадры нужно вычитывать из Frames: пока канал полон, чтение (и ack) стоит. Close останавливает чтение и без этого
+type Client struct {
+	conn        Conn
+	subprotocol string
+	frames      chan Frame
+	done        chan struct{}
+	closed      chan struct{} // закрывается в Close: чтение не ждёт, пока освободится место в frames
+	closeOnce   sync.Once
+	meta        *FrameHeader // SubprotocolMeta: метаданные, пришедшие перед следующим бинарным кадром
+
+	writeMu sync.Mutex // gorilla допускает только одного писателя
+
+	mu       sync.Mutex
+	pending  map[string]chan Ack
+	nextID   uint64
+	playback Playback
+	err      error
+}
+
+// Dial открывает сессию воспроизведения; url вида ws://host/v1/streams/{id}/ws?rate=2
+// subprotocols — желаемый режим метаданных кадров (SubprotocolFramed/SubprotocolMeta), без них — сырые кадры
+func Dial(ctx context.Context, url string, header http.Header, subprotocols ...string) (*Client, error) {
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = subprotocols
+	conn, _, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient запускает чтение из conn
+// Режим кадров берётся из conn.Subprotocol(), если conn его сообщает (как *websocket.Conn)
+func NewClient(conn Conn) *Client {
+	c := &Client{
+		conn:    conn,
+		frames:  make(chan Frame, 16),
+		done:    make(chan struct{}),
+		closed:  make(chan struct{}),
+		pending: make(map[string]chan Ack),
+	}
+	if sp, ok := conn.(interface{ Subprotocol() string }); ok {
+		c.subprotocol = sp.Subprotocol()
+	}
+	go c.readLoop()
+	return c
+}
+
+// Frames кадры в порядке получения; закрывается по концу сессии (причина — Err)
+func (c *Client) Frames() <-chan Frame {
+	return c.frames
+}
+
+// Done закрывается, когда чтение завершилось
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err причина завершения чтения (nil, пока соединение живо)
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Playback последнее полученное сообщение "playback" (сервер шлёт его только при согласованном подпротоколе)
+func (c *Client) Playback() Playback {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.playback
+}
+
+// Close закрыть соединение; горутина чтения завершается, даже если Frames больше никто не читает
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return c.conn.Close()
+}
+
+func (c *Client) readLoop() {
+	defer close(c.frames)
+	defer close(c.done)
+	for {
+		typ, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+		switch typ {
+		case websocket.BinaryMessage:
+			if err = c.handleFrame(data); err != nil {
+				c.mu.Lock()
+				c.err = err
+				c.mu.Unlock()
+				return
+			}
+		case websocket.TextMessage:
+			c.handleText(data)
+		}
+	}
+}
+
+// handleFrame — бинарный кадр с метаданными согласно подпротоколу
+func (c *Client) handleFrame(data []byte) error {
+	f := Frame{Data: data}
+	switch c.subprotocol {
+	case SubprotocolFramed:
+		h, payload, err := ParseFramed(data)
+		if err != nil {
+			return err
+		}
+		f = Frame{Data: payload, Meta: &h}
+	case SubprotocolMeta:
+		f.Meta, c.meta = c.meta, nil
+	}
+	select {
+	case c.frames <- f:
+		return nil
+	case <-c.closed:
+		return ErrClosed
+	}
+}
+
+// handleText — служебные сообщения; незнакомые типы пропускаем (совместимость с новыми версиями сервера)
+func (c *Client) handleText(data []byte) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return
+	}
+	switch env.Type {
+	case TypeFrame:
+		var m FrameMeta
+		if json.Unmarshal(data, &m) == nil {
+			c.meta = &FrameHeader{Seq: m.Sequence, TimestampMS: m.TimestampMS, Skipped: m.Skipped, Mime: m.MimeType}
+		}
+	case TypePlayback:
+		var p Playback
+		if json.Unmarshal(data, &p) == nil {
+			c.mu.Lock()
+			c.playback = p
+			c.mu.Unlock()
+		}
+	case TypeAck:
+		var ack Ack
+		if json.Unmarshal(data, &ack) != nil {
+			return
+		}
+		c.mu.Lock()
+		ch := c.pending[ack.ID]
+		delete(c.pending, ack.ID)
+		c.mu.Unlock()
+		if ch != nil {
+			ch <- ack
+		}
+	}
+}
+
+// Send отправить команду и дождаться ack; ID и версию проставляет клиент
+// Отказ сервера возвращается ошибкой вместе с состоянием сессии
+func (c *Client) Send(ctx context.Context, cmd Command) (State, error) {
+	ch := make(chan Ack, 1)
+	c.mu.Lock()
+	c.nextID++
+	cmd.V = Version
+	cmd.ID = strconv.FormatUint(c.nextID, 10)
+	c.pending[cmd.ID] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, cmd.ID)
+		c.mu.Unlock()
+	}()
+
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return State{}, err
+	}
+	c.writeMu.Lock()
+	err = c.conn.WriteMessage(websocket.TextMessage, data)
+	c.writeMu.Unlock()
+	if err != nil {
+		return State{}, err
+	}
+
+	select {
+	case ack := <-ch:
+		var st State
+		if ack.State != nil {
+			st = *ack.State
+		}
+		if !ack.OK {
+			return st, fmt.Errorf("wsproto: %s rejected: %s", cmd.Type, ack.Error)
+		}
+		return st, nil
+	case <-c.done:
+		return State{}, ErrClosed
+	case <-ctx.Done():
+		return State{}, ctx.Err()
+	}
+}
+
+func (c *Client) Pause(ctx context.Context) (State, error) {
+	return c.Send(ctx, Command{Type: TypePause})
+}
+
+func (c *Client) Resume(ctx context.Context) (State, error) {
+	return c.Send(ctx, Command{Type: TypeResume})
+}
+
+// Seek перейти к кадру seq (зажимается в границы стрима)
+func (c *Client) Seek(ctx context.Context, seq int64) (State, error) {
+	return c.Send(ctx, Command{Type: TypeSeek, Seq: &seq})
+}
+
+// SeekMs перейти ко времени стрима ms (от первого кадра)
+func (c *Client) SeekMs(ctx context.Context, ms int64) (State, error) {
+	return c.Send(ctx, Command{Type: TypeSeek, Ms: &ms})
+}
+
+func (c *Client) SetRate(ctx context.Context, rate float64) (State, error) {
+	return c.Send(ctx, Command{Type: TypeSetRate, Rate: &rate})
+}
+
+// Stop попросить сервер завершить сессию; после ack сервер закрывает соединение
+func (c *Client) Stop(ctx context.Context) (State, error) {
+	return c.Send(ctx, Command{Type: TypeStop})
+}
@@ -0,0 +1,215 @@
+package wsproto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type message struct {
+	typ  int
+	data []byte
+}
+
+// fakeConn — вместо websocket.Conn: in — то, что "прислал сервер", out — то, что записал клиент
+type fakeConn struct {
+	in          chan message
+	out         chan []byte
+	closed      chan struct{}
+	subprotocol string
+}
+
+func (c *fakeConn) Subprotocol() string { return c.subprotocol }
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{in: make(chan message, 16), out: make(chan []byte, 16), closed: make(chan struct{})}
+}
+
+func (c *fakeConn) ReadMessage() (int, []byte, error) {
+	select {
+	case m := <-c.in:
+		return m.typ, m.data, nil
+	case <-c.closed:
+		return 0, nil, io.EOF
+	}
+}
+
+func (c *fakeConn) WriteMessage(_ int, data []byte) error {
+	c.out <- data
+	return nil
+}
+
+func (c *fakeConn) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func (c *fakeConn) sendJSON(t *testing.T, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.in <- message{websocket.TextMessage, data}
+}
+
+// serve — фейковый сервер: на каждую команду отвечает ack через reply
+func (c *fakeConn) serve(t *testing.T, reply func(Command) Ack) {
+	go func() {
+		for {
+			select {
+			case data := <-c.out:
+				var cmd Command
+				if err := json.Unmarshal(data, &cmd); err != nil {
+					t.Errorf("bad command %s: %v", data, err)
+					return
+				}
+				ack := reply(cmd)
+				ack.Type, ack.ID, ack.Cmd = TypeAck, cmd.ID, cmd.Type
+				c.sendJSON(t, ack)
+			case <-c.closed:
+				return
+			}
+		}
+	}()
+}
+
+func TestClientCommandsAndAcks(t *testing.T) {
+	conn := newFakeConn()
+	var got []Command
+	conn.serve(t, func(cmd Command) Ack {
+		got = append(got, cmd)
+		if cmd.Type == TypeSetRate && *cmd.Rate > 8 {
+			return Ack{V: Version, OK: false, Error: "rate out of range", State: &State{Rate: 1}}
+		}
+		return Ack{V: Version, OK: true, State: &State{Paused: cmd.Type == TypePause, Seq: 7, Rate: 1}}
+	})
+	c := NewClient(conn)
+	defer c.Close()
+	ctx := context.Background()
+
+	st, err := c.Pause(ctx)
+	if err != nil || !st.Paused {
+		t.Fatalf("pause: state=%+v err=%v", st, err)
+	}
+	if st, err = c.Seek(ctx, 7); err != nil || st.Seq != 7 {
+		t.Fatalf("seek: state=%+v err=%v", st, err)
+	}
+	if _, err = c.SetRate(ctx, 16); err == nil {
+		t.Fatal("expected rejected set_rate")
+	}
+
+	if len(got) != 3 || got[0].Type != TypePause || got[1].Type != TypeSeek || *got[1].Seq != 7 {
+		t.Fatalf("unexpected commands: %+v", got)
+	}
+	for i, cmd := range got {
+		if cmd.V != Version || cmd.ID == "" || (i > 0 && cmd.ID == got[i-1].ID) {
+			t.Fatalf("command %d must carry version and unique id: %+v", i, cmd)
+		}
+	}
+}
+
+func TestClientFramesAndPlayback(t *testing.T) {
+	conn := newFakeConn()
+	c := NewClient(conn)
+
+	conn.sendJSON(t, Playback{V: Version, Type: TypePlayback, Rate: 2, FPS: 50})
+	conn.in <- message{websocket.BinaryMessage, []byte{0xff, 0xd8}}
+	conn.sendJSON(t, map[string]string{"type": "something_new"}) // неизвестный тип пропускается
+	conn.in <- message{websocket.BinaryMessage, []byte{0xff, 0xd9}}
+
+	for _, want := range []byte{0xd8, 0xd9} {
+		select {
+		case f := <-c.Frames():
+			if f.Data[1] != want || f.Meta != nil {
+				t.Fatalf("got frame %+v, want raw ..%x", f, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("frame not delivered")
+		}
+	}
+	if p := c.Playback(); p.Rate != 2 || p.FPS != 50 {
+		t.Fatalf("playback not recorded: %+v", p)
+	}
+
+	_ = c.Close()
+	if _, ok := <-c.Frames(); ok {
+		t.Fatal("frames channel must close with the connection")
+	}
+	if c.Err() == nil {
+		t.Fatal("expected read error after close")
+	}
+}
+
+func TestClientSendFailsWhenClosed(t *testing.T) {
+	conn := newFakeConn()
+	c := NewClient(conn)
+	go func() {
+		<-conn.out // команду "получили", но ответить не успели
+		_ = conn.Close()
+	}()
+	if _, err := c.Resume(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestClientFrameMetadata(t *testing.T) {
+	want := FrameHeader{Seq: 42, TimestampMS: 1680, Skipped: 2, Mime: "image/jpeg"}
+	jpeg := []byte{0xff, 0xd8, 0xff, 0xd9}
+
+	framed := newFakeConn()
+	framed.subprotocol = SubprotocolFramed
+	framed.in <- message{websocket.BinaryMessage, append(AppendFrameHeader(nil, want), jpeg...)}
+
+	meta := newFakeConn()
+	meta.subprotocol = SubprotocolMeta
+	meta.sendJSON(t, FrameMeta{V: Version, Type: TypeFrame, Sequence: 42, TimestampMS: 1680, Skipped: 2, MimeType: "image/jpeg"})
+	meta.in <- message{websocket.BinaryMessage, jpeg}
+
+	for _, conn := range []*fakeConn{framed, meta} {
+		c := NewClient(conn)
+		select {
+		case f := <-c.Frames():
+			if f.Meta == nil || *f.Meta != want || string(f.Data) != string(jpeg) {
+				t.Fatalf("%s: got %+v", conn.subprotocol, f)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: frame not delivered", conn.subprotocol)
+		}
+		_ = c.Close()
+	}
+}
+
+func TestClientCloseStopsReaderWithoutConsumer(t *testing.T) {
+	conn := newFakeConn()
+	c := NewClient(conn)
+	// Frames никто не читает: канал кадров забивается, и чтение встаёт на отправке
+	go func() {
+		for range 32 {
+			select {
+			case conn.in <- message{websocket.BinaryMessage, []byte{0xFF, 0xD8}}:
+			case <-conn.closed:
+				return
+			}
+		}
+	}()
+	deadline := time.Now().Add(time.Second)
+	for len(c.frames) < cap(c.frames) {
+		if time.Now().After(deadline) {
+			t.Fatal("frames channel did not fill up")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	_ = c.Close()
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("reader goroutine must stop on Close")
+	}
+}
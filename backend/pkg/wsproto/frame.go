@@ -0,0 +1,99 @@
+package wsproto
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Подпротоколы WebSocket (Sec-WebSocket-Protocol) — как кадр сопровождается метаданными
+// Без подпротокола сервер шлёт "сырой" JPEG в бинарном сообщении (как раньше)
+const (
+	SubprotocolFramed = "mjpeg.framed.v1" // бинарное сообщение = FrameHeader + кадр
+	SubprotocolMeta   = "mjpeg.meta.v1"   // перед каждым бинарным кадром — текстовое сообщение FrameMeta
+)
+
+// TypeFrame тип текстового сообщения с метаданными кадра (подпротокол SubprotocolMeta)
+const TypeFrame = "frame"
+
+// FrameHeader метаданные кадра
+// TimestampMS — время стрима: (seq - min_seq) * frame_interval_ms, та же шкала, что у seek по ms
+// Skipped — сколько кадров сервер пропустил перед этим, догоняя шкалу времени
+type FrameHeader struct {
+	Seq         int64
+	TimestampMS int64
+	Skipped     uint32
+	Mime        string
+}
+
+// FrameMeta текстовое представление FrameHeader
+type FrameMeta struct {
+	V           int    `json:"v"`
+	Type        string `json:"type"` // всегда "frame"
+	Sequence    int64  `json:"sequence"`
+	TimestampMS int64  `json:"timestamp_ms"`
+	Skipped     uint32 `json:"skipped"`
+	MimeType    string `json:"mime_type"`
+}
+
+// Бинарный заголовок (big-endian):
+//
+//	0      version (1)
+//	1..2   длина заголовка целиком, включая mime (клиент пропускает неизвестные хвостовые поля)
+//	3..10  seq
+//	11..18 timestamp_ms
+//	19..22 skipped
+//	23     длина mime
+//	24..   mime
+const (
+	frameHeaderVersion = 1
+	frameHeaderFixed   = 24
+	maxMimeLen         = math.MaxUint8
+)
+
+var ErrBadFrameHeader = errors.New("wsproto: bad frame header")
+
+// AppendFrameHeader дописывает бинарный заголовок кадра в dst; mime длиннее 255 байт обрезается
+func AppendFrameHeader(dst []byte, h FrameHeader) []byte {
+	mime := h.Mime
+	if len(mime) > maxMimeLen {
+		mime = mime[:maxMimeLen]
+	}
+	dst = append(dst, frameHeaderVersion)
+	dst = binary.BigEndian.AppendUint16(dst, uint16(frameHeaderFixed+len(mime)))
+	dst = binary.BigEndian.AppendUint64(dst, uint64(h.Seq))
+	dst = binary.BigEndian.AppendUint64(dst, uint64(h.TimestampMS))
+	dst = binary.BigEndian.AppendUint32(dst, h.Skipped)
+	dst = append(dst, byte(len(mime)))
+	return append(dst, mime...)
+}
+
+// ParseFramed разбирает бинарное сообщение подпротокола SubprotocolFramed: заголовок и сам кадр
+func ParseFramed(msg []byte) (FrameHeader, []byte, error) {
+	if len(msg) < frameHeaderFixed || msg[0] != frameHeaderVersion {
+		return FrameHeader{}, nil, ErrBadFrameHeader
+	}
+	hdrLen := int(binary.BigEndian.Uint16(msg[1:3]))
+	mimeLen := int(msg[23])
+	if hdrLen < frameHeaderFixed+mimeLen || hdrLen > len(msg) {
+		return FrameHeader{}, nil, ErrBadFrameHeader
+	}
+	h := FrameHeader{
+		Seq:         int64(binary.BigEndian.Uint64(msg[3:11])),
+		TimestampMS: int64(binary.BigEndian.Uint64(msg[11:19])),
+		Skipped:     binary.BigEndian.Uint32(msg[19:23]),
+		Mime:        string(msg[frameHeaderFixed : frameHeaderFixed+mimeLen]),
+	}
+	return h, msg[hdrLen:], nil
+}
+
+// ClampSkipped — счётчик пропусков в поле заголовка
+func ClampSkipped(n int64) uint32 {
+	switch {
+	case n < 0:
+		return 0
+	case n > math.MaxUint32:
+		return math.MaxUint32
+	}
+	return uint32(n)
+}
@@ -0,0 +1,48 @@
+package wsproto
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFrameHeaderRoundTrip(t *testing.T) {
+	for _, h := range []FrameHeader{
+		{Seq: 0, TimestampMS: 0, Skipped: 0, Mime: ""},
+		{Seq: 1<<40 + 7, TimestampMS: 123456, Skipped: 3, Mime: "image/jpeg"},
+	} {
+		msg := append(AppendFrameHeader(nil, h), "payload"...)
+		got, payload, err := ParseFramed(msg)
+		if err != nil || got != h || string(payload) != "payload" {
+			t.Fatalf("round trip %+v: got %+v payload=%q err=%v", h, got, payload, err)
+		}
+	}
+}
+
+func TestFrameHeaderTruncatesLongMime(t *testing.T) {
+	h := FrameHeader{Mime: "image/" + strings.Repeat("x", 300)}
+	got, payload, err := ParseFramed(AppendFrameHeader(nil, h))
+	if err != nil || len(got.Mime) != maxMimeLen || len(payload) != 0 {
+		t.Fatalf("got mime len %d payload=%q err=%v", len(got.Mime), payload, err)
+	}
+}
+
+func TestParseFramedRejectsGarbage(t *testing.T) {
+	valid := AppendFrameHeader(nil, FrameHeader{Mime: "image/png"})
+	for name, msg := range map[string][]byte{
+		"short":     valid[:10],
+		"version":   append([]byte{9}, valid[1:]...),
+		"truncated": valid[:len(valid)-1],
+	} {
+		if _, _, err := ParseFramed(msg); !errors.Is(err, ErrBadFrameHeader) {
+			t.Fatalf("%s: expected ErrBadFrameHeader, got %v", name, err)
+		}
+	}
+}
+
+func TestClampSkipped(t *testing.T) {
+	if ClampSkipped(-1) != 0 || ClampSkipped(5) != 5 || ClampSkipped(math.MaxInt64) != math.MaxUint32 {
+		t.Fatal("ClampSkipped out of range handling")
+	}
+}
@@ -0,0 +1,67 @@
+// Package wsproto — протокол управления WebSocket-воспроизведением (/v1/streams/{id}/ws) и Go-клиент к нему
+//
+// Бинарные сообщения сервера — кадры (JPEG), текстовые — JSON с полем type:
+// первым приходит "playback" (только при согласованном подпротоколе: сырой клиент получает одни кадры),
+// затем "ack" на каждую команду клиента
+// Клиент шлёт текстовые команды: pause, resume, seek, set_rate, stop
+package wsproto
+
+// Version текущая версия протокола; команда без "v" считается версией 1
+const Version = 1
+
+// Типы сообщений
+const (
+	TypePlayback = "playback" // сервер: фактические параметры воспроизведения
+	TypeAck      = "ack"      // сервер: результат команды
+
+	TypePause   = "pause"
+	TypeResume  = "resume"
+	TypeSeek    = "seek"     // seq или ms (время от первого кадра)
+	TypeSetRate = "set_rate" // rate — множитель скорости
+	TypeStop    = "stop"     // сервер отвечает ack и закрывает соединение (1000)
+)
+
+// Command команда клиента
+type Command struct {
+	V    int      `json:"v,omitempty"`
+	ID   string   `json:"id,omitempty"` // возвращается в ack, чтобы сопоставить ответ
+	Type string   `json:"type"`
+	Seq  *int64   `json:"seq,omitempty"`
+	Ms   *int64   `json:"ms,omitempty"`
+	Rate *float64 `json:"rate,omitempty"`
+}
+
+// State состояние воспроизведения после применения команды
+type State struct {
+	Paused     bool    `json:"paused"`
+	Seq        int64   `json:"seq"` // следующий кадр к отправке
+	Rate       float64 `json:"rate"`
+	IntervalMS float64 `json:"interval_ms"`
+	FPS        float64 `json:"fps"`
+}
+
+// Ack ответ сервера на команду; приходит после того, как команда применена (или отклонена)
+type Ack struct {
+	V     int    `json:"v"`
+	Type  string `json:"type"` // всегда "ack"
+	ID    string `json:"id,omitempty"`
+	Cmd   string `json:"cmd"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+	State *State `json:"state,omitempty"`
+}
+
+// Playback первое текстовое сообщение сессии: фактические параметры воспроизведения
+type Playback struct {
+	V                int     `json:"v"`
+	Type             string  `json:"type"` // всегда "playback"
+	StreamIntervalMS float64 `json:"stream_interval_ms"`
+	IntervalMS       float64 `json:"interval_ms"`
+	Rate             float64 `json:"rate"`
+	FPS              float64 `json:"fps"`
+}
+
+// envelope — для разбора текстового сообщения по типу
+type envelope struct {
+	Type string `json:"type"`
+}
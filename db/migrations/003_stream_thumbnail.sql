@@ -0,0 +1,5 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Постер стрима (уменьшенный первый кадр): NULL — ещё не сгенерирован, пустой — кадр не удалось декодировать
+ALTER TABLE streams ADD COLUMN IF NOT EXISTS "thumbnail" BYTEA;
+-- +goose Down
@@ -0,0 +1,8 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Счётчик кадров стрима: ведётся при дозаписи (AppendFrames), чтобы список не считал count(frames) на каждый запрос
+ALTER TABLE streams ADD COLUMN IF NOT EXISTS "frame_count" BIGINT NOT NULL DEFAULT 0;
+UPDATE streams s SET frame_count = (SELECT count(*) FROM frames f WHERE f.stream_id = s.id);
+-- Keyset-пагинация списка по (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_streams_created_id ON streams(created_at, id);
+-- +goose Down
@@ -0,0 +1,5 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Версия строки стрима для оптимистичной блокировки: растёт на каждом UpdateStream, в API отдаётся как etag
+ALTER TABLE streams ADD COLUMN IF NOT EXISTS "version" BIGINT NOT NULL DEFAULT 1;
+-- +goose Down
@@ -0,0 +1,6 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Версия кадров стрима: растёт только при правке кадров (admin delete-frames/truncate/renumber), не при UpdateStream
+-- По ней сбрасываются кэш чанков (в памяти и на диске) и ETag кадров и постера
+ALTER TABLE streams ADD COLUMN IF NOT EXISTS "frames_version" BIGINT NOT NULL DEFAULT 1;
+-- +goose Down
@@ -1,13 +1,17 @@
 const API_VERSION = "v1";
 
-const API_BASE =
-    (window.STREAM_API || `${window.location.protocol}//${window.location.hostname}:8080`) +
-    `/${API_VERSION}`;
+const API_ORIGIN =
+    window.STREAM_API || `${window.location.protocol}//${window.location.hostname}:8080`;
+const API_BASE = `${API_ORIGIN}/${API_VERSION}`;
 const WS_BASE = API_BASE.replace(/^http/, "ws");
 
 const streamList = document.getElementById("stream-list");
 const refreshBtn = document.getElementById("refresh");
+const searchInput = document.getElementById("search");
+const moreBtn = document.getElementById("more");
 const startWsBtn = document.getElementById("start-ws");
+const startMjpegBtn = document.getElementById("start-mjpeg");
+const downloadAviBtn = document.getElementById("download-avi");
 const stopBtn = document.getElementById("stop");
 const currentStreamLabel = document.getElementById("current-stream");
 const statusEl = document.getElementById("status");
@@ -26,6 +30,8 @@ const editCancelBtn = document.getElementById("edit-cancel-btn");
 let selectedStream = null;
 let wsConnection = null;
 let currentMjpegUrl = null;
+let loadedStreams = [];
+let nextPageToken = "";
 
 function normalizeStream(stream) {
     return {
@@ -34,6 +40,7 @@ function normalizeStream(stream) {
         frame_interval_ms: stream.frame_interval_ms ?? stream.frameIntervalMs,
         created_at: stream.created_at ?? stream.createdAt,
         updated_at: stream.updated_at ?? stream.updatedAt,
+        thumbnail_url: stream.thumbnail_url ?? stream.thumbnailUrl,
     };
 }
 
@@ -49,13 +56,23 @@ function revokeCurrentMjpegUrl() {
     }
 }
 
-async function fetchStreams() {
+async function fetchStreams(append = false) {
+    const params = new URLSearchParams();
+    const search = searchInput.value.trim();
+    if (search) params.set("search", search);
+    if (append && nextPageToken) params.set("page_token", nextPageToken);
     try {
-        const res = await fetch(`${API_BASE}/streams`);
+        const res = await fetch(`${API_BASE}/streams?${params}`);
         if (!res.ok) throw new Error(`HTTP ${res.status}`);
         const data = await res.json();
         const streams = (data.streams || []).map(normalizeStream);
-        renderStreams(streams);
+        loadedStreams = append ? loadedStreams.concat(streams) : streams;
+        nextPageToken = data.next_page_token ?? data.nextPageToken ?? "";
+        moreBtn.hidden = !nextPageToken;
+        // свежий etag выбранного стрима для следующей правки
+        const fresh = selectedStream && loadedStreams.find((s) => s.id === selectedStream.id);
+        if (fresh) selectedStream = fresh;
+        renderStreams(loadedStreams);
     } catch (err) {
         logStatus(`Error loading streams: ${err.message}`);
     }
@@ -65,7 +82,15 @@ function renderStreams(streams) {
     streamList.innerHTML = "";
     streams.forEach((stream) => {
         const li = document.createElement("li");
-        li.textContent = `${stream.title} (${stream.frame_count ?? "?"} frames)`;
+        if (stream.thumbnail_url) {
+            const preview = document.createElement("img");
+            preview.className = "preview";
+            preview.alt = "";
+            preview.loading = "lazy";
+            preview.src = `${API_ORIGIN}${stream.thumbnail_url}`;
+            li.appendChild(preview);
+        }
+        li.appendChild(document.createTextNode(`${stream.title} (${stream.frame_count ?? "?"} frames)`));
         li.dataset.id = stream.id;
         li.addEventListener("click", () => selectStream(stream));
         if (selectedStream && selectedStream.id === stream.id) {
@@ -88,10 +113,28 @@ function selectStream(stream) {
     });
     currentStreamLabel.textContent = `Stream: ${stream.title}`;
     startWsBtn.disabled = false;
+    startMjpegBtn.disabled = false;
+    downloadAviBtn.disabled = false;
     stopBtn.disabled = false;
     editBtn.disabled = false;
 }
 
+function startMjpeg() {
+    if (!selectedStream) return;
+    cleanupWs();
+    revokeCurrentMjpegUrl();
+    wsCanvas.hidden = true;
+    mjpegView.hidden = false;
+    mjpegView.src = `${API_BASE}/streams/${selectedStream.id}/mjpeg`;
+    logStatus("MJPEG started");
+}
+
+// Скачать весь стрим как Motion-JPEG AVI (браузер сохранит файл по Content-Disposition)
+function downloadAvi() {
+    if (!selectedStream) return;
+    window.location.href = `${API_BASE}/streams/${selectedStream.id}/export?format=avi`;
+}
+
 function startWebSocket() {
     if (!selectedStream) return;
     cleanupWs();
@@ -102,7 +145,8 @@ function startWebSocket() {
     wsCanvas.width = 640;
     wsCanvas.height = 360;
 
-    const ws = new WebSocket(`${WS_BASE}/streams/${selectedStream.id}/ws`);
+    // mjpeg.meta.v1: без подпротокола сервер шлёт только кадры — ни playback с фактической скоростью, ни метаданных
+    const ws = new WebSocket(`${WS_BASE}/streams/${selectedStream.id}/ws`, "mjpeg.meta.v1");
     ws.binaryType = "arraybuffer";
 
     ws.onopen = () => {
@@ -113,6 +157,14 @@ function startWebSocket() {
         if (typeof event.data === "string") {
             try {
                 const meta = JSON.parse(event.data);
+                if (meta.type === "playback") {
+                    logStatus(`Playback ${meta.rate}x (${meta.fps.toFixed(1)} fps)`);
+                    return;
+                }
+                if (meta.type === "ack") {
+                    logStatus(meta.ok ? `${meta.cmd}: ok` : `${meta.cmd}: ${meta.error}`);
+                    return;
+                }
                 logStatus(`Frame ${meta.sequence} (${meta.mime_type})`);
             } catch (err) {
                 logStatus(`Error parsing metadata: ${err.message}`);
@@ -168,6 +220,7 @@ function stopStreaming() {
     wsCanvas.hidden = true;
     stopBtn.disabled = true;
     startWsBtn.disabled = !selectedStream;
+    startMjpegBtn.disabled = !selectedStream;
     editBtn.disabled = !selectedStream;
     logStatus("Stopped");
 }
@@ -193,18 +246,31 @@ async function updateStream(event) {
         description: editDescription.value,
     };
 
+    // description в маске всегда: так его можно очистить
+    const mask = ["title", "description"];
     const intervalVal = String(editFrameInterval.value || "").trim();
-    if (intervalVal !== "") payload.frameIntervalMs = Number(intervalVal);
+    if (intervalVal !== "") {
+        payload.frameIntervalMs = Number(intervalVal);
+        mask.push("frameIntervalMs");
+    }
+    payload.updateMask = mask.join(",");
+    // etag: если стрим успели изменить, сервер ответит 412, а не перезапишет чужую правку
+    if (selectedStream.etag) payload.etag = selectedStream.etag;
 
     try {
         const res = await fetch(`${API_BASE}/streams/${selectedStream.id}`, {
-            method: "PUT",
+            method: "PATCH",
             headers: { "Content-Type": "application/json" },
             body: JSON.stringify(payload),
         });
+        if (res.status === 412) {
+            logStatus("Stream was changed by someone else, reloading");
+            await fetchStreams();
+            return;
+        }
         if (!res.ok) {
             const err = await res.json().catch(() => ({}));
-            throw new Error(err.error || `HTTP ${res.status}`);
+            throw new Error(err.message || err.error || `HTTP ${res.status}`);
         }
 
         const data = await res.json().catch(() => ({}));
@@ -233,8 +299,12 @@ function cancelEdit() {
     editDialog.close();
 }
 
-refreshBtn.addEventListener("click", fetchStreams);
+refreshBtn.addEventListener("click", () => fetchStreams());
+moreBtn.addEventListener("click", () => fetchStreams(true));
+searchInput.addEventListener("change", () => fetchStreams());
 startWsBtn.addEventListener("click", startWebSocket);
+startMjpegBtn.addEventListener("click", startMjpeg);
+downloadAviBtn.addEventListener("click", downloadAvi);
 stopBtn.addEventListener("click", stopStreaming);
 editBtn.addEventListener("click", openEditModal);
 editForm.addEventListener("submit", updateStream);